@@ -0,0 +1,125 @@
+package main
+
+import (
+	homeHander "gameServer/app/home/hander"
+	"gameServer/app/room/hander"
+	"gameServer/app/room/hander/logic"
+	"gameServer/app/room/hander/room"
+	"gameServer/common/db/heros"
+	"gameServer/common/db/items"
+	"gameServer/pkg/cache/ssdb"
+	"gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/pkg/redis"
+	"gameServer/service/rpc"
+	"gameServer/service/services/gate"
+	"gameServer/service/services/node"
+	"os"
+	"os/signal"
+	"syscall"
+
+	_ "gameServer/app/home/hander/inits"
+	_ "gameServer/app/room/hander/inits"
+
+	"github.com/seefan/gossdb/v2/conf"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 单进程开发模式：网关、home、room 运行在同一进程，通过进程内 rpc 直接调用，不依赖 etcd
+//
+// 各服务仍使用各自的配置文件和节点配置，启动时统一开启 rpclocal；
+// config.Get() 为 room 的配置，room 的逻辑直接读取全局配置，网关和 home 使用各自的配置创建
+func main() {
+	configPath := "./config/" // ./config/
+	logPath := "./logs/"      // ./logs/
+
+	log2.Init(log2.Config{Level: zapcore.DebugLevel, LogDir: logPath, IsDocker: false})
+
+	gateConfig := load("gate-1", "gate", configPath)
+	homeConfig := load("home-1", "home", configPath)
+	roomConfig := load("room-1", "room", configPath)
+	config.SetDefault(roomConfig)
+
+	// 获取数据源
+	cfg := &conf.Config{
+		Host:        config.Get().GameSSDBHost(),
+		Port:        config.Get().GameSSDBPort(),
+		MinPoolSize: 10,
+		MaxPoolSize: config.Get().GameSSDBMaxPoolSize(),
+		Encoding:    true, // 支持非基本数据类型
+		AutoClose:   true,
+		Password:    config.Get().GameSSDBPassword(),
+	}
+	err := ssdb.Init(cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer ssdb.Close()
+
+	// 初始化redis
+	redis.NewRedisClient(config.Get().RedisAddress())
+	items.Listening()
+	heros.Listening()
+
+	// home 节点
+	homeForward := rpc.NewForward()
+	if err = homeForward.AddModules([]interface {
+	}{
+		new(homeHander.HomeHandler),
+	}); err != nil {
+		panic(err)
+	}
+	homeNode := node.NewServerFrom(homeConfig)
+	if err = homeNode.Serve(homeForward); err != nil {
+		panic(err)
+	}
+
+	// room 节点
+	roomForward := rpc.NewForward()
+	if err = roomForward.AddModules([]interface {
+	}{
+		new(hander.HanderTest),
+		new(room.HandlerRoom),
+	}); err != nil {
+		panic(err)
+	}
+	roomNode := node.NewServerFrom(roomConfig)
+	if err = roomNode.Serve(roomForward, logic.Recover, logic.StartPool); err != nil {
+		panic(err)
+	}
+
+	// 网关最后启动，开始接入客户端时节点已经注册
+	gateServer, err := gate.NewServerFrom(gateConfig)
+	if err != nil {
+		panic(err)
+	}
+	if err = gateServer.Serve(); err != nil {
+		panic(err)
+	}
+	log2.Get().Info("dev server started", zap.Uint32("gate", gateServer.ID()), zap.Uint32("home", homeNode.ID()), zap.Uint32("room", roomNode.ID()))
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT)
+	sig := <-ch
+	log2.Get().Sugar().Infof("received signal: %+v", sig)
+
+	// 先停止接入，再关闭节点
+	for _, s := range []interface{ Close() error }{gateServer, homeNode, roomNode} {
+		if err = s.Close(); err != nil {
+			log2.Get().Error("close failed", zap.Error(err))
+		}
+	}
+	log2.Get().Info("server stop ！")
+}
+
+// load 读取单个服务的配置并开启进程内 rpc
+func load(nodeName, serviceName, configPath string) *config.Config {
+	c, err := config.New(nodeName, serviceName, configPath, serviceName, "toml")
+	if err != nil {
+		panic(err)
+	}
+	c.Set("common.rpclocal", true)
+	c.Set("common.stream.enable", false)
+	return c
+}
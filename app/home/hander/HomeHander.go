@@ -9,6 +9,7 @@ import (
 	"gameServer/common/db/items"
 	"gameServer/common/db/reward"
 	"gameServer/common/errorCode"
+	"gameServer/pkg/cache/ssdb"
 	"gameServer/pkg/logger/log2"
 	"gameServer/pkg/utils"
 	"gameServer/protobuf/pbGo"
@@ -22,9 +23,9 @@ type HomeHandler struct { //必须大写,必须使用指针
 }
 
 // 2001
-func (h *HomeHandler) GetItemInfoHandler(_ context.Context, player *common.Player, req *pbGo.ItemInfoRep, resp *pbGo.ItemInfoResp) *common.ErrorInfo {
+func (h *HomeHandler) GetItemInfoHandler(ctx context.Context, player *common.Player, req *pbGo.ItemInfoRep, resp *pbGo.ItemInfoResp) *common.ErrorInfo {
 
-	allItems, err := items.GetAllItems(player.UserId)
+	allItems, err := items.GetAllItems(ctx, player.UserId)
 	if err != nil {
 		return &common.ErrorInfo{
 			Code: errorCode.ErrorCode_GetConfigFailed,
@@ -46,7 +47,7 @@ func (h *HomeHandler) GetItemInfoHandler(_ context.Context, player *common.Playe
 }
 
 // 2002
-func (h *HomeHandler) BuyItemHandler(_ context.Context, player *common.Player, req *pbGo.BuyItemRep, resp *pbGo.BuyItemResp) *common.ErrorInfo {
+func (h *HomeHandler) BuyItemHandler(ctx context.Context, player *common.Player, req *pbGo.BuyItemRep, resp *pbGo.BuyItemResp) *common.ErrorInfo {
 	if req.Item == nil {
 		return &common.ErrorInfo{
 			Code: errorCode.ErrorCode_ReqIsNull,
@@ -60,23 +61,36 @@ func (h *HomeHandler) BuyItemHandler(_ context.Context, player *common.Player, r
 		}
 	}
 
-	verify := items.VerifyItem(player.UserId, item.Price)
+	verify := items.VerifyItem(ctx, player.UserId, item.Price)
 	if !verify {
 		log2.Get().Debug("BuyItem false ", zap.Uint64("UserId", player.UserId), zap.Uint64("ItemId", req.Item.ItemId))
 		return &common.ErrorInfo{
 			Code: errorCode.ErrorCode_ItemNotEnough,
 		}
 	}
-	flag := items.ConsumeItem(player.UserId, item.Price)
+	// 扣除和发放必须一起完成，期限只在扣除前检查
+	ctx, err := ssdb.Detach(ctx)
+	if err != nil {
+		return &common.ErrorInfo{
+			Code: errorCode.ErrorCode_Timeout,
+		}
+	}
+	flag := items.ConsumeItem(ctx, player.UserId, item.Price)
 	if !flag {
 		log2.Get().Error("BuyItem ConsumeItem false ", zap.Uint64("UserId", player.UserId), zap.Any("item.Price", item.Price))
 		return &common.ErrorInfo{
 			Code: errorCode.ErrorCode_ItemNotEnough,
 		}
 	}
-	items.RewardItem(player.UserId, map[int]int64{
+	flag = items.RewardItem(ctx, player.UserId, map[int]int64{
 		int(req.Item.ItemId): req.Item.Count,
 	})
+	if !flag {
+		log2.Get().Error("BuyItem RewardItem false ", zap.Uint64("UserId", player.UserId), zap.Uint64("ItemId", req.Item.ItemId))
+		return &common.ErrorInfo{
+			Code: errorCode.ErrorCode_DBError,
+		}
+	}
 
 	// 错误做法
 	//resp = &pbGo.ItemInfoResp{
@@ -86,7 +100,7 @@ func (h *HomeHandler) BuyItemHandler(_ context.Context, player *common.Player, r
 }
 
 // 2003
-func (h *HomeHandler) BuyHeroHandler(_ context.Context, player *common.Player, req *pbGo.BuyHeroRep, resp *pbGo.BuyHeroResp) *common.ErrorInfo {
+func (h *HomeHandler) BuyHeroHandler(ctx context.Context, player *common.Player, req *pbGo.BuyHeroRep, resp *pbGo.BuyHeroResp) *common.ErrorInfo {
 	hero := config.GetHeroConfigById(int(req.HeroId))
 	if hero == nil {
 		log2.Get().Error("GetHeroConfigById is null ", zap.Any("HeroId:", req.HeroId))
@@ -95,7 +109,7 @@ func (h *HomeHandler) BuyHeroHandler(_ context.Context, player *common.Player, r
 		}
 	}
 
-	ls := heros.GetAllUnLockCharacter(player.UserId)
+	ls := heros.GetAllUnLockCharacter(ctx, player.UserId)
 	if ls == nil {
 		log2.Get().Debug("BuyHeroRepHandler GetAllUnLockCharacter fail", zap.Uint64("UserId", player.UserId))
 		return &common.ErrorInfo{
@@ -110,14 +124,21 @@ func (h *HomeHandler) BuyHeroHandler(_ context.Context, player *common.Player, r
 		}
 	}
 
-	verify := items.VerifyItem(player.UserId, hero.Price)
+	verify := items.VerifyItem(ctx, player.UserId, hero.Price)
 	if !verify {
 		log2.Get().Debug("BuyHero false ", zap.Uint64("UserId", player.UserId), zap.Uint32("ItemId", req.HeroId))
 		return &common.ErrorInfo{
 			Code: errorCode.ErrorCode_ItemNotEnough,
 		}
 	}
-	ok := items.ConsumeItem(player.UserId, hero.Price)
+	// 扣除和解锁必须一起完成，期限只在扣除前检查
+	ctx, err := ssdb.Detach(ctx)
+	if err != nil {
+		return &common.ErrorInfo{
+			Code: errorCode.ErrorCode_Timeout,
+		}
+	}
+	ok := items.ConsumeItem(ctx, player.UserId, hero.Price)
 	if !ok {
 		log2.Get().Debug("BuyHeroRepHandler ConsumeItem fail", zap.Uint64("UserId", player.UserId))
 		return &common.ErrorInfo{
@@ -125,7 +146,7 @@ func (h *HomeHandler) BuyHeroHandler(_ context.Context, player *common.Player, r
 		}
 	}
 
-	ok = heros.UnLockCharacter(player.UserId, []int{int(req.HeroId)})
+	ok = heros.UnLockCharacter(ctx, player.UserId, []int{int(req.HeroId)})
 	if !ok {
 		log2.Get().Debug("BuyHeroRepHandler ConsumeItem fail", zap.Uint64("UserId", player.UserId))
 		return &common.ErrorInfo{
@@ -150,7 +171,7 @@ func (h *HomeHandler) BuyHeroHandler(_ context.Context, player *common.Player, r
 }
 
 // 2004
-func (h *HomeHandler) ReceiveAwardHandler(_ context.Context, player *common.Player, req *pbGo.ReceiveAwardReq, resp *pbGo.ReceiveAwardResp) *common.ErrorInfo {
+func (h *HomeHandler) ReceiveAwardHandler(ctx context.Context, player *common.Player, req *pbGo.ReceiveAwardReq, resp *pbGo.ReceiveAwardResp) *common.ErrorInfo {
 	awardConfig := config.GetReceiveAwardConfigById(int(req.Id))
 
 	if awardConfig == nil {
@@ -160,12 +181,19 @@ func (h *HomeHandler) ReceiveAwardHandler(_ context.Context, player *common.Play
 		}
 	}
 
-	rewardInfo := reward.GetAllRewardInfo(player.UserId)
+	rewardInfo := reward.GetAllRewardInfo(ctx, player.UserId)
 	timestamp := int64(0)
 	if rewardInfo != nil { //直接奖励
 		timestamp = rewardInfo[int(req.Id)].Timestamp
 	}
 
+	// 记录领取和发放必须一起完成，期限只在写入前检查
+	ctx, err := ssdb.Detach(ctx)
+	if err != nil {
+		return &common.ErrorInfo{
+			Code: errorCode.ErrorCode_Timeout,
+		}
+	}
 	infos := make([]*pbGo.ItemInfo, 0)
 	if awardConfig.RewardType == 1 || awardConfig.RewardType == 2 {
 		if timestamp > 0 {
@@ -174,14 +202,14 @@ func (h *HomeHandler) ReceiveAwardHandler(_ context.Context, player *common.Play
 			}
 		}
 
-		ok := reward.SaveRewardInfo(player.UserId, int(req.Id))
+		ok := reward.SaveRewardInfo(ctx, player.UserId, int(req.Id))
 		if !ok {
 			log2.Get().Error(" save SaveRewardInfo is false ", zap.Any("id:", req.Id))
 			return &common.ErrorInfo{
 				Code: errorCode.ErrorCode_DBError,
 			}
 		}
-		ok = items.RewardItem(player.UserId, awardConfig.RewardMap)
+		ok = items.RewardItem(ctx, player.UserId, awardConfig.RewardMap)
 		if !ok {
 			log2.Get().Error(" RewardItem is false ", zap.Any("RewardMap:", awardConfig.RewardMap))
 			return &common.ErrorInfo{
@@ -203,14 +231,14 @@ func (h *HomeHandler) ReceiveAwardHandler(_ context.Context, player *common.Play
 		}
 		isToday := utils.IsToday(rewardTimestamp)
 		if !isToday {
-			ok := reward.SaveRewardInfo(player.UserId, int(req.Id))
+			ok := reward.SaveRewardInfo(ctx, player.UserId, int(req.Id))
 			if !ok {
 				log2.Get().Error(" save RewardDb is false ", zap.Any("id:", req.Id))
 				return &common.ErrorInfo{
 					Code: errorCode.ErrorCode_DBError,
 				}
 			}
-			ok = items.RewardItem(player.UserId, awardConfig.RewardMap)
+			ok = items.RewardItem(ctx, player.UserId, awardConfig.RewardMap)
 			if !ok {
 				log2.Get().Error(" SaveRewardInfo is false ", zap.Any("RewardMap:", awardConfig.RewardMap))
 				return &common.ErrorInfo{
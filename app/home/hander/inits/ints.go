@@ -1,6 +1,7 @@
 package inits
 
 import (
+	"context"
 	"gameServer/app/home/hander/config"
 	"gameServer/app/home/hander/rank"
 	"gameServer/common/constValue"
@@ -10,6 +11,7 @@ import (
 	"gameServer/pkg/excel/reader"
 	"gameServer/pkg/logger/log2"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -58,7 +60,10 @@ func init() {
 }
 
 func RankGold() {
-	allUser, err := user.GetAllUserNoCache()
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second) // 下一次统计前结束
+	defer cancel()
+
+	allUser, err := user.GetAllUserNoCache(ctx)
 	if err != nil {
 		log2.Get().Error("RankServer GetAllUserNoCache failed ", zap.Any("err:", err))
 		return
@@ -77,7 +82,7 @@ func RankGold() {
 		return
 	}
 	for userId, _ := range allUser {
-		count, err := items.GetItemNoCache(userId, constValue.GoldItemId)
+		count, err := items.GetItemNoCache(ctx, userId, constValue.GoldItemId)
 		if err != nil {
 			log2.Get().Error("RankServer GetItemNoCache failed ", zap.Any("err:", err))
 			return
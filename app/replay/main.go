@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gameServer/app/room/hander/logic"
+	"gameServer/pkg/logger/log2"
+	"os"
+
+	_ "gameServer/app/room/hander/inits"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// 房间回放校验：按回放日志重新运行房间，对比结算结果
+//
+//	go run ./app/replay ./replays/room-1000-3-1700000000.log
+//
+// 需在包含 excels 目录的路径下运行，表格配置需与记录时一致；任一日志不一致时退出码为 1
+func main() {
+	verbose := flag.Bool("v", false, "输出结算详情")
+	logPath := flag.String("logPath", "./logs/", "日志路径")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: replay [-v] <replay.log>...")
+		os.Exit(2)
+	}
+
+	log2.Init(log2.Config{Level: zapcore.WarnLevel, LogDir: *logPath})
+
+	failed := 0
+	for _, name := range flag.Args() {
+		if !verify(name, *verbose) {
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("%d/%d mismatch\n", failed, flag.NArg())
+		os.Exit(1)
+	}
+}
+
+func verify(name string, verbose bool) bool {
+	file, err := os.Open(name)
+	if err != nil {
+		fmt.Printf("%s: %v\n", name, err)
+		return false
+	}
+	defer file.Close()
+
+	res, err := logic.Replay(file)
+	if err != nil {
+		fmt.Printf("%s: %v\n", name, err)
+		return false
+	}
+
+	switch {
+	case !res.Finished:
+		fmt.Printf("%s: room %d not finished, %d commands replayed\n", name, res.RoomId, res.Commands)
+	case res.Match():
+		fmt.Printf("%s: room %d ok, %d commands\n", name, res.RoomId, res.Commands)
+	default:
+		fmt.Printf("%s: room %d MISMATCH, %d commands\n", name, res.RoomId, res.Commands)
+	}
+	if verbose || (res.Finished && !res.Match()) {
+		fmt.Printf("  recorded: %s\n  replayed: %s\n", protojson.Format(res.Recorded), protojson.Format(res.Replayed))
+	}
+	return !res.Finished || res.Match()
+}
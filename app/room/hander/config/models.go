@@ -37,16 +37,21 @@ type Room struct {
 	ItemSum          uint32           `excel:"itemSum"`          // 藏品随机数量
 	EarlyTermination []int            `excel:"earlyTermination"` // 提取结束
 	RobotList        []uint8          `excel:"robotList"`        // 机器人id list
+
+	PrivateRoundLimit []uint8 `excel:"privateRoundLimit"` // 私人房间可选的最大局数 最小,最大，为空时不可修改
+	PrivateTimeout    []int   `excel:"privateTimeout"`    // 私人房间可选的操作超时 最小,最大，为空时不可修改
 }
 
 type Robot struct {
 	RobotType uint8 `excel:"robotType"` //机器人类型
 
 	CharacterList []uint32 `excel:"characterList"` //人物
-	JoinTimes     []int    `excel:"joinTimes"`     //加入时间
 	ThinkTimes    []int    `excel:"thinkTimes"`    //思考时间
 
 	Vibration uint8 `excel:"vibration"` //波动百分比
+
+	Strategy    string `excel:"strategy"`    //出价策略，为空时使用默认策略
+	ChoiceItems []int  `excel:"choiceItems"` //携带的道具
 }
 
 type RoomItemAllocation struct {
@@ -129,6 +134,16 @@ func GetRoomConfigByRoomId(roomId uint32) *Room {
 	return nil
 }
 
+// GetAllRoomConfig 所有房间配置
+func GetAllRoomConfig() []*Room {
+	roomInfo, ok := allStructMap["room"]
+	if !ok {
+		return nil
+	}
+	prt := roomInfo.(*[]*Room)
+	return *prt
+}
+
 func GetAbilityConfigById(id uint32) *Ability {
 	roomInfo, ok := allStructMap["ability"]
 	if !ok {
@@ -200,13 +215,12 @@ func GetAllItemConfig() []*config.Item {
 	return infos
 }
 
+// GetRoomItemAllocationConfigById 藏品分配，InitRoomItemAllocationConfig 之后才能查到
 func GetRoomItemAllocationConfigById(id int) *FRoomItemAllocation {
-	roomInfo, ok := allStructMap["roomItemAllocation"]
+	infos, ok := allStructMap["roomItemAllocation"].([]*FRoomItemAllocation)
 	if !ok {
 		return nil
 	}
-	prt := roomInfo.(*[]*FRoomItemAllocation)
-	infos := *prt
 	for _, info := range infos {
 		if info.id == id {
 			return info
@@ -254,3 +268,13 @@ func InitRoomItemAllocationConfig() {
 	}
 	allStructMap["roomItemAllocation"] = all
 }
+
+// ItemTypeRate 道具类型的权重
+func (a *FRoomItemAllocation) ItemTypeRate() map[uint32]uint32 {
+	return a.itemTypeRate
+}
+
+// NDList 藏品总价值的正态分布区间
+func (a *FRoomItemAllocation) NDList() []*ND {
+	return a.ndList
+}
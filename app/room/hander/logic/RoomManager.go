@@ -3,12 +3,18 @@ package logic
 import (
 	"context"
 	"gameServer/app/room/hander/config"
+	"gameServer/common/db/affinity"
+	"gameServer/common/errorCode"
+	config2 "gameServer/pkg/config"
 	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+	rpcxServer "gameServer/service/rpc/server"
+	"gameServer/service/services/node"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
-	"golang.org/x/exp/rand"
 )
 
 const (
@@ -18,10 +24,12 @@ const (
 )
 
 type RoomManager struct {
-	mu         sync.RWMutex
-	rooms      map[int32]*Room
-	playerRoom map[uint64]*Room //玩家房间,目前加入的房间,有可能是旧的，有可能正在玩的
-	nextRoomId int32            //自增的房间id
+	mu            sync.RWMutex
+	rooms         map[int32]*Room
+	playerRoom    map[uint64]*Room //玩家房间,目前加入的房间,有可能是旧的，有可能正在玩的
+	spectatorRoom map[uint64]*Room //玩家观战的房间
+	privateRooms  map[string]*Room //邀请码-未开始的私人房间
+	nextRoomId    int32            //自增的房间id
 
 	playerCancel map[uint64]context.CancelFunc
 
@@ -32,6 +40,10 @@ type RoomManager struct {
 	matchQueue chan *MatchRequest
 
 	roomCloseCh chan int32 // 房间回收通道,对应room的 closeChan
+
+	matchStats *matchStats // 匹配统计
+
+	pooled atomic.Bool // 已启用跨节点匹配池，见 StartPool
 }
 
 type MatchRequest struct {
@@ -41,6 +53,34 @@ type MatchRequest struct {
 
 	matchStartTime int64 //开始匹配的时间秒
 	//ver        int64 // ✅ 关键字段
+
+	partyId uint64        // 组队匹配的队伍 id
+	party   []*PlayerInfo // 组队匹配时的所有队员，player 为第一个队员，同进同出
+}
+
+// players 请求中的所有玩家
+func (req *MatchRequest) players() []*PlayerInfo {
+	if len(req.party) == 0 {
+		return []*PlayerInfo{req.player}
+	}
+	return req.party
+}
+
+// size 占用的座位数
+func (req *MatchRequest) size() int {
+	return max(len(req.party), 1)
+}
+
+// rating 组队时取队员积分的平均值
+func (req *MatchRequest) rating() int64 {
+	if len(req.party) == 0 {
+		return req.player.Rating
+	}
+	sum := int64(0)
+	for _, p := range req.party {
+		sum += p.Rating
+	}
+	return sum / int64(len(req.party))
 }
 
 func (rm *RoomManager) FindRoomByUserId(userId uint64) *Room {
@@ -54,6 +94,14 @@ func (rm *RoomManager) FindRoomByUserId(userId uint64) *Room {
 //		defer rm.mu.Unlock()
 //		rm.playerRoom[userId] = room
 //	}
+//
+// RoomCount 当前房间数，作为节点负载上报
+func (rm *RoomManager) RoomCount() int64 {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return int64(len(rm.rooms))
+}
+
 func (rm *RoomManager) delPlayerRoom(userId uint64) {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
@@ -62,16 +110,20 @@ func (rm *RoomManager) delPlayerRoom(userId uint64) {
 
 func NewRoomManager() *RoomManager {
 	rm := &RoomManager{
-		rooms:        make(map[int32]*Room),
-		playerRoom:   make(map[uint64]*Room),
-		playerCancel: make(map[uint64]context.CancelFunc),
-		playerState:  make(map[uint64]int),
-		matchQueue:   make(chan *MatchRequest, 10000), // 高并发缓冲
-		roomCloseCh:  make(chan int32, 1000),
+		rooms:         make(map[int32]*Room),
+		playerRoom:    make(map[uint64]*Room),
+		spectatorRoom: make(map[uint64]*Room),
+		privateRooms:  make(map[string]*Room),
+		playerCancel:  make(map[uint64]context.CancelFunc),
+		playerState:   make(map[uint64]int),
+		matchQueue:    make(chan *MatchRequest, 10000), // 高并发缓冲
+		roomCloseCh:   make(chan int32, 1000),
+		matchStats:    &matchStats{stats: make(map[uint32]*MatchStat)},
 	}
 	//
 	go rm.matchWorker()
 	go rm.roomRecycleWorker()
+	rpcxServer.SetLoad("rooms", rm.RoomCount)
 	return rm
 }
 
@@ -87,13 +139,39 @@ func (rm *RoomManager) roomRecycleWorker() {
 				}
 				if pRoom.roomId == room.roomId {
 					rm.delPlayerRoom(userId)
+					rm.unbind(userId, room.roomId)
+				}
+			}
+			rm.mu.Lock()
+			for userId, sRoom := range rm.spectatorRoom {
+				if sRoom == room {
+					delete(rm.spectatorRoom, userId)
 				}
 			}
+			rm.mu.Unlock()
+			if room.private != nil {
+				rm.removePrivateCode(room)
+			}
 		}
 		rm.DeleteRoom(roomId)
 	}
 }
 
+// moveToNode 房间在其它节点，通知网关把玩家的房间协议改为转发到 serverId 节点，返回 ErrorCode_Moved 由网关重新转发
+func moveToNode(player *common.Player, serverId uint32) uint16 {
+	node.BindServer([]*common.Player{player}, config2.Get().ServiceGroup(), serverId)
+	return errorCode.ErrorCode_Moved
+}
+
+// unbind 解除玩家与本节点房间的绑定
+func (rm *RoomManager) unbind(userId uint64, roomId int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	if err := affinity.Unbind(ctx, userId, config2.Get().NodeID(), roomId); err != nil {
+		log2.Get().Warn("[roomRecycleWorker] unbind room failed", zap.Uint64("userId", userId), zap.Int32("roomId", roomId), zap.Error(err))
+	}
+}
+
 func (rm *RoomManager) matchWorker() {
 
 	// 按房间类型分桶
@@ -124,8 +202,15 @@ func (rm *RoomManager) matchWorker() {
 
 		// 定时撮合
 		case <-ticker.C:
-			var players []*MatchRequest
+			now := time.Now().Unix()
 			for roomType, ms := range buckets {
+				// 已取消的请求
+				for uid, req := range ms {
+					if req.ctx.Err() != nil {
+						delete(ms, uid)
+					}
+				}
+				rm.matchStats.setQueue(roomType, len(ms))
 				if len(ms) == 0 {
 					continue
 				}
@@ -135,62 +220,15 @@ func (rm *RoomManager) matchWorker() {
 					continue
 				}
 
-				need := roomConfig.CapacityLimit
-				var robots []*MatchRequest
-				// 不够一桌就继续等
-				if len(ms) < need {
-					now := time.Now().Unix()
-					// 可能加入机器人
-					minTime := now //最早匹配时间
-					for _, info := range ms {
-						if info.matchStartTime < minTime {
-							minTime = info.matchStartTime
-						}
-					}
-					// 至少10s 匹配机器人
-					if now-minTime < rand.Int63n(5)+8 { //随机 8~12 s
-						continue
+				// 按积分分组，每组一桌
+				for _, players := range rm.matchBucket(roomConfig, ms, now) {
+					for _, info := range players {
+						delete(ms, info.player.Player.UserId)
 					}
-					lack := need - len(ms)
-					robots = make([]*MatchRequest, 0, lack)
-					for _ = range lack {
-						// 随机选择一个
-						l := len(roomConfig.RobotList)
-						if l == 0 {
-							log2.Get().Error(" len(roomConfig.RobotList)==0")
-							continue
-						}
-						robotType := roomConfig.RobotList[rand.Intn(len(roomConfig.RobotList))]
-						robotConfig := config.GetRobotById(robotType)
-						if robotConfig == nil {
-							continue
-						}
-						robot := createOneRobot(roomConfig, robotConfig)
-						robots = append(robots, robot)
-					}
-				}
-
-				players = make([]*MatchRequest, 0, need)
-				for _, req := range ms {
-					players = append(players, req)
+					rm.matchStats.record(roomType, players, now)
+					rm.createRoomWithPlayers(roomConfig, players)
 				}
-				for _, ro := range robots {
-					players = append(players, ro)
-				}
-
-				// 取一桌人
-				//players := list[:need]
-				//buckets[roomType] = list[need:]
-				if len(players) < need {
-					continue
-				}
-
-				rm.createRoomWithPlayers(roomConfig, players)
-			}
-			// 删除
-			for _, info := range players {
-				roomConfig := info.roomConfig
-				delete(buckets[roomConfig.RoomType], info.player.Player.UserId)
+				rm.matchStats.setQueue(roomType, len(ms))
 			}
 
 		}
@@ -201,33 +239,53 @@ func (rm *RoomManager) createRoomWithPlayers(cfg *config.Room, reqs []*MatchRequ
 
 	room := rm.CreateRoom(cfg)
 
+	userIds := make([]uint64, 0, len(reqs))
 	for _, req := range reqs {
 
-		uid := req.player.Player.UserId
-
 		if req.player.playerType == 0 {
-			// ❗ 再次检查 cancel（非常关键）
+			// ❗ 再次检查 cancel（非常关键），队伍中任一队员取消时整个队伍都不加入
 			select {
 			case <-req.ctx.Done():
-				rm.cleanPlayer(uid) // 玩家可能取消
+				for _, p := range req.players() {
+					rm.cleanPlayer(p.Player.UserId) // 玩家可能取消
+				}
 				continue
 			default:
 			}
 		}
-
-		err := room.Join(req.player, cfg) // 必定加成功的
-		if err != nil {
-			rm.cleanPlayer(uid)
-			continue
+		if req.partyId > 0 {
+			partyMatched(req.partyId, req.player.Player.UserId)
 		}
 
-		rm.mu.Lock()
-		rm.playerRoom[uid] = room
-		rm.playerState[uid] = StateInRoom
-		delete(rm.playerCancel, uid)
-		rm.mu.Unlock()
+		for _, p := range req.players() {
+			uid := p.Player.UserId
+			err := room.Join(p, cfg) // 必定加成功的
+			if err != nil {
+				rm.cleanPlayer(uid)
+				continue
+			}
+
+			rm.mu.Lock()
+			rm.playerRoom[uid] = room
+			rm.playerState[uid] = StateInRoom
+			delete(rm.playerCancel, uid)
+			rm.mu.Unlock()
+
+			if p.playerType == 0 {
+				userIds = append(userIds, uid)
+			}
+		}
 	}
 	log2.Get().Info("[createRoomWithPlayers]:create Room ", zap.Int32("roomId= ", room.roomId))
+
+	// 登记玩家所在房间节点，重连时网关据此路由回本节点
+	go func(roomId int32) {
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		defer cancel()
+		if err := affinity.Bind(ctx, userIds, config2.Get().NodeID(), roomId); err != nil {
+			log2.Get().Error("[createRoomWithPlayers] bind room failed", zap.Int32("roomId", roomId), zap.Error(err))
+		}
+	}(room.roomId)
 }
 
 func (rm *RoomManager) cleanPlayer(uid uint64) {
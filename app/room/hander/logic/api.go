@@ -9,6 +9,8 @@ import (
 	"gameServer/protobuf/pbGo"
 	"gameServer/protobuf/protoHandlerInit"
 	"gameServer/service/services/node"
+	"slices"
+	"sort"
 	"time"
 
 	"go.uber.org/zap"
@@ -67,13 +69,19 @@ func (room *Room) applyAbility(info *config.Ability, userId uint64, hintType uin
 	// 道具的价值过滤
 	flag = cPrice(gridInfo, flag, info.CPrice)
 
+	// 机器人使用自己的随机数，不影响玩家的提示
+	rng := room.rng
+	if p := room.playerInfos[userId]; p != nil && p.playerType > 0 {
+		rng = room.robotRng
+	}
+
 	//显示类
 	// 显示数量过滤
-	flag = showItemCount(flag, info.ShowItemCount)
+	flag = showItemCount(rng, flag, info.ShowItemCount)
 	// todo 以后会不会叠加？？
 	showPrice(gridInfo, flag, uint32(info.ShowPrice), h)
 	showGridCount(gridInfo, flag, uint32(info.ShowGridCount), h)
-	showQCA(gridInfo, flag, info.ShowQuality, info.ShowContour, info.ShowAll, userId, curRound.RoundIndex)
+	showQCA(rng, gridInfo, flag, info.ShowQuality, info.ShowContour, info.ShowAll, userId, curRound.RoundIndex)
 	h.HintType = hintType
 	h.id = info.Id
 	return h
@@ -83,7 +91,7 @@ func (room *Room) applyAbility(info *config.Ability, userId uint64, hintType uin
 // 显示数量
 // 0:所有
 // n:具体shul
-func showItemCount(flag map[int]struct{}, showSum int8) map[int]struct{} {
+func showItemCount(rng *rand.Rand, flag map[int]struct{}, showSum int8) map[int]struct{} {
 
 	if showSum == 0 {
 		return flag
@@ -100,13 +108,14 @@ func showItemCount(flag map[int]struct{}, showSum int8) map[int]struct{} {
 	for uid := range flag {
 		areaTypeList = append(areaTypeList, uid)
 	}
+	sort.Ints(areaTypeList) // 固定顺序，同一种子结果一致
 	flag = make(map[int]struct{}, showSum)
 
 	// 不重复
 	for i := int8(0); i < showSum; i++ {
 		for {
 
-			index := rand.Intn(int(maxLen))
+			index := rng.Intn(int(maxLen))
 			if _, ok := repeatFlag[index]; !ok {
 				repeatFlag[index] = struct{}{}
 				uid := areaTypeList[index]
@@ -182,7 +191,7 @@ func showGridCount(gridInfo *[]*maxRects.Placement, flag map[int]struct{}, showT
 }
 
 // 是否显示：品质,轮廓,全显示
-func showQCA(gridInfo *[]*maxRects.Placement, flag map[int]struct{}, q, c, a bool, userId uint64, curRoundIndex int8) {
+func showQCA(rng *rand.Rand, gridInfo *[]*maxRects.Placement, flag map[int]struct{}, q, c, a bool, userId uint64, curRoundIndex int8) {
 	info := *gridInfo
 	for _, p := range info {
 		if _, ok := flag[p.Uid]; !ok {
@@ -202,7 +211,7 @@ func showQCA(gridInfo *[]*maxRects.Placement, flag map[int]struct{}, q, c, a boo
 				showInfo.Quality = make(map[uint32]*maxRects.Quality)
 			}
 			for {
-				index := randIndexByXY(p.StartX, p.EndX, p.StartY, p.EndY)
+				index := randIndexByXY(rng, p.StartX, p.EndX, p.StartY, p.EndY)
 				_, ok := showInfo.Quality[index]
 				if !ok {
 					itemConfig := config.GetItemConfigById(p.Item.Id)
@@ -407,7 +416,13 @@ func cItemTypeCount(gridInfo *[]*maxRects.Placement, flag map[int]struct{}, item
 	if itemType == -1 {
 		minLen := intsets.MaxInt
 		var targetLs []int
-		for _, ls := range typeMap {
+		types := make([]int8, 0, len(typeMap))
+		for t := range typeMap {
+			types = append(types, t)
+		}
+		slices.Sort(types) // 数量相同时取类型小的
+		for _, t := range types {
+			ls := typeMap[t]
 			if len(ls) < minLen {
 				minLen = len(ls)
 				targetLs = ls
@@ -519,12 +534,47 @@ func buildChangeScreenInfo(changeScreenInfo *pbGo.ScreenInfo, gridInfo []*maxRec
 	}
 }
 
+// buildBetInfoList 一个回合所有玩家的竞拍金额和使用的道具，按玩家 id 排序
+func (room *Room) buildBetInfoList(round *Round) []*pbGo.PlayerInfo {
+	list := make([]*pbGo.PlayerInfo, 0, len(round.Op))
+	for userId, op := range round.Op {
+		betInfo := make([]*pbGo.ItemInfo, 0, 2)
+		betInfo = append(betInfo, &pbGo.ItemInfo{
+			ItemId: uint64(constValue.GoldItemId),
+			Count:  op.goldValue,
+		})
+		if op.itemId > 0 {
+			betInfo = append(betInfo, &pbGo.ItemInfo{
+				ItemId: uint64(op.itemId),
+				Count:  1,
+			})
+		}
+		var heroId int
+		if p := room.playerInfos[userId]; p != nil {
+			heroId = p.HeroId
+		}
+		list = append(list, &pbGo.PlayerInfo{
+			UserId:  userId,
+			HeroId:  uint32(heroId),
+			BetInfo: betInfo,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].UserId < list[j].UserId
+	})
+	return list
+}
+
 // 推送信息
 func (room *Room) pushRoundInfo(roomConfig *config.Room, roomSettlementInfo *pbGo.RoomSettlementInfo) {
 	curRound := room.getCurrentRound()
-	// 发送消息
-	for userId, info := range room.playerInfos {
-		if info.playerType > 0 { // 机器人
+	// 发送消息，按玩家 id 顺序计算，同一种子结果一致
+	for _, userId := range room.sortedUserIds() {
+		info := room.playerInfos[userId]
+		if info.playerType > 0 { // 机器人只获得提示，不推送
+			if (!room.replaying || room.simulating) && roomSettlementInfo == nil && info.status != PlayerStatusLeave {
+				room.observeRound(roomConfig, curRound, info)
+			}
 			continue
 		}
 		log2.Get().Info("[pushRoundInfo Start  ]", zap.Uint64("userId：", userId), zap.Int32("roomId：", room.roomId))
@@ -547,7 +597,7 @@ func (room *Room) pushRoundInfo(roomConfig *config.Room, roomSettlementInfo *pbG
 					continue
 				}
 				// 需要排重
-				entryAbilityId := uint32(roomConfig.EntryList[rand.Intn(len(roomConfig.EntryList))])
+				entryAbilityId := uint32(roomConfig.EntryList[room.rng.Intn(len(roomConfig.EntryList))])
 				//词条能力
 				entryAbility := config.GetAbilityConfigById(entryAbilityId)
 				if entryAbility == nil {
@@ -634,7 +684,10 @@ func (room *Room) pushRoundInfo(roomConfig *config.Room, roomSettlementInfo *pbG
 		endTime22 = curRound.creatTime + int64(roomConfig.Timeout)
 
 		log2.Get().Info("[pushRoundInfo End ]", zap.Uint64("userId：", userId), zap.Int32("roomId：", room.roomId))
-		node.Push(info.Player, protoHandlerInit.RoundInfoPush, &pbGo.RoundInfoPush{
+		if room.replaying {
+			continue
+		}
+		node.PushAsync(info.Player, protoHandlerInit.RoundInfoPush, &pbGo.RoundInfoPush{
 			EndTimeOut:       curRound.creatTime + int64(roomConfig.Timeout),
 			RoundIndex:       uint32(curRound.RoundIndex),
 			IsFinish:         roomSettlementInfo != nil,
@@ -645,6 +698,7 @@ func (room *Room) pushRoundInfo(roomConfig *config.Room, roomSettlementInfo *pbG
 		})
 	}
 
+	room.pushSpectators(roomConfig, roomSettlementInfo)
 }
 
 // 索引
@@ -660,8 +714,8 @@ func getXYByIndex(index uint32) (x, y uint32) {
 // 随机一个位置
 // 2~6
 // 4~5
-func randIndexByXY(sx, ex, sy, ey uint32) uint32 {
-	x := uint32(rand.Intn(int(ex-sx)+1) + int(sx)) //sx ~ ex
-	y := uint32(rand.Intn(int(ey-sy)+1) + int(sy))
+func randIndexByXY(rng *rand.Rand, sx, ex, sy, ey uint32) uint32 {
+	x := uint32(rng.Intn(int(ex-sx)+1) + int(sx)) //sx ~ ex
+	y := uint32(rng.Intn(int(ey-sy)+1) + int(sy))
 	return getIndexByXY(x, y)
 }
@@ -5,9 +5,11 @@ import (
 	"gameServer/app/room/hander/config"
 	"gameServer/common/constValue"
 	"gameServer/common/db/items"
+	"gameServer/common/db/party"
 	"gameServer/common/errorCode"
 	"gameServer/pkg/logger/log2"
 	"gameServer/protobuf/pbGo"
+	"gameServer/service/common"
 	"time"
 
 	"go.uber.org/zap"
@@ -17,18 +19,25 @@ var (
 	roomManager *RoomManager
 )
 
-func StartMatch(player *PlayerInfo, roomConfig *config.Room) {
+// StartMatch 开始匹配，在队伍中时作为队员准备，全部队员准备后一起匹配
+func StartMatch(ctx context.Context, player *PlayerInfo, roomConfig *config.Room) uint16 {
 	uid := player.Player.UserId
+	var partyId uint64
+	if partyOption().Enable {
+		var err error
+		if partyId, err = party.FindId(ctx, uid); err != nil {
+			return partyCode(err)
+		}
+	}
+	LeaveSpectate(uid)         // 匹配时退出观战
+	LeavePrivateRoom(ctx, uid) // 退出未开始的私人房间
 
 	roomManager.mu.Lock()
-	// 取消旧的
-	if cancel, ok := roomManager.playerCancel[uid]; ok {
+	// 取消旧的，队伍的匹配只能通过取消匹配或离开队伍取消
+	if cancel, ok := roomManager.playerCancel[uid]; ok && partyId == 0 {
 		cancel()
+		delete(roomManager.playerCancel, uid)
 	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-
-	roomManager.playerCancel[uid] = cancel
 	room := roomManager.playerRoom[uid]
 	//if room == nil {
 	//	log2.Get().Warn("")
@@ -36,38 +45,54 @@ func StartMatch(player *PlayerInfo, roomConfig *config.Room) {
 	if room != nil {
 		room.Leave(uid)
 	}
-	roomManager.playerState[uid] = StateMatching
-
 	roomManager.mu.Unlock()
 
-	// ✅ 丢进队列（关键变化）
-	roomManager.matchQueue <- &MatchRequest{
+	if partyId > 0 {
+		return partyReady(ctx, partyId, player, roomConfig)
+	}
+
+	req := &MatchRequest{
 		player:         player,
 		roomConfig:     roomConfig,
-		ctx:            ctx,
 		matchStartTime: time.Now().Unix(),
 	}
+	// 跨节点匹配池，写入失败时只在本节点匹配
+	if roomManager.pooled.Load() {
+		err := addPoolTicket(req)
+		if err == nil {
+			return 0
+		}
+		log2.Get().Warn("[StartMatch] add pool ticket failed, match locally", zap.Uint64("userId", uid), zap.Error(err))
+	}
+
+	roomManager.mu.Lock()
+	matchCtx, cancel := context.WithCancel(context.Background())
+	roomManager.playerCancel[uid] = cancel
+	roomManager.playerState[uid] = StateMatching
+	roomManager.mu.Unlock()
+
+	// ✅ 丢进队列（关键变化）
+	req.ctx = matchCtx
+	roomManager.matchQueue <- req
+	return 0
 }
 
-func BetOp(userId uint64, count int64) uint16 {
+func BetOp(ctx context.Context, userId uint64, count int64) uint16 {
 	room := roomManager.FindRoomByUserId(userId)
 	if room == nil {
 		return errorCode.ErrorCode_NotJoinRoom
 	}
-	roomConfig := config.GetRoomConfigByRoomId(room.roomType)
-	if roomConfig == nil {
-		return errorCode.ErrorCode_GetConfigFailed
-	}
+	roomConfig := room.roomConfig
 
 	//2. 检查是否满足条件
-	ok := items.VerifyItem(userId, map[int]int64{
+	ok := items.VerifyItem(ctx, userId, map[int]int64{
 		constValue.GoldItemId: count,
 	})
 	if !ok {
 		return errorCode.ErrorCode_ItemNotEnough
 	}
 
-	resp := room.Action(roomConfig, &Operation{
+	resp := room.Action(ctx, roomConfig, &Operation{
 		userId:    userId,
 		operation: PlayerOpBet,
 		isBet:     true,
@@ -80,7 +105,7 @@ func BetOp(userId uint64, count int64) uint16 {
 	return resp.Code
 }
 
-func UserItem(userId uint64, itemId int, count int64) (*pbGo.UseItemResp, uint16) {
+func UserItem(ctx context.Context, userId uint64, itemId int, count int64) (*pbGo.UseItemResp, uint16) {
 	if count != 1 {
 		return nil, errorCode.ErrorCode_UseItemNumErr
 	}
@@ -89,12 +114,9 @@ func UserItem(userId uint64, itemId int, count int64) (*pbGo.UseItemResp, uint16
 	if room == nil {
 		return nil, errorCode.ErrorCode_NotJoinRoom
 	}
-	roomConfig := config.GetRoomConfigByRoomId(room.roomType)
-	if roomConfig == nil {
-		return nil, errorCode.ErrorCode_GetConfigFailed
-	}
+	roomConfig := room.roomConfig
 
-	resp := room.Action(roomConfig, &Operation{
+	resp := room.Action(ctx, roomConfig, &Operation{
 		userId:    userId,
 		operation: PlayerOpUseItem,
 		itemId:    itemId,
@@ -106,7 +128,20 @@ func UserItem(userId uint64, itemId int, count int64) (*pbGo.UseItemResp, uint16
 	return resp.Data, resp.Code
 }
 
-func CancelMatch(userId uint64) {
+func CancelMatch(ctx context.Context, userId uint64) {
+	// 未开始的私人房间直接离开
+	if room := roomManager.FindRoomByUserId(userId); room != nil && room.private != nil && room.roomStatus == RoomStatusWait {
+		LeavePrivateRoom(ctx, userId)
+		return
+	}
+	if roomManager.pooled.Load() {
+		removePoolTicket(userId)
+	}
+	// 任一队员取消时整个队伍取消
+	if partyOption().Enable {
+		cancelPartyMatch(userId)
+	}
+
 	roomManager.mu.Lock()
 	defer roomManager.mu.Unlock()
 
@@ -124,3 +159,13 @@ func CancelMatch(userId uint64) {
 	}
 
 }
+
+// GetRoomSnapshot 玩家所在房间的当前状态，断线重连时使用
+func GetRoomSnapshot(ctx context.Context, player *common.Player) (*pbGo.GetRoomSnapshotResp, uint16) {
+	room := roomManager.FindRoomByUserId(player.UserId)
+	if room == nil {
+		return nil, errorCode.ErrorCode_NotJoinRoom
+	}
+	resp := room.Snapshot(ctx, player)
+	return resp.Data, resp.Code
+}
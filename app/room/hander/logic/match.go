@@ -0,0 +1,314 @@
+package logic
+
+import (
+	"expvar"
+	"gameServer/app/room/hander/config"
+	config2 "gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// MatchOption 按积分匹配，在 [room.match] 中配置
+type MatchOption struct {
+	// Window 开始匹配时可接受的积分差
+	Window int64
+	// Widen 每等待一秒积分差扩大的值
+	Widen int64
+	// MaxWindow 积分差上限
+	MaxWindow int64
+	// RobotMinWait RobotMaxWait 加入机器人前的等待时间范围，取近期真实玩家成桌等待时间的两倍
+	RobotMinWait time.Duration
+	RobotMaxWait time.Duration
+	// Rating 初始积分
+	Rating int64
+	// K elo 系数，一局中积分变化的上限
+	K float64
+}
+
+// matchOption 从配置读取，未配置时使用默认值
+var matchOption = sync.OnceValue(func() MatchOption {
+	opt := MatchOption{
+		Window:       100,
+		Widen:        25,
+		MaxWindow:    500,
+		RobotMinWait: 8 * time.Second,
+		RobotMaxWait: 20 * time.Second,
+		Rating:       1000,
+		K:            32,
+	}
+	service := config2.Get().Service()
+	if service == nil {
+		return opt
+	}
+	if v := service.GetInt64("match.window"); v > 0 {
+		opt.Window = v
+	}
+	if v := service.GetInt64("match.widen"); v > 0 {
+		opt.Widen = v
+	}
+	if v := service.GetInt64("match.maxwindow"); v > 0 {
+		opt.MaxWindow = v
+	}
+	if v := service.GetInt64("match.robotminwait"); v > 0 {
+		opt.RobotMinWait = time.Duration(v) * time.Second
+	}
+	if v := service.GetInt64("match.robotmaxwait"); v > 0 {
+		opt.RobotMaxWait = time.Duration(v) * time.Second
+	}
+	if v := service.GetInt64("match.rating"); v > 0 {
+		opt.Rating = v
+	}
+	if v := service.GetFloat64("match.k"); v > 0 {
+		opt.K = v
+	}
+	return opt
+})
+
+// window 等待 wait 秒后可接受的积分差
+func (opt MatchOption) window(wait int64) int64 {
+	return min(opt.Window+opt.Widen*wait, opt.MaxWindow)
+}
+
+// MatchStat 某类型房间的匹配统计
+type MatchStat struct {
+	Queue      int     // 匹配中的玩家数
+	Rooms      uint64  // 成桌数
+	RobotRooms uint64  // 加入了机器人的成桌数
+	Players    uint64  // 成桌的真实玩家数
+	Robots     uint64  // 加入的机器人数
+	WaitAvg    float64 // 真实玩家成桌等待时间 秒，滑动平均
+	WaitMax    int64   // 最长等待时间 秒
+	HumanWait  float64 // 没有机器人的成桌等待时间 秒，滑动平均，决定何时加入机器人
+	SpreadAvg  float64 // 同桌真实玩家的积分差，滑动平均，越小匹配质量越高
+	RobotWait  float64 // 当前加入机器人前的等待时间 秒
+}
+
+// statAlpha 滑动平均系数
+const statAlpha = 0.1
+
+func ewma(avg, value float64, first bool) float64 {
+	if first {
+		return value
+	}
+	return avg + statAlpha*(value-avg)
+}
+
+// matchStats 匹配统计，房间类型-统计
+type matchStats struct {
+	mu    sync.Mutex
+	stats map[uint32]*MatchStat
+}
+
+func (s *matchStats) get(roomType uint32) *MatchStat {
+	stat, ok := s.stats[roomType]
+	if !ok {
+		stat = &MatchStat{}
+		s.stats[roomType] = stat
+	}
+	return stat
+}
+
+// robotWait 加入机器人前的等待时间
+func (s *matchStats) robotWait(roomType uint32, opt MatchOption) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stat := s.get(roomType)
+	wait := time.Duration(2 * stat.HumanWait * float64(time.Second))
+	wait = min(max(wait, opt.RobotMinWait), opt.RobotMaxWait)
+	stat.RobotWait = wait.Seconds()
+	return wait
+}
+
+func (s *matchStats) setQueue(roomType uint32, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.get(roomType).Queue = n
+}
+
+// record 记录一桌的等待时间和积分差
+func (s *matchStats) record(roomType uint32, group []*MatchRequest, now int64) {
+	var (
+		robots     uint64
+		waitSum    int64
+		waitMax    int64
+		minR, maxR int64
+		humans     uint64
+	)
+	for _, req := range group {
+		if req.player.playerType > 0 {
+			robots++
+			continue
+		}
+		wait := now - req.matchStartTime
+		for _, p := range req.players() {
+			waitSum += wait
+			waitMax = max(waitMax, wait)
+			if humans == 0 || p.Rating < minR {
+				minR = p.Rating
+			}
+			if humans == 0 || p.Rating > maxR {
+				maxR = p.Rating
+			}
+			humans++
+		}
+	}
+	if humans == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stat := s.get(roomType)
+	first := stat.Rooms == 0
+	humanFirst := stat.Rooms == stat.RobotRooms
+	wait := float64(waitSum) / float64(humans)
+	stat.Rooms++
+	stat.Players += humans
+	stat.Robots += robots
+	stat.WaitAvg = ewma(stat.WaitAvg, wait, first)
+	stat.WaitMax = max(stat.WaitMax, waitMax)
+	stat.SpreadAvg = ewma(stat.SpreadAvg, float64(maxR-minR), first)
+	if robots > 0 {
+		stat.RobotRooms++
+	} else {
+		stat.HumanWait = ewma(stat.HumanWait, wait, humanFirst)
+	}
+}
+
+// snapshot 当前统计的副本
+func (s *matchStats) snapshot() map[uint32]MatchStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[uint32]MatchStat, len(s.stats))
+	for roomType, stat := range s.stats {
+		out[roomType] = *stat
+	}
+	return out
+}
+
+// MatchStats 各类型房间的匹配统计
+func (rm *RoomManager) MatchStats() map[uint32]MatchStat {
+	return rm.matchStats.snapshot()
+}
+
+func init() {
+	// 通过 pprof 地址的 /debug/vars 查看
+	expvar.Publish("room_match", expvar.Func(func() any {
+		if roomManager == nil {
+			return nil
+		}
+		return roomManager.MatchStats()
+	}))
+}
+
+// matchBucket 按积分撮合一种房间的匹配请求
+//
+// 从等待最久的玩家开始，选积分差在其窗口内、差值最小的玩家凑成一桌，队伍按队员积分的平均值整体加入，
+// 放不下的队伍跳过；凑不满且等待超过 robotWait 时用机器人补满，机器人积分取同桌玩家的平均值
+func (rm *RoomManager) matchBucket(roomConfig *config.Room, ms map[uint64]*MatchRequest, now int64) [][]*MatchRequest {
+	var (
+		opt       = matchOption()
+		need      = roomConfig.CapacityLimit
+		robotWait = rm.matchStats.robotWait(roomConfig.RoomType, opt)
+		reqs      = make([]*MatchRequest, 0, len(ms))
+		used      = make(map[uint64]bool, len(ms))
+		groups    [][]*MatchRequest
+	)
+	for _, req := range ms {
+		reqs = append(reqs, req)
+	}
+	sort.Slice(reqs, func(i, j int) bool {
+		if reqs[i].matchStartTime != reqs[j].matchStartTime {
+			return reqs[i].matchStartTime < reqs[j].matchStartTime
+		}
+		return reqs[i].player.Player.UserId < reqs[j].player.Player.UserId
+	})
+
+	for _, anchor := range reqs {
+		if used[anchor.player.Player.UserId] {
+			continue
+		}
+		if anchor.size() > need {
+			continue
+		}
+		wait := now - anchor.matchStartTime
+		window := opt.window(wait)
+		candidates := make([]*MatchRequest, 0)
+		for _, req := range reqs {
+			if req == anchor || used[req.player.Player.UserId] {
+				continue
+			}
+			if ratingDiff(req, anchor) <= window {
+				candidates = append(candidates, req)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return ratingDiff(candidates[i], anchor) < ratingDiff(candidates[j], anchor)
+		})
+
+		group := []*MatchRequest{anchor}
+		seats := anchor.size()
+		for _, req := range candidates {
+			if seats == need {
+				break
+			}
+			if seats+req.size() <= need {
+				group = append(group, req)
+				seats += req.size()
+			}
+		}
+		if seats < need {
+			if time.Duration(wait)*time.Second < robotWait {
+				continue
+			}
+			sum := int64(0)
+			for _, req := range group {
+				sum += req.rating() * int64(req.size())
+			}
+			robots := createMatchRobots(roomConfig, need-seats, sum/int64(seats))
+			if len(robots) < need-seats {
+				continue
+			}
+			group = append(group, robots...)
+		}
+		for _, req := range group {
+			used[req.player.Player.UserId] = true
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func ratingDiff(a, b *MatchRequest) int64 {
+	d := a.rating() - b.rating()
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// createMatchRobots 随机选择机器人配置补位
+func createMatchRobots(roomConfig *config.Room, n int, rating int64) []*MatchRequest {
+	robots := make([]*MatchRequest, 0, n)
+	for range n {
+		// 随机选择一个
+		l := len(roomConfig.RobotList)
+		if l == 0 {
+			log2.Get().Error(" len(roomConfig.RobotList)==0")
+			continue
+		}
+		robotType := roomConfig.RobotList[rand.Intn(l)]
+		robotConfig := config.GetRobotById(robotType)
+		if robotConfig == nil {
+			continue
+		}
+		robot := createOneRobot(roomConfig, robotConfig)
+		robot.player.Rating = rating
+		robots = append(robots, robot)
+	}
+	return robots
+}
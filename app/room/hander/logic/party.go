@@ -0,0 +1,403 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gameServer/app/room/hander/config"
+	"gameServer/common/db/matchPool"
+	"gameServer/common/db/party"
+	"gameServer/common/errorCode"
+	config2 "gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/protobuf/pbGo"
+	"gameServer/protobuf/protoHandlerInit"
+	"gameServer/service/common"
+	"gameServer/service/services/node"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PartyOption 组队匹配，在 [room.party] 中配置
+//
+// 队伍保存在 redis 中，队员可以在不同的房间节点上；未启用匹配池时，队伍在收到最后一个准备的节点上匹配，
+// 只有队员都在该节点上时才能取消，因此多个房间节点时需要同时启用匹配池
+type PartyOption struct {
+	Enable bool
+	// MaxSize 队伍人数上限，匹配时还不能超过房间的人数上限
+	MaxSize int
+}
+
+// partyOption 从配置读取，未配置时不启用
+var partyOption = sync.OnceValue(func() PartyOption {
+	opt := PartyOption{MaxSize: 4}
+	service := config2.Get().Service()
+	if service == nil {
+		return opt
+	}
+	opt.Enable = service.GetBool("party.enable")
+	if v := service.GetInt("party.maxsize"); v > 0 {
+		opt.MaxSize = v
+	}
+	return opt
+})
+
+var (
+	errNotInvited    = errors.New("not invited")
+	errNotLeader     = errors.New("not party leader")
+	errPartyFull     = errors.New("party full")
+	errPartyMatching = errors.New("party matching")
+	errPartyTooLarge = errors.New("party larger than room capacity")
+)
+
+// partyCode 队伍操作的错误码
+func partyCode(err error) uint16 {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, party.ErrNotFound):
+		return errorCode.ErrorCode_PartyNotFound
+	case errors.Is(err, party.ErrInParty):
+		return errorCode.ErrorCode_InParty
+	case errors.Is(err, errNotInvited):
+		return errorCode.ErrorCode_NotInvited
+	case errors.Is(err, errNotLeader):
+		return errorCode.ErrorCode_NotPartyLeader
+	case errors.Is(err, errPartyFull):
+		return errorCode.ErrorCode_PartyFull
+	case errors.Is(err, errPartyMatching):
+		return errorCode.ErrorCode_PartyMatching
+	case errors.Is(err, errPartyTooLarge):
+		return errorCode.ErrorCode_PartyTooLarge
+	}
+	log2.Get().Error("[party] redis failed", zap.Error(err))
+	return errorCode.ErrorCode_RemoteCallFailed
+}
+
+// partyInfo 队伍信息，准备的队员按加入顺序
+func partyInfo(p *party.Party) *pbGo.PartyInfo {
+	info := &pbGo.PartyInfo{
+		PartyId:      p.PartyId,
+		LeaderId:     p.LeaderId,
+		MemberIdList: p.Members,
+		RoomType:     p.RoomType,
+		ReadyIdList:  make([]uint64, 0, len(p.Ready)),
+		Matching:     p.TicketId > 0,
+	}
+	for _, userId := range p.Members {
+		if _, ok := p.Ready[userId]; ok {
+			info.ReadyIdList = append(info.ReadyIdList, userId)
+		}
+	}
+	return info
+}
+
+// pushParty 通过个人主题推送给所有队员，队员可能在其它网关上
+func pushParty(p *party.Party) {
+	msg := &pbGo.PartyPush{Party: partyInfo(p)}
+	for _, userId := range p.Members {
+		if err := node.Publish(common.UserTopic(userId), protoHandlerInit.PartyPush, msg); err != nil {
+			log2.Get().Warn("[pushParty] publish failed", zap.Uint64("partyId", p.PartyId), zap.Uint64("userId", userId), zap.Error(err))
+		}
+	}
+}
+
+// resetReady 取消所有队员的准备，返回正在匹配的票据
+func resetReady(p *party.Party) uint64 {
+	ticketId := p.TicketId
+	p.RoomType = 0
+	p.Ready = nil
+	p.TicketId = 0
+	return ticketId
+}
+
+// cancelPartyTicket 移除队伍的匹配票据，未启用匹配池时取消本节点上的匹配
+func cancelPartyTicket(ticketId uint64) {
+	if ticketId == 0 {
+		return
+	}
+	if roomManager.pooled.Load() {
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		defer cancel()
+		if _, err := matchPool.Remove(ctx, ticketId); err != nil {
+			log2.Get().Warn("[cancelPartyTicket] remove failed", zap.Uint64("ticketId", ticketId), zap.Error(err))
+		}
+		return
+	}
+	roomManager.mu.Lock()
+	defer roomManager.mu.Unlock()
+	if cancel, ok := roomManager.playerCancel[ticketId]; ok {
+		cancel()
+	}
+}
+
+// ================= 对外接口 =================
+
+// CreateParty 创建队伍，创建者为队长
+func CreateParty(ctx context.Context, userId uint64) (*pbGo.PartyInfo, uint16) {
+	if !partyOption().Enable {
+		return nil, errorCode.ErrorCode_PartyDisabled
+	}
+	p, err := party.Create(ctx, userId)
+	if err != nil {
+		return nil, partyCode(err)
+	}
+	return partyInfo(p), 0
+}
+
+// InviteParty 队长邀请玩家，被邀请的玩家收到邀请推送
+func InviteParty(ctx context.Context, userId, targetId uint64) uint16 {
+	if !partyOption().Enable {
+		return errorCode.ErrorCode_PartyDisabled
+	}
+	partyId, err := party.FindId(ctx, userId)
+	if err != nil {
+		return partyCode(err)
+	}
+	if partyId == 0 {
+		return errorCode.ErrorCode_PartyNotFound
+	}
+	_, err = party.Update(ctx, partyId, func(p *party.Party) error {
+		if p.LeaderId != userId {
+			return errNotLeader
+		}
+		if slices.Contains(p.Members, targetId) {
+			return party.ErrInParty
+		}
+		if len(p.Members) >= partyOption().MaxSize {
+			return errPartyFull
+		}
+		if !slices.Contains(p.Invites, targetId) {
+			p.Invites = append(p.Invites, targetId)
+		}
+		return nil
+	})
+	if err != nil {
+		return partyCode(err)
+	}
+	err = node.Publish(common.UserTopic(targetId), protoHandlerInit.PartyInvitePush, &pbGo.PartyInvitePush{
+		PartyId:   partyId,
+		InviterId: userId,
+	})
+	if err != nil {
+		log2.Get().Warn("[InviteParty] publish failed", zap.Uint64("partyId", partyId), zap.Uint64("targetId", targetId), zap.Error(err))
+	}
+	return 0
+}
+
+// AcceptParty 接受邀请加入队伍，队伍正在匹配时不能加入
+func AcceptParty(ctx context.Context, userId, partyId uint64) (*pbGo.PartyInfo, uint16) {
+	if !partyOption().Enable {
+		return nil, errorCode.ErrorCode_PartyDisabled
+	}
+	p, err := party.Update(ctx, partyId, func(p *party.Party) error {
+		if !slices.Contains(p.Invites, userId) {
+			return errNotInvited
+		}
+		if p.TicketId > 0 {
+			return errPartyMatching
+		}
+		if len(p.Members) >= partyOption().MaxSize {
+			return errPartyFull
+		}
+		p.Invites = slices.DeleteFunc(p.Invites, func(id uint64) bool { return id == userId })
+		p.Members = append(p.Members, userId)
+		return nil
+	})
+	if err != nil {
+		return nil, partyCode(err)
+	}
+	pushParty(p)
+	return partyInfo(p), 0
+}
+
+// LeaveParty 离开队伍，正在匹配时取消整个队伍的匹配，队长离开时由下一个队员成为队长
+func LeaveParty(ctx context.Context, userId uint64) uint16 {
+	if !partyOption().Enable {
+		return errorCode.ErrorCode_PartyDisabled
+	}
+	partyId, err := party.FindId(ctx, userId)
+	if err != nil {
+		return partyCode(err)
+	}
+	if partyId == 0 {
+		return errorCode.ErrorCode_PartyNotFound
+	}
+	var ticketId uint64
+	p, err := party.Update(ctx, partyId, func(p *party.Party) error {
+		if !slices.Contains(p.Members, userId) {
+			return party.ErrNotFound
+		}
+		ticketId = resetReady(p)
+		p.Members = slices.DeleteFunc(p.Members, func(id uint64) bool { return id == userId })
+		if p.LeaderId == userId && len(p.Members) > 0 {
+			p.LeaderId = p.Members[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return partyCode(err)
+	}
+	cancelPartyTicket(ticketId)
+	if len(p.Members) > 0 {
+		pushParty(p)
+	}
+	return 0
+}
+
+// partyReady 队员准备，全部队员以同一类型房间准备后整个队伍加入匹配；准备的房间类型变化时其他队员需要重新准备
+func partyReady(ctx context.Context, partyId uint64, player *PlayerInfo, roomConfig *config.Room) uint16 {
+	uid := player.Player.UserId
+	data, err := json.Marshal(newPlayerSnapshot(player))
+	if err != nil {
+		log2.Get().Error("[partyReady] marshal failed", zap.Uint64("userId", uid), zap.Error(err))
+		return errorCode.ErrorCode_GetConfigFailed
+	}
+
+	queued := false
+	p, err := party.Update(ctx, partyId, func(p *party.Party) error {
+		queued = false
+		if !slices.Contains(p.Members, uid) {
+			return party.ErrNotFound
+		}
+		if p.TicketId > 0 {
+			return errPartyMatching
+		}
+		if len(p.Members) > roomConfig.CapacityLimit {
+			return errPartyTooLarge
+		}
+		if p.RoomType != roomConfig.RoomType {
+			p.RoomType = roomConfig.RoomType
+			p.Ready = nil
+		}
+		if p.Ready == nil {
+			p.Ready = make(map[uint64]json.RawMessage)
+		}
+		p.Ready[uid] = data
+		for _, userId := range p.Members {
+			if _, ok := p.Ready[userId]; !ok {
+				return nil
+			}
+		}
+		p.TicketId = p.Members[0]
+		queued = true
+		return nil
+	})
+	if err != nil {
+		return partyCode(err)
+	}
+	pushParty(p)
+	if !queued {
+		return 0
+	}
+
+	req, err := partyMatchRequest(p, roomConfig)
+	if err == nil {
+		err = queueParty(req)
+	}
+	if err != nil {
+		log2.Get().Error("[partyReady] queue party failed", zap.Uint64("partyId", partyId), zap.Error(err))
+		if p, err = party.Update(ctx, partyId, func(p *party.Party) error {
+			resetReady(p)
+			return nil
+		}); err == nil {
+			pushParty(p)
+		}
+		return errorCode.ErrorCode_RemoteCallFailed
+	}
+	return 0
+}
+
+// partyMatchRequest 按所有队员的准备信息创建匹配请求
+func partyMatchRequest(p *party.Party, roomConfig *config.Room) (*MatchRequest, error) {
+	req := &MatchRequest{
+		roomConfig:     roomConfig,
+		matchStartTime: time.Now().Unix(),
+		partyId:        p.PartyId,
+		party:          make([]*PlayerInfo, 0, len(p.Members)),
+	}
+	for _, userId := range p.Members {
+		ps := &playerSnapshot{}
+		if err := json.Unmarshal(p.Ready[userId], ps); err != nil {
+			return nil, err
+		}
+		info, err := ps.playerInfo()
+		if err != nil {
+			return nil, err
+		}
+		req.party = append(req.party, info)
+	}
+	req.player = req.party[0]
+	return req, nil
+}
+
+// queueParty 队伍加入匹配池，未启用时在本节点匹配，任一队员取消时整个队伍取消
+func queueParty(req *MatchRequest) error {
+	if roomManager.pooled.Load() {
+		return addPoolTicket(req)
+	}
+	roomManager.mu.Lock()
+	for _, p := range req.party {
+		uid := p.Player.UserId
+		if old, ok := roomManager.playerCancel[uid]; ok {
+			old()
+		}
+		roomManager.playerState[uid] = StateMatching
+	}
+	// 所有队员共用一个取消函数，票据为第一个队员
+	ctx, cancel := context.WithCancel(context.Background())
+	req.ctx = ctx
+	roomManager.playerCancel[req.player.Player.UserId] = cancel
+	for _, p := range req.party[1:] {
+		roomManager.playerCancel[p.Player.UserId] = cancel
+	}
+	roomManager.mu.Unlock()
+	roomManager.matchQueue <- req
+	return nil
+}
+
+// cancelPartyMatch 队员取消匹配，取消所有队员的准备和队伍的匹配
+func cancelPartyMatch(userId uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	partyId, err := party.FindId(ctx, userId)
+	if err != nil || partyId == 0 {
+		return
+	}
+	var ticketId uint64
+	p, err := party.Update(ctx, partyId, func(p *party.Party) error {
+		ticketId = resetReady(p)
+		return nil
+	})
+	if err != nil {
+		log2.Get().Warn("[cancelPartyMatch] update failed", zap.Uint64("partyId", partyId), zap.Error(err))
+		return
+	}
+	cancelPartyTicket(ticketId)
+	pushParty(p)
+}
+
+// partyMatched 队伍成桌后清除准备状态，之后可以再次匹配；队伍期间已重新匹配时不修改
+func partyMatched(partyId, ticketId uint64) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		defer cancel()
+		p, err := party.Update(ctx, partyId, func(p *party.Party) error {
+			if p.TicketId != ticketId {
+				return errPartyMatching
+			}
+			resetReady(p)
+			return nil
+		})
+		if err != nil {
+			if !errors.Is(err, errPartyMatching) && !errors.Is(err, party.ErrNotFound) {
+				log2.Get().Warn("[partyMatched] update failed", zap.Uint64("partyId", partyId), zap.Error(err))
+			}
+			return
+		}
+		pushParty(p)
+	}()
+}
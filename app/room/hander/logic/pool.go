@@ -0,0 +1,394 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"gameServer/app/room/hander/config"
+	"gameServer/common/db/matchPool"
+	config2 "gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+	"gameServer/service/services/node"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PoolOption 跨节点匹配池，在 [room.pool] 中配置
+//
+// 所有节点的匹配请求写入 redis，持有撮合锁的节点统一撮合，每桌分配给房间最少的节点创建，
+// 再通知玩家所在网关把房间协议转发到该节点
+type PoolOption struct {
+	Enable bool
+	// Interval 撮合、领取分配和上报负载的间隔
+	Interval time.Duration
+	// NodeTimeout 超过该时间未上报负载的节点不再分配
+	NodeTimeout time.Duration
+	// TicketTTL 票据最长保留时间，玩家断线后未取消的票据到期丢弃
+	TicketTTL time.Duration
+}
+
+// poolOption 从配置读取，未配置时不启用
+var poolOption = sync.OnceValue(func() PoolOption {
+	opt := PoolOption{
+		Interval:    200 * time.Millisecond,
+		NodeTimeout: 5 * time.Second,
+		TicketTTL:   5 * time.Minute,
+	}
+	service := config2.Get().Service()
+	if service == nil {
+		return opt
+	}
+	opt.Enable = service.GetBool("pool.enable")
+	if v := service.GetInt64("pool.interval"); v > 0 {
+		opt.Interval = time.Duration(v) * time.Millisecond
+	}
+	if v := service.GetInt64("pool.nodetimeout"); v > 0 {
+		opt.NodeTimeout = time.Duration(v) * time.Second
+	}
+	if v := service.GetInt64("pool.ticketttl"); v > 0 {
+		opt.TicketTTL = time.Duration(v) * time.Second
+	}
+	return opt
+})
+
+// poolTicket 匹配池中的票据
+type poolTicket struct {
+	RoomType  uint32
+	StartTime int64 // 开始匹配的时间秒
+	Player    *playerSnapshot
+
+	PartyId uint64            // 组队匹配的队伍 id
+	Party   []*playerSnapshot // 组队匹配时的所有队员
+}
+
+// poolTable 撮合好的一桌，由分配到的节点创建房间
+type poolTable struct {
+	RoomType uint32
+	Players  []*playerSnapshot
+	Tickets  map[uint64]json.RawMessage // 玩家 id-票据，分配到的节点失效时放回匹配池
+}
+
+// matchRequest 按票据还原匹配请求
+func (ticket *poolTicket) matchRequest(roomConfig *config.Room) (*MatchRequest, error) {
+	p, err := ticket.Player.playerInfo()
+	if err != nil {
+		return nil, err
+	}
+	req := &MatchRequest{
+		player:         p,
+		roomConfig:     roomConfig,
+		ctx:            context.Background(),
+		matchStartTime: ticket.StartTime,
+		partyId:        ticket.PartyId,
+	}
+	for _, ps := range ticket.Party {
+		if p, err = ps.playerInfo(); err != nil {
+			return nil, err
+		}
+		req.party = append(req.party, p)
+	}
+	return req, nil
+}
+
+// StartPool 启用跨节点匹配池，未启用时各节点只匹配本节点的玩家
+//
+// 在 rpc 客户端就绪之后调用，已分配到本节点的桌会立即创建房间并通知网关绑定；需要在 Recover 之后，避免房间 id 重复
+func StartPool() {
+	if !poolOption().Enable {
+		return
+	}
+	roomManager.pooled.Store(true)
+	go roomManager.poolWorker()
+}
+
+// addPoolTicket 加入匹配池，组队时以第一个队员的 id 作为票据
+func addPoolTicket(req *MatchRequest) error {
+	ticket := &poolTicket{
+		RoomType:  req.roomConfig.RoomType,
+		StartTime: req.matchStartTime,
+		Player:    newPlayerSnapshot(req.player),
+		PartyId:   req.partyId,
+	}
+	for _, p := range req.party {
+		ticket.Party = append(ticket.Party, newPlayerSnapshot(p))
+	}
+	b, err := json.Marshal(ticket)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	return matchPool.Add(ctx, req.roomConfig.RoomType, req.player.Player.UserId, b)
+}
+
+// removePoolTicket 移出匹配池，已成桌时忽略
+func removePoolTicket(userId uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	if _, err := matchPool.Remove(ctx, userId); err != nil {
+		log2.Get().Warn("[removePoolTicket] remove failed", zap.Uint64("userId", userId), zap.Error(err))
+	}
+}
+
+func (rm *RoomManager) poolWorker() {
+	var (
+		opt      = poolOption()
+		serverId = config2.Get().NodeID()
+		ticker   = time.NewTicker(opt.Interval)
+	)
+	defer ticker.Stop()
+	rm.resumeAssigned(serverId)
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		if err := matchPool.Heartbeat(ctx, serverId, rm.RoomCount()); err != nil {
+			log2.Get().Warn("[poolWorker] heartbeat failed", zap.Error(err))
+		}
+		rm.takeAssigned(ctx, serverId)
+		if ok, err := matchPool.TryLock(ctx, serverId, 3*opt.Interval); err != nil {
+			log2.Get().Warn("[poolWorker] lock failed", zap.Error(err))
+		} else if ok {
+			rm.poolMatch(ctx, opt)
+		}
+		cancel()
+	}
+}
+
+// takeAssigned 创建分配给本节点的桌，房间创建后才确认，节点中途退出时由撮合节点放回匹配池
+func (rm *RoomManager) takeAssigned(ctx context.Context, serverId uint32) {
+	for range 100 {
+		b, err := matchPool.Acquire(ctx, serverId)
+		if err != nil {
+			log2.Get().Warn("[takeAssigned] acquire failed", zap.Error(err))
+			return
+		}
+		if b == nil {
+			return
+		}
+		rm.createTable(b, serverId)
+		if err = matchPool.Ack(ctx, serverId, b); err != nil {
+			log2.Get().Warn("[takeAssigned] ack failed", zap.Error(err))
+		}
+	}
+}
+
+// resumeAssigned 节点重启后继续创建已取出但未确认的桌，玩家已在恢复的房间中时说明房间已创建
+func (rm *RoomManager) resumeAssigned(serverId uint32) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	tables, err := matchPool.Unacked(ctx, serverId)
+	if err != nil {
+		log2.Get().Warn("[resumeAssigned] load failed", zap.Error(err))
+		return
+	}
+	for _, b := range tables {
+		table := &poolTable{}
+		if err = json.Unmarshal(b, table); err == nil && !rm.tableCreated(table) {
+			rm.createTable(b, serverId)
+		}
+		if err = matchPool.Ack(ctx, serverId, b); err != nil {
+			log2.Get().Warn("[resumeAssigned] ack failed", zap.Error(err))
+		}
+	}
+}
+
+// tableCreated 桌中的真实玩家是否已在本节点的房间中
+func (rm *RoomManager) tableCreated(table *poolTable) bool {
+	for userId := range table.Tickets {
+		if rm.FindRoomByUserId(userId) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// createTable 按分配的桌创建房间，并通知网关以后把房间协议转发到本节点
+func (rm *RoomManager) createTable(b []byte, serverId uint32) {
+	table := &poolTable{}
+	if err := json.Unmarshal(b, table); err != nil {
+		log2.Get().Error("[createTable] invalid table, dropped", zap.ByteString("data", b), zap.Error(err))
+		return
+	}
+	roomConfig := config.GetRoomConfigByRoomId(table.RoomType)
+	if roomConfig == nil {
+		log2.Get().Error("[createTable] room config not found", zap.Uint32("roomType", table.RoomType))
+		return
+	}
+	reqs := make([]*MatchRequest, 0, len(table.Players))
+	players := make([]*common.Player, 0, len(table.Players))
+	for _, ps := range table.Players {
+		p, err := ps.playerInfo()
+		if err != nil {
+			log2.Get().Error("[createTable] invalid player", zap.Uint64("userId", ps.Player.UserId), zap.Error(err))
+			continue
+		}
+		reqs = append(reqs, &MatchRequest{player: p, roomConfig: roomConfig, ctx: context.Background()})
+		if p.playerType == 0 {
+			players = append(players, p.Player)
+		}
+	}
+	node.BindServer(players, config2.Get().ServiceGroup(), serverId)
+	rm.createRoomWithPlayers(roomConfig, reqs)
+	for userId, raw := range table.Tickets {
+		ticket := &poolTicket{}
+		if err := json.Unmarshal(raw, ticket); err == nil && ticket.PartyId > 0 {
+			partyMatched(ticket.PartyId, userId)
+		}
+	}
+}
+
+// poolMatch 撮合匹配池中的所有票据，只在持有撮合锁的节点上执行
+func (rm *RoomManager) poolMatch(ctx context.Context, opt PoolOption) {
+	loads := rm.liveNodes(ctx, opt)
+	if len(loads) == 0 {
+		return
+	}
+	now := time.Now().Unix()
+	for _, roomConfig := range config.GetAllRoomConfig() {
+		roomType := roomConfig.RoomType
+		all, err := matchPool.LoadAll(ctx, roomType)
+		if err != nil {
+			log2.Get().Warn("[poolMatch] load tickets failed", zap.Uint32("roomType", roomType), zap.Error(err))
+			continue
+		}
+		ms := make(map[uint64]*MatchRequest, len(all))
+		expired := make([]uint64, 0)
+		for userId, b := range all {
+			ticket := &poolTicket{}
+			if err = json.Unmarshal(b, ticket); err != nil || ticket.Player == nil || ticket.Player.Player == nil {
+				log2.Get().Error("[poolMatch] invalid ticket, dropped", zap.Uint64("userId", userId), zap.ByteString("data", b), zap.Error(err))
+				expired = append(expired, userId)
+				continue
+			}
+			if time.Duration(now-ticket.StartTime)*time.Second > opt.TicketTTL {
+				expired = append(expired, userId)
+				continue
+			}
+			req, err := ticket.matchRequest(roomConfig)
+			if err != nil {
+				log2.Get().Error("[poolMatch] invalid ticket, dropped", zap.Uint64("userId", userId), zap.Error(err))
+				expired = append(expired, userId)
+				continue
+			}
+			ms[userId] = req
+		}
+		if len(expired) > 0 {
+			if _, err = matchPool.Take(ctx, roomType, expired); err != nil {
+				log2.Get().Warn("[poolMatch] drop tickets failed", zap.Uint32("roomType", roomType), zap.Error(err))
+			}
+		}
+		rm.matchStats.setQueue(roomType, len(ms))
+		if len(ms) == 0 {
+			continue
+		}
+
+		for _, group := range rm.matchBucket(roomConfig, ms, now) {
+			if !rm.assignTable(ctx, roomConfig, group, all, loads) {
+				continue
+			}
+			for _, req := range group {
+				delete(ms, req.player.Player.UserId)
+			}
+			rm.matchStats.record(roomType, group, now)
+		}
+		rm.matchStats.setQueue(roomType, len(ms))
+	}
+}
+
+// liveNodes 仍在上报负载的节点，节点 id-房间数；超时的节点未创建的桌放回匹配池
+func (rm *RoomManager) liveNodes(ctx context.Context, opt PoolOption) map[uint32]int64 {
+	nodes, err := matchPool.Nodes(ctx)
+	if err != nil {
+		log2.Get().Warn("[liveNodes] load nodes failed", zap.Error(err))
+		return nil
+	}
+	loads := make(map[uint32]int64, len(nodes))
+	for serverId, load := range nodes {
+		if time.Since(time.Unix(load.Time, 0)) <= opt.NodeTimeout {
+			loads[serverId] = load.Rooms
+		} else {
+			rm.recoverNode(ctx, serverId)
+		}
+	}
+	return loads
+}
+
+// recoverNode 把失效节点已分配但未确认的桌中的票据放回匹配池，全部放回后删除节点记录
+func (rm *RoomManager) recoverNode(ctx context.Context, serverId uint32) {
+	tables, err := matchPool.Pending(ctx, serverId)
+	if err != nil {
+		log2.Get().Warn("[recoverNode] load tables failed", zap.Uint32("serverId", serverId), zap.Error(err))
+		return
+	}
+	for _, b := range tables {
+		table := &poolTable{}
+		if err = json.Unmarshal(b, table); err != nil {
+			log2.Get().Error("[recoverNode] invalid table, dropped", zap.ByteString("data", b), zap.Error(err))
+		} else {
+			tickets := make(map[uint64][]byte, len(table.Tickets))
+			for userId, ticket := range table.Tickets {
+				tickets[userId] = ticket
+			}
+			if err = matchPool.Restore(ctx, table.RoomType, tickets); err != nil {
+				log2.Get().Error("[recoverNode] restore tickets failed", zap.Uint32("serverId", serverId), zap.Error(err))
+				return
+			}
+		}
+		if err = matchPool.Discard(ctx, serverId, b); err != nil {
+			log2.Get().Warn("[recoverNode] discard table failed", zap.Uint32("serverId", serverId), zap.Error(err))
+			return
+		}
+	}
+	if err = matchPool.RemoveNode(ctx, serverId); err != nil {
+		log2.Get().Warn("[recoverNode] remove node failed", zap.Uint32("serverId", serverId), zap.Error(err))
+		return
+	}
+	log2.Get().Info("[recoverNode] node tables returned to pool", zap.Uint32("serverId", serverId), zap.Int("tables", len(tables)))
+}
+
+// assignTable 把一桌分配给房间最少的节点，票据的移出和分配是原子的
+//
+// 期间有玩家取消或分配失败时，票据仍在匹配池中，返回是否分配成功
+func (rm *RoomManager) assignTable(ctx context.Context, roomConfig *config.Room, group []*MatchRequest, tickets map[uint64][]byte, loads map[uint32]int64) bool {
+	roomType := roomConfig.RoomType
+	table := &poolTable{
+		RoomType: roomType,
+		Players:  make([]*playerSnapshot, 0, len(group)),
+		Tickets:  make(map[uint64]json.RawMessage, len(group)),
+	}
+	userIds := make([]uint64, 0, len(group))
+	for _, req := range group {
+		if req.player.playerType == 0 {
+			userId := req.player.Player.UserId
+			userIds = append(userIds, userId)
+			table.Tickets[userId] = tickets[userId]
+		}
+		for _, p := range req.players() {
+			table.Players = append(table.Players, newPlayerSnapshot(p))
+		}
+	}
+
+	var serverId uint32
+	for id, rooms := range loads {
+		if serverId == 0 || rooms < loads[serverId] || (rooms == loads[serverId] && id < serverId) {
+			serverId = id
+		}
+	}
+	b, err := json.Marshal(table)
+	if err != nil {
+		log2.Get().Error("[assignTable] marshal failed", zap.Any("userIds", userIds), zap.Error(err))
+		return false
+	}
+	ok, err := matchPool.Assign(ctx, serverId, roomType, userIds, b)
+	if err != nil {
+		log2.Get().Error("[assignTable] assign failed", zap.Uint32("serverId", serverId), zap.Any("userIds", userIds), zap.Error(err))
+		return false
+	}
+	if !ok {
+		return false
+	}
+	loads[serverId]++ // 本轮后续的桌按新负载分配
+	log2.Get().Info("[assignTable] table assigned", zap.Uint32("roomType", roomType), zap.Uint32("serverId", serverId), zap.Any("userIds", userIds))
+	return true
+}
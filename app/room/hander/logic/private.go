@@ -0,0 +1,454 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gameServer/app/room/hander/config"
+	"gameServer/common/db/affinity"
+	"gameServer/common/db/invite"
+	"gameServer/common/errorCode"
+	config2 "gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/protobuf/pbGo"
+	"gameServer/protobuf/protoHandlerInit"
+	"gameServer/service/common"
+	"gameServer/service/services/node"
+
+	"go.uber.org/zap"
+	"golang.org/x/exp/rand"
+)
+
+// privateMinPlayer 私人房间开始的最少人数，包含机器人
+const privateMinPlayer = 2
+
+// privateCodeRetry 生成邀请码时与其它房间重复的最多重试次数
+const privateCodeRetry = 10
+
+var (
+	errRoomFull     = errors.New("room full")
+	errRoomStarted  = errors.New("room started")
+	errCodeConflict = errors.New("invite code conflict")
+)
+
+// privateRoom 私人房间信息，只在房间协程中修改
+type privateRoom struct {
+	Code      string // 邀请码，创建后不变
+	OwnerId   uint64 // 房主，房主离开时转给 id 最小的玩家
+	RobotFill bool   // 开始时用机器人补满空位
+}
+
+// ================= 私人房间命令 =================
+
+// 房主开始
+type startPrivateCmd struct {
+	UserId uint64
+	Resp   chan uint16
+
+	robots  []*PlayerInfo // 补位的机器人，记录到回放日志
+	started bool
+}
+
+// 离开未开始的私人房间
+type leavePrivateCmd struct {
+	UserId uint64
+	Resp   chan uint16
+
+	left bool
+}
+
+func (r *Room) handleStartPrivate(c *startPrivateCmd) {
+	if r.roomStatus != RoomStatusWait {
+		c.Resp <- errorCode.ErrorCode_RoomStarted
+		return
+	}
+	if c.UserId != r.private.OwnerId {
+		c.Resp <- errorCode.ErrorCode_NotRoomOwner
+		return
+	}
+	if r.private.RobotFill && !r.replaying {
+		c.robots = r.createFillRobots()
+	}
+	if len(r.playerInfos)+len(c.robots) < privateMinPlayer {
+		c.robots = nil
+		c.Resp <- errorCode.ErrorCode_PlayerNotEnough
+		return
+	}
+	for _, robot := range c.robots {
+		r.playerInfos[robot.Player.UserId] = robot
+	}
+	c.started = true
+	c.Resp <- errorCode.ErrorCode_Success
+
+	// 入场消耗在开始时扣除，与匹配的房间相同
+	r.startGame(r.roomConfig)
+}
+
+// createFillRobots 补满空位的机器人
+func (r *Room) createFillRobots() []*PlayerInfo {
+	lack := r.maxPlayer - len(r.playerInfos)
+	robotList := r.roomConfig.RobotList
+	if lack <= 0 || len(robotList) == 0 {
+		return nil
+	}
+	robots := make([]*PlayerInfo, 0, lack)
+	for range lack {
+		robotConfig := config.GetRobotById(robotList[rand.Intn(len(robotList))])
+		if robotConfig == nil {
+			continue
+		}
+		robots = append(robots, createOneRobot(r.roomConfig, robotConfig).player)
+	}
+	return robots
+}
+
+func (r *Room) handleLeavePrivate(c *leavePrivateCmd) {
+	if r.roomStatus != RoomStatusWait {
+		c.Resp <- errorCode.ErrorCode_RoomStarted
+		return
+	}
+	c.left = r.leavePrivate(c.UserId)
+	if !c.left {
+		c.Resp <- errorCode.ErrorCode_NotJoinRoom
+		return
+	}
+	c.Resp <- errorCode.ErrorCode_Success
+}
+
+// leavePrivate 玩家离开未开始的私人房间，房主离开时转给下一个玩家，没人时关闭房间
+func (r *Room) leavePrivate(userId uint64) bool {
+	if _, ok := r.playerInfos[userId]; !ok {
+		return false
+	}
+	delete(r.playerInfos, userId)
+
+	if len(r.playerInfos) == 0 {
+		r.roomStatus = RoomStatusClose
+		r.cmdChan <- &stop{}
+		r.closeChan <- r.roomId
+		return true
+	}
+	if r.private.OwnerId == userId {
+		r.private.OwnerId = r.sortedUserIds()[0]
+	}
+	r.pushPrivateRoom()
+	return true
+}
+
+// privateRoomInfo 私人房间当前信息，只能在房间协程中调用
+func (r *Room) privateRoomInfo() *pbGo.PrivateRoomInfo {
+	return &pbGo.PrivateRoomInfo{
+		Code:           r.private.Code,
+		RoomType:       r.roomType,
+		OwnerId:        r.private.OwnerId,
+		RoundLimit:     uint32(r.roomConfig.RoundLimit),
+		Timeout:        uint32(r.roomConfig.Timeout),
+		RobotFill:      r.private.RobotFill,
+		Capacity:       uint32(r.maxPlayer),
+		PlayerInfoList: r.buildPlayerInfoList(),
+	}
+}
+
+// pushPrivateRoom 成员变化时推送给房间中的玩家
+func (r *Room) pushPrivateRoom() {
+	if r.replaying {
+		return
+	}
+	receivers := make([]*common.Player, 0, len(r.playerInfos))
+	for _, p := range r.playerInfos {
+		if p.playerType == 0 {
+			receivers = append(receivers, p.Player)
+		}
+	}
+	node.BroadcastAsync(receivers, protoHandlerInit.PrivateRoomPush, &pbGo.PrivateRoomPush{
+		Room: r.privateRoomInfo(),
+	})
+}
+
+// ================= 对外接口 =================
+
+// joinPrivate 加入私人房间并登记，返回加入后的房间信息；ctx 结束时不再等待，
+// 之后仍加入成功时照常登记，玩家会收到房间推送
+func (r *Room) joinPrivate(ctx context.Context, p *PlayerInfo) (*pbGo.PrivateRoomInfo, error) {
+	if r.roomStatus != RoomStatusWait {
+		return nil, errRoomStarted
+	}
+	cmd := &JoinCmd{
+		Player:     p,
+		roomConfig: r.roomConfig,
+		Resp:       make(chan error, 1),
+	}
+	select {
+	case r.cmdChan <- cmd:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	userId := p.Player.UserId
+	select {
+	case err := <-cmd.Resp:
+		if err != nil {
+			return nil, err
+		}
+		roomManager.enterPrivateRoom(userId, r)
+		return cmd.info, nil
+	case <-ctx.Done():
+		go func() {
+			if err := <-cmd.Resp; err == nil {
+				roomManager.enterPrivateRoom(userId, r)
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// StartPrivate 房主开始私人房间
+func (r *Room) StartPrivate(ctx context.Context, userId uint64) uint16 {
+	if r.roomStatus != RoomStatusWait {
+		return errorCode.ErrorCode_RoomStarted
+	}
+	resp := make(chan uint16, 1)
+	select {
+	case r.cmdChan <- &startPrivateCmd{UserId: userId, Resp: resp}:
+	case <-ctx.Done():
+		return errorCode.ErrorCode_Timeout
+	}
+
+	select {
+	case code := <-resp:
+		return code
+	case <-ctx.Done():
+		return errorCode.ErrorCode_Timeout
+	}
+}
+
+// LeavePrivate 离开未开始的私人房间并取消登记；ctx 结束时不再等待，之后仍离开成功时照常取消登记
+func (r *Room) LeavePrivate(ctx context.Context, userId uint64) uint16 {
+	if r.roomStatus != RoomStatusWait {
+		return errorCode.ErrorCode_RoomStarted
+	}
+	resp := make(chan uint16, 1)
+	select {
+	case r.cmdChan <- &leavePrivateCmd{UserId: userId, Resp: resp}:
+	case <-ctx.Done():
+		return errorCode.ErrorCode_Timeout
+	}
+
+	select {
+	case code := <-resp:
+		if code == errorCode.ErrorCode_Success {
+			roomManager.leavePrivateRoom(userId, r)
+		}
+		return code
+	case <-ctx.Done():
+		go func() {
+			if <-resp == errorCode.ErrorCode_Success {
+				roomManager.leavePrivateRoom(userId, r)
+			}
+		}()
+		return errorCode.ErrorCode_Timeout
+	}
+}
+
+// privateRoomConfig 按自定义规则修改后的房间配置，规则为 0 时使用配置表的值，超出配置范围时返回 false
+func privateRoomConfig(roomConfig *config.Room, roundLimit uint8, timeout int) (*config.Room, bool) {
+	cfg := *roomConfig
+	if roundLimit > 0 && roundLimit != cfg.RoundLimit {
+		bound := cfg.PrivateRoundLimit
+		if len(bound) != 2 || roundLimit < bound[0] || roundLimit > bound[1] {
+			return nil, false
+		}
+		cfg.RoundLimit = roundLimit
+	}
+	if timeout > 0 && timeout != cfg.Timeout {
+		bound := cfg.PrivateTimeout
+		if len(bound) != 2 || timeout < bound[0] || timeout > bound[1] {
+			return nil, false
+		}
+		cfg.Timeout = timeout
+	}
+	return &cfg, true
+}
+
+// createPrivateRoom 创建私人房间并分配邀请码，邀请码在所有节点中唯一，其它节点据此把加入请求转到本节点
+func (rm *RoomManager) createPrivateRoom(ctx context.Context, cfg *config.Room, ownerId uint64, robotFill bool) (*Room, error) {
+	rm.mu.Lock()
+	rm.nextRoomId = rm.nextRoomId + 1
+	roomId := rm.nextRoomId
+	rm.mu.Unlock()
+
+	code := ""
+	for range privateCodeRetry {
+		c := fmt.Sprintf("%06d", rand.Intn(1000000))
+		if rm.FindPrivateRoom(c) != nil { // 本节点的邀请码已过期，房间仍在等待
+			continue
+		}
+		ok, err := invite.Reserve(ctx, c, config2.Get().NodeID(), roomId)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			code = c
+			break
+		}
+	}
+	if code == "" {
+		return nil, errCodeConflict
+	}
+
+	room := NewPrivateRoom(roomId, cfg, &privateRoom{
+		Code:      code,
+		OwnerId:   ownerId,
+		RobotFill: robotFill,
+	}, rm.roomCloseCh)
+	rm.mu.Lock()
+	rm.rooms[roomId] = room
+	rm.privateRooms[code] = room
+	rm.mu.Unlock()
+	return room, nil
+}
+
+// FindPrivateRoom 按邀请码查找未开始的私人房间
+func (rm *RoomManager) FindPrivateRoom(code string) *Room {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.privateRooms[code]
+}
+
+// removePrivateCode 房间开始或销毁后邀请码失效
+func (rm *RoomManager) removePrivateCode(room *Room) {
+	rm.mu.Lock()
+	if rm.privateRooms[room.private.Code] == room {
+		delete(rm.privateRooms, room.private.Code)
+	}
+	rm.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		defer cancel()
+		if err := invite.Release(ctx, room.private.Code, config2.Get().NodeID(), room.roomId); err != nil {
+			log2.Get().Warn("[removePrivateCode] release code failed", zap.Int32("roomId", room.roomId), zap.String("code", room.private.Code), zap.Error(err))
+		}
+	}()
+}
+
+// enterPrivateRoom 登记玩家所在的私人房间
+func (rm *RoomManager) enterPrivateRoom(userId uint64, room *Room) {
+	rm.mu.Lock()
+	rm.playerRoom[userId] = room
+	rm.playerState[userId] = StateInRoom
+	rm.mu.Unlock()
+
+	// 登记玩家所在房间节点，开始前断线重连也能回到本节点
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		defer cancel()
+		if err := affinity.Bind(ctx, []uint64{userId}, config2.Get().NodeID(), room.roomId); err != nil {
+			log2.Get().Error("[enterPrivateRoom] bind room failed", zap.Int32("roomId", room.roomId), zap.Error(err))
+		}
+	}()
+}
+
+// leavePrivateRoom 取消玩家所在私人房间的登记
+func (rm *RoomManager) leavePrivateRoom(userId uint64, room *Room) {
+	rm.mu.Lock()
+	if rm.playerRoom[userId] == room {
+		delete(rm.playerRoom, userId)
+	}
+	rm.playerState[userId] = StateIdle
+	rm.mu.Unlock()
+	go rm.unbind(userId, room.roomId)
+}
+
+// CreatePrivateRoom 创建私人房间，房主自动加入；正在匹配或在其它房间时先退出
+func CreatePrivateRoom(ctx context.Context, player *PlayerInfo, roomConfig *config.Room, roundLimit uint8, timeout int, robotFill bool) (*pbGo.PrivateRoomInfo, uint16) {
+	cfg, ok := privateRoomConfig(roomConfig, roundLimit, timeout)
+	if !ok || (robotFill && len(cfg.RobotList) == 0) {
+		return nil, errorCode.ErrorCode_RuleOutOfRange
+	}
+	uid := player.Player.UserId
+	CancelMatch(ctx, uid)
+	LeaveSpectate(uid)
+
+	room, err := roomManager.createPrivateRoom(ctx, cfg, uid, robotFill)
+	if err != nil {
+		log2.Get().Error("[CreatePrivateRoom] reserve code failed", zap.Uint64("userId", uid), zap.Error(err))
+		return nil, errorCode.ErrorCode_DBError
+	}
+	// 新房间的加入不会阻塞，不因超时留下没有房主的房间
+	info, err := room.joinPrivate(context.WithoutCancel(ctx), player)
+	if err != nil { // 新房间不会失败
+		log2.Get().Error("[CreatePrivateRoom] join failed", zap.Uint64("userId", uid), zap.Error(err))
+		return nil, errorCode.ErrorCode_RoomNotFound
+	}
+	log2.Get().Info("[CreatePrivateRoom] create room", zap.Int32("roomId", room.roomId), zap.String("code", info.Code))
+	return info, 0
+}
+
+// JoinPrivateRoom 通过邀请码加入本节点上的私人房间，其它节点的房间由 PrivateRoomType 转发
+func JoinPrivateRoom(ctx context.Context, player *PlayerInfo, code string) (*pbGo.PrivateRoomInfo, uint16) {
+	room := roomManager.FindPrivateRoom(code)
+	if room == nil {
+		return nil, errorCode.ErrorCode_RoomNotFound
+	}
+	uid := player.Player.UserId
+	if roomManager.FindRoomByUserId(uid) != room {
+		CancelMatch(ctx, uid)
+	}
+	LeaveSpectate(uid)
+
+	info, err := room.joinPrivate(ctx, player)
+	switch {
+	case errors.Is(err, errRoomFull):
+		return nil, errorCode.ErrorCode_RoomFull
+	case errors.Is(err, errRoomStarted):
+		return nil, errorCode.ErrorCode_RoomStarted
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return nil, errorCode.ErrorCode_Timeout
+	case err != nil:
+		return nil, errorCode.ErrorCode_RoomNotFound
+	}
+	return info, 0
+}
+
+// StartPrivateRoom 房主开始所在的私人房间
+func StartPrivateRoom(ctx context.Context, userId uint64) uint16 {
+	room := roomManager.FindRoomByUserId(userId)
+	if room == nil || room.private == nil {
+		return errorCode.ErrorCode_NotJoinRoom
+	}
+	code := room.StartPrivate(ctx, userId)
+	if code == errorCode.ErrorCode_Success {
+		roomManager.removePrivateCode(room)
+	}
+	return code
+}
+
+// LeavePrivateRoom 离开所在的未开始的私人房间
+func LeavePrivateRoom(ctx context.Context, userId uint64) uint16 {
+	room := roomManager.FindRoomByUserId(userId)
+	if room == nil || room.private == nil {
+		return errorCode.ErrorCode_NotJoinRoom
+	}
+	return room.LeavePrivate(ctx, userId)
+}
+
+// PrivateRoomType 邀请码对应的房间类型
+//
+// 房间在其它节点时，通知网关把玩家的房间协议改为转发到该节点，返回 ErrorCode_Moved，由网关重新转发
+func PrivateRoomType(ctx context.Context, player *common.Player, code string) (uint32, uint16) {
+	if room := roomManager.FindPrivateRoom(code); room != nil {
+		return room.roomType, 0
+	}
+	at, err := invite.Find(ctx, code)
+	if err != nil {
+		log2.Get().Warn("[PrivateRoomType] find invite code failed", zap.String("code", code), zap.Error(err))
+		return 0, errorCode.ErrorCode_DBError
+	}
+	if at == nil || at.ServerId == config2.Get().NodeID() {
+		return 0, errorCode.ErrorCode_RoomNotFound
+	}
+	LeaveSpectate(player.UserId)
+	CancelMatch(ctx, player.UserId)
+	return 0, moveToNode(player, at.ServerId)
+}
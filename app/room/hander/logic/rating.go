@@ -0,0 +1,87 @@
+package logic
+
+import (
+	"context"
+	"gameServer/common/db/rating"
+	"gameServer/pkg/logger/log2"
+	"math"
+
+	"go.uber.org/zap"
+)
+
+// LoadRating 玩家某类型房间的积分，没有记录时为初始积分；读取失败时返回错误，
+// 不能按初始积分匹配，否则结算时会覆盖玩家真实的积分
+func LoadRating(ctx context.Context, userId uint64, roomType uint32) (int64, error) {
+	value, ok, err := rating.Get(ctx, userId, roomType)
+	if err != nil {
+		log2.Get().Error("[LoadRating] get rating failed", zap.Uint64("userId", userId), zap.Uint32("roomType", roomType), zap.Error(err))
+		return 0, err
+	}
+	if !ok {
+		return matchOption().Rating, nil
+	}
+	return value, nil
+}
+
+// calcRatings 一局结束后真实玩家的新积分，玩家 id-积分
+//
+// 按名次两两比较计算 elo：获胜者利润不为负时排第一，否则排最后；离开的玩家排在未离开的玩家之后。
+// 机器人按创建时的积分参与计算，但不保存；私人房间规则不同，不计算积分
+func (r *Room) calcRatings(winnerId uint64, profit int64) map[uint64]int64 {
+	if r.private != nil || len(r.playerInfos) < 2 {
+		return nil
+	}
+	place := func(userId uint64) int {
+		switch {
+		case userId == winnerId && profit >= 0:
+			return 3
+		case userId == winnerId:
+			return 0
+		case r.playerInfos[userId].status == PlayerStatusLeave:
+			return 1
+		}
+		return 2
+	}
+
+	var (
+		k       = matchOption().K / float64(len(r.playerInfos)-1)
+		userIds = r.sortedUserIds()
+		ratings = make(map[uint64]int64, len(userIds))
+	)
+	for _, userId := range userIds {
+		p := r.playerInfos[userId]
+		if p.playerType > 0 {
+			continue
+		}
+		delta := 0.0
+		for _, otherId := range userIds {
+			if otherId == userId {
+				continue
+			}
+			score := 0.5
+			if a, b := place(userId), place(otherId); a > b {
+				score = 1
+			} else if a < b {
+				score = 0
+			}
+			expected := 1 / (1 + math.Pow(10, float64(r.playerInfos[otherId].Rating-p.Rating)/400))
+			delta += score - expected
+		}
+		ratings[userId] = p.Rating + int64(math.Round(k*delta))
+	}
+	return ratings
+}
+
+// saveRatings 保存新积分，不阻塞房间协程
+func (r *Room) saveRatings(ratings map[uint64]int64) {
+	if len(ratings) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		defer cancel()
+		if err := rating.Save(ctx, r.roomType, ratings); err != nil {
+			log2.Get().Error("[room] save rating failed", zap.Int32("roomId", r.roomId), zap.Error(err))
+		}
+	}()
+}
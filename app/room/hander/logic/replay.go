@@ -0,0 +1,425 @@
+package logic
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gameServer/app/room/hander/config"
+	config2 "gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/protobuf/pbGo"
+	"gameServer/service/common"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ReplayOption 房间回放日志，在 [room.replay] 中配置
+type ReplayOption struct {
+	// Enable 记录每个房间处理的所有命令
+	Enable bool
+	// Dir 日志目录，每个房间一个文件
+	Dir string
+}
+
+// replayOption 从配置读取，未配置时关闭
+var replayOption = sync.OnceValue(func() ReplayOption {
+	opt := ReplayOption{Dir: "./replays/"}
+	service := config2.Get().Service()
+	if service == nil {
+		return opt
+	}
+	opt.Enable = service.GetBool("replay.enable")
+	if v := service.GetString("replay.dir"); v != "" {
+		opt.Dir = v
+	}
+	return opt
+})
+
+// 回放日志条目类型
+const (
+	replayCreate  = "create"  // 创建房间
+	replayJoin    = "join"    // JoinCmd
+	replayAction  = "action"  // ActionCmd
+	replayTimeout = "timeout" // timeoutCmd
+	replayLeave   = "leave"   // LeaveCmd
+	replayStart   = "start"   // 私人房间房主开始
+	replayRestore = "restore" // 节点重启后从检查点恢复
+	replaySettle  = "settle"  // 房间结算
+)
+
+// replayEntry 回放日志的一行，按房间协程处理的顺序追加
+type replayEntry struct {
+	Time int64  `json:"time"` // 处理完成的时间戳 毫秒
+	Type string `json:"type"`
+
+	Create  *replayRoom     `json:"create,omitempty"`
+	Join    *replayPlayer   `json:"join,omitempty"`
+	Action  *replayOp       `json:"action,omitempty"`
+	Timeout int8            `json:"timeout,omitempty"` // 超时的回合
+	Leave   uint64          `json:"leave,omitempty"`   // 离开的玩家
+	Start   *replayStartOp  `json:"start,omitempty"`
+	Restore *roomSnapshot   `json:"restore,omitempty"`
+	Settle  json.RawMessage `json:"settle,omitempty"` // RoomSettlementInfo，没有人出价时为空
+}
+
+type replayRoom struct {
+	RoomId     int32
+	Seed       uint64
+	CreateTime int64
+	Config     *config.Room // 创建时的房间配置
+	Private    *privateRoom `json:",omitempty"` // 私人房间，匹配的房间为空
+}
+
+type replayPlayer struct {
+	UserId        uint64
+	PlayerType    uint8
+	RobotType     uint8
+	HeroId        int
+	ChoiceItemMap map[int]int64
+	Rating        int64
+}
+
+type replayOp struct {
+	UserId    uint64
+	Operation int8
+	GoldValue int64
+	ItemId    int
+	Consumed  bool // 使用道具时扣除是否成功
+}
+
+type replayStartOp struct {
+	UserId uint64
+	Robots []*replayPlayer // 补位的机器人
+}
+
+// replayRecorder 房间回放日志，只在房间协程中写入
+type replayRecorder struct {
+	roomId int32
+	file   *os.File
+}
+
+// newReplayRecorder 新房间的回放日志，写入种子和配置；未开启或失败时返回 nil
+func newReplayRecorder(r *Room, cfg *config.Room) *replayRecorder {
+	rec := openReplayRecorder(r.roomId, r.createTime)
+	if rec == nil {
+		return nil
+	}
+	rec.write(&replayEntry{
+		Type: replayCreate,
+		Create: &replayRoom{
+			RoomId:     r.roomId,
+			Seed:       r.seed,
+			CreateTime: r.createTime,
+			Config:     cfg,
+			Private:    r.private,
+		},
+	})
+	return rec
+}
+
+// openReplayRecorder 打开房间的回放日志，已存在时追加
+func openReplayRecorder(roomId int32, createTime int64) *replayRecorder {
+	opt := replayOption()
+	if !opt.Enable {
+		return nil
+	}
+	if err := os.MkdirAll(opt.Dir, 0755); err != nil {
+		log2.Get().Warn("[replay] create dir failed", zap.String("dir", opt.Dir), zap.Error(err))
+		return nil
+	}
+	name := filepath.Join(opt.Dir, fmt.Sprintf("room-%d-%d-%d.log", config2.Get().NodeID(), roomId, createTime))
+	file, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log2.Get().Warn("[replay] open file failed", zap.String("file", name), zap.Error(err))
+		return nil
+	}
+	return &replayRecorder{roomId: roomId, file: file}
+}
+
+func (rec *replayRecorder) write(e *replayEntry) {
+	e.Time = time.Now().UnixMilli()
+	data, err := json.Marshal(e)
+	if err != nil {
+		log2.Get().Warn("[replay] marshal failed", zap.Int32("roomId", rec.roomId), zap.String("type", e.Type), zap.Error(err))
+		return
+	}
+	if _, err = rec.file.Write(append(data, '\n')); err != nil {
+		log2.Get().Warn("[replay] write failed", zap.Int32("roomId", rec.roomId), zap.Error(err))
+	}
+}
+
+func (rec *replayRecorder) close() {
+	_ = rec.file.Close()
+}
+
+// record 记录处理完的命令，房间结束时写入结算并关闭日志
+func (r *Room) record(cmd interface{}) {
+	if r.recorder == nil {
+		return
+	}
+	e := &replayEntry{}
+	switch c := cmd.(type) {
+	case *JoinCmd:
+		e.Type = replayJoin
+		e.Join = newReplayPlayer(c.Player)
+	case *ActionCmd:
+		e.Type = replayAction
+		e.Action = &replayOp{
+			UserId:    c.UserId,
+			Operation: c.op.operation,
+			GoldValue: c.op.goldValue,
+			ItemId:    c.op.itemId,
+			Consumed:  c.consumed,
+		}
+	case *timeoutCmd:
+		e.Type = replayTimeout
+		e.Timeout = c.RoundIndex
+	case *LeaveCmd:
+		e.Type = replayLeave
+		e.Leave = c.UserId
+	case *leavePrivateCmd:
+		if c.left {
+			e.Type = replayLeave
+			e.Leave = c.UserId
+		}
+	case *startPrivateCmd:
+		if c.started {
+			e.Type = replayStart
+			e.Start = &replayStartOp{UserId: c.UserId, Robots: make([]*replayPlayer, 0, len(c.robots))}
+			for _, robot := range c.robots {
+				e.Start.Robots = append(e.Start.Robots, newReplayPlayer(robot))
+			}
+		}
+	case *resumeCmd:
+		e.Type = replayRestore
+		e.Restore = c.snapshot
+	}
+	if e.Type != "" {
+		r.recorder.write(e)
+	}
+
+	if r.roomStatus == RoomStatusClose {
+		e = &replayEntry{Type: replaySettle}
+		if r.settlement != nil {
+			e.Settle, _ = protojson.Marshal(r.settlement)
+		}
+		r.recorder.write(e)
+		r.recorder.close()
+		r.recorder = nil
+	}
+}
+
+func newReplayPlayer(p *PlayerInfo) *replayPlayer {
+	rp := &replayPlayer{
+		UserId:        p.Player.UserId,
+		PlayerType:    p.playerType,
+		HeroId:        p.HeroId,
+		ChoiceItemMap: p.ChoiceItemMap,
+		Rating:        p.Rating,
+	}
+	if p.robotConfig != nil {
+		rp.RobotType = p.robotConfig.RobotType
+	}
+	return rp
+}
+
+// playerInfo 回放中加入房间的玩家
+func (p *replayPlayer) playerInfo() *PlayerInfo {
+	info := &PlayerInfo{
+		Player:        &common.Player{UserId: p.UserId},
+		playerType:    p.PlayerType,
+		HeroId:        p.HeroId,
+		ChoiceItemMap: p.ChoiceItemMap,
+		UserItemMap:   make(map[int]int8),
+		Rating:        p.Rating,
+	}
+	if p.PlayerType > 0 {
+		info.robotConfig = config.GetRobotById(p.RobotType)
+	}
+	return info
+}
+
+// ReplayResult 回放结果
+type ReplayResult struct {
+	RoomId   int32
+	Commands int                      // 回放的命令数
+	Finished bool                     // 日志中有结算记录
+	Recorded *pbGo.RoomSettlementInfo // 日志中记录的结算
+	Replayed *pbGo.RoomSettlementInfo // 重新运行得到的结算
+}
+
+// Match 日志已结算且重新运行的结算与记录一致
+func (res *ReplayResult) Match() bool {
+	return res.Finished && proto.Equal(res.Recorded, res.Replayed)
+}
+
+// Replay 按回放日志重新运行房间主循环，不读写数据库、不推送
+//
+// 道具、能力等表格配置使用当前加载的配置，需与记录时一致
+func Replay(reader io.Reader) (*ReplayResult, error) {
+	var (
+		res     = &ReplayResult{}
+		cfg     *config.Room
+		rp      *replayer
+		scanner = bufio.NewScanner(reader)
+	)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	defer func() {
+		if rp != nil {
+			rp.stop()
+		}
+	}()
+
+	for line := 1; scanner.Scan(); line++ {
+		e := &replayEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if rp == nil && e.Type != replayCreate && e.Type != replayRestore {
+			return nil, fmt.Errorf("line %d: %s before create", line, e.Type)
+		}
+
+		switch e.Type {
+		case replayCreate:
+			cfg = e.Create.Config
+			res.RoomId = e.Create.RoomId
+			r := newRoom(e.Create.RoomId, e.Create.Seed, cfg, make(chan int32, 1))
+			r.private = e.Create.Private
+			rp = startReplayer(r, cfg)
+			continue
+		case replayRestore:
+			if rp != nil {
+				rp.stop()
+			}
+			if cfg == nil {
+				cfg = e.Restore.Config
+			}
+			if cfg == nil {
+				if cfg = config.GetRoomConfigByRoomId(e.Restore.RoomType); cfg == nil {
+					return nil, fmt.Errorf("line %d: room config not found: %d", line, e.Restore.RoomType)
+				}
+			}
+			r, err := roomFromSnapshot(e.Restore, cfg, make(chan int32, 1))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			res.RoomId = r.roomId
+			rp = startReplayer(r, cfg)
+			rp.send(&resumeCmd{roomConfig: cfg})
+		case replayJoin:
+			rp.join(e.Join)
+		case replayAction:
+			rp.action(e.Action)
+		case replayTimeout:
+			rp.send(&timeoutCmd{RoundIndex: e.Timeout, roomConfig: cfg})
+		case replayLeave:
+			rp.send(&LeaveCmd{UserId: e.Leave})
+		case replayStart:
+			rp.start(e.Start)
+		case replaySettle:
+			res.Finished = true
+			if len(e.Settle) > 0 {
+				res.Recorded = &pbGo.RoomSettlementInfo{}
+				if err := protojson.Unmarshal(e.Settle, res.Recorded); err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+			}
+			continue
+		default:
+			return nil, fmt.Errorf("line %d: unknown type %s", line, e.Type)
+		}
+		res.Commands++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if rp == nil {
+		return nil, errors.New("empty replay")
+	}
+	rp.stop()
+	res.Replayed = rp.room.settlement
+	rp = nil
+	return res, nil
+}
+
+// replayer 回放中的房间，逐条发送命令，有响应的命令等待处理完成
+type replayer struct {
+	room *Room
+	cfg  *config.Room
+	done chan struct{}
+}
+
+func startReplayer(r *Room, cfg *config.Room) *replayer {
+	r.replaying = true
+	rp := &replayer{room: r, cfg: cfg, done: make(chan struct{})}
+	go func() {
+		r.loop()
+		close(rp.done)
+	}()
+	return rp
+}
+
+// send 房间已结束时丢弃
+func (rp *replayer) send(cmd interface{}) {
+	select {
+	case rp.room.cmdChan <- cmd:
+	case <-rp.done:
+	}
+}
+
+func (rp *replayer) join(p *replayPlayer) {
+	resp := make(chan error, 1)
+	rp.send(&JoinCmd{Player: p.playerInfo(), roomConfig: rp.cfg, Resp: resp})
+	select {
+	case <-resp:
+	case <-rp.done:
+	}
+}
+
+func (rp *replayer) action(op *replayOp) {
+	resp := make(chan *ActionResp, 1)
+	rp.send(&ActionCmd{
+		UserId:     op.UserId,
+		roomConfig: rp.cfg,
+		op: &Operation{
+			userId:    op.UserId,
+			goldValue: op.GoldValue,
+			operation: op.Operation,
+			isBet:     op.Operation == PlayerOpBet,
+			itemId:    op.ItemId,
+		},
+		Resp:     resp,
+		consumed: op.Consumed,
+	})
+	select {
+	case <-resp:
+	case <-rp.done:
+	}
+}
+
+func (rp *replayer) start(op *replayStartOp) {
+	robots := make([]*PlayerInfo, 0, len(op.Robots))
+	for _, p := range op.Robots {
+		robots = append(robots, p.playerInfo())
+	}
+	resp := make(chan uint16, 1)
+	rp.send(&startPrivateCmd{UserId: op.UserId, Resp: resp, robots: robots})
+	select {
+	case <-resp:
+	case <-rp.done:
+	}
+}
+
+// stop 结束主循环并等待退出
+func (rp *replayer) stop() {
+	rp.send(&stop{})
+	<-rp.done
+}
@@ -1,11 +1,18 @@
 package logic
 
 import (
+	"context"
 	"gameServer/app/room/hander/config"
+	"gameServer/app/room/hander/strategy"
+	"gameServer/common/constValue"
+	"gameServer/common/errorCode"
 	"gameServer/pkg/random/snowflake"
+	"gameServer/protobuf/pbGo"
 	"gameServer/service/common"
-	"math/rand"
+	"slices"
 	"time"
+
+	"golang.org/x/exp/rand"
 )
 
 var (
@@ -43,13 +50,23 @@ func (rm *RoomManager) createRobots(n int, roomConfig *config.Room) []*MatchRequ
 }
 
 func createOneRobot(roomConfig *config.Room, robotConfig *config.Robot) *MatchRequest {
+	heroId := 3005
+	if l := len(robotConfig.CharacterList); l > 0 {
+		heroId = int(robotConfig.CharacterList[rand.Intn(l)])
+	}
+	choiceItemMap := make(map[int]int64, len(robotConfig.ChoiceItems))
+	for _, itemId := range robotConfig.ChoiceItems {
+		choiceItemMap[itemId] = 1
+	}
 	robot := &PlayerInfo{
 		Player: &common.Player{
 			UserId: robotGenerate.Generate(),
 		},
-		HeroId:      3005,
-		playerType:  1,
-		robotConfig: robotConfig,
+		HeroId:        heroId,
+		ChoiceItemMap: choiceItemMap,
+		UserItemMap:   make(map[int]int8),
+		playerType:    1,
+		robotConfig:   robotConfig,
 	}
 
 	return &MatchRequest{
@@ -58,54 +75,223 @@ func createOneRobot(roomConfig *config.Room, robotConfig *config.Robot) *MatchRe
 	}
 }
 
-// 游戏开始时调用
-func (r *Room) startRobotActions(roomConfig *config.Room) {
-	ticker := time.NewTicker(2 * time.Second)
-	for {
-		select {
-		// 定时撮合
-		case <-ticker.C:
-			if r == nil {
-				ticker.Stop()
-				return
-			}
+// 机器人思考结束，在房间协程中按策略决定本回合的操作
+type robotCmd struct {
+	UserId     uint64
+	RoundIndex int8
+	roomConfig *config.Room
+}
 
-			if r.roomStatus == RoomStatusClose {
-				ticker.Stop()
-				return
-			}
+// robotStrategy 机器人配置选择的策略
+func robotStrategy(robotConfig *config.Robot) strategy.Strategy {
+	if robotConfig == nil {
+		return strategy.New(strategy.DefaultName, strategy.Option{})
+	}
+	return strategy.New(robotConfig.Strategy, strategy.Option{Vibration: robotConfig.Vibration})
+}
 
-			round := r.getCurrentRound()
-			for _, p := range r.playerInfos {
-				if p.playerType > 0 {
-					timeLen := rand.Intn(p.robotConfig.ThinkTimes[1]-p.robotConfig.ThinkTimes[0]) + p.robotConfig.ThinkTimes[1] //10~15
-					r.robotRoutine(p, roomConfig, round, int64(timeLen))
-				}
-			}
+// robotThinkTime 机器人的思考时间，在配置的范围内随机，短于回合超时
+func (r *Room) robotThinkTime(robot *PlayerInfo, roomConfig *config.Room) time.Duration {
+	think := 0
+	if robot.robotConfig != nil && len(robot.robotConfig.ThinkTimes) >= 2 {
+		lower, upper := robot.robotConfig.ThinkTimes[0], robot.robotConfig.ThinkTimes[1]
+		think = lower
+		if upper > lower {
+			think += r.robotRng.Intn(upper - lower + 1)
 		}
 	}
+	if roomConfig.Timeout > 1 {
+		think = min(think, roomConfig.Timeout-1)
+	}
+	return time.Duration(max(think, 0)) * time.Second
+}
 
+// scheduleRobots 本回合开始后为未弃权的机器人计时，思考结束后再决定操作
+func (r *Room) scheduleRobots(roomConfig *config.Room, round *Round) {
+	if r.replaying { // 机器人的操作由回放日志给出
+		return
+	}
+	for _, userId := range r.sortedUserIds() {
+		p := r.playerInfos[userId]
+		if p.playerType == 0 || p.status == PlayerStatusLeave {
+			continue
+		}
+		if round.Op[userId] != nil { // 已弃权
+			continue
+		}
+		cmd := &robotCmd{UserId: userId, RoundIndex: round.RoundIndex, roomConfig: roomConfig}
+		round.robotTimers = append(round.robotTimers, time.AfterFunc(r.robotThinkTime(p, roomConfig), func() {
+			r.cmdChan <- cmd
+		}))
+	}
 }
 
-func (r *Room) robotRoutine(robot *PlayerInfo, roomConfig *config.Room, curRound *Round, timeLen int64) {
-	op := curRound.Op[robot.Player.UserId]
-	if op != nil {
+// stopRobots 停止本回合还未触发的机器人计时
+func (round *Round) stopRobots() {
+	for _, timer := range round.robotTimers {
+		timer.Stop()
+	}
+	round.robotTimers = nil
+}
+
+func (r *Room) handleRobot(c *robotCmd) {
+	if r.roomStatus != RoomStatusPlay {
+		return
+	}
+	round := r.getCurrentRound()
+	if round.RoundIndex != c.RoundIndex { // 旧回合的计时
+		return
+	}
+	robot := r.playerInfos[c.UserId]
+	if robot == nil || robot.status == PlayerStatusLeave {
+		return
+	}
+	if op := round.Op[c.UserId]; op != nil && (op.isBet || op.operation == PlayerOpAbstain) {
 		return
 	}
 
-	//log2.Get().Info("robot robot start", zap.Int("startTime", startTime))
-	now := time.Now().Unix()
-	if curRound.creatTime+timeLen > now { //未来 > now
+	s := robotStrategy(robot.robotConfig)
+	d := s.Decide(r.robotView(robot, false), r.robotRng)
+	if d.UseItem > 0 {
+		resp := r.robotAction(c, &Operation{userId: c.UserId, operation: PlayerOpUseItem, itemId: d.UseItem})
+		if resp.Code == errorCode.ErrorCode_Success {
+			r.observeItem(robot, d.UseItem, resp.Data)
+		}
+		d = s.Decide(r.robotView(robot, true), r.robotRng)
+		if d.UseItem > 0 { // 每回合最多使用一个道具
+			d = strategy.Decision{Abstain: true}
+		}
+	}
+
+	op := &Operation{userId: c.UserId, operation: PlayerOpAbstain}
+	if !d.Abstain && d.Bet > 0 {
+		op.operation = PlayerOpBet
+		op.isBet = true
+		op.goldValue = d.Bet
+	}
+	r.robotAction(c, op)
+}
+
+// robotAction 在房间协程中执行机器人的操作，和玩家的操作一样记录到回放日志
+func (r *Room) robotAction(c *robotCmd, op *Operation) *ActionResp {
+	cmd := &ActionCmd{
+		ctx:        context.Background(),
+		UserId:     op.userId,
+		roomConfig: c.roomConfig,
+		op:         op,
+		Resp:       make(chan *ActionResp, 1),
+	}
+	r.handleAction(cmd)
+	r.record(cmd)
+	return <-cmd.Resp
+}
+
+// robotView 机器人能看到的信息，只包含该机器人已获得的提示
+func (r *Room) robotView(robot *PlayerInfo, itemUsed bool) *strategy.View {
+	userId := robot.Player.UserId
+	round := r.getCurrentRound()
+	view := &strategy.View{
+		RoundIndex: round.RoundIndex,
+		RoundLimit: r.maxRound,
+		ItemSum:    int(r.roomConfig.ItemSum),
+		Candidates: roomCandidates(r.roomConfig),
+		Totals:     robot.robotTotals,
+		ItemUsed:   itemUsed,
+	}
+	for _, p := range *r.gridInfo {
+		showInfo := p.ShowInfoMap[userId]
+		if showInfo == nil {
+			continue
+		}
+		item := strategy.Item{}
+		if showInfo.All > 0 {
+			item.ItemId = p.Item.Id
+		}
+		if showInfo.All > 0 || showInfo.Contour > 0 {
+			item.Area = p.Item.Length * p.Item.Width
+		}
+		for _, q := range showInfo.Quality {
+			item.Quality = q.QualityType
+			break
+		}
+		if item != (strategy.Item{}) {
+			view.Items = append(view.Items, item)
+		}
+	}
+	if lastRound := r.getLastRound(); lastRound != nil {
+		for uid, op := range lastRound.Op {
+			if uid != userId && op.goldValue > 0 {
+				view.LastBids = append(view.LastBids, op.goldValue)
+			}
+		}
+	}
+	for itemId := range robot.ChoiceItemMap {
+		if itemId > 0 && robot.UserItemMap[itemId] == 0 {
+			view.ChoiceItems = append(view.ChoiceItems, itemId)
+		}
+	}
+	slices.Sort(view.ChoiceItems)
+	return view
+}
+
+// roomCandidates 房间可能出现的藏品
+func roomCandidates(roomConfig *config.Room) []strategy.Candidate {
+	candidates := make([]strategy.Candidate, 0, len(roomConfig.ItemList))
+	for _, itemId := range roomConfig.ItemList {
+		item := config.GetItemConfigById(itemId)
+		if item == nil || len(item.Area) != 2 {
+			continue
+		}
+		candidates = append(candidates, strategy.Candidate{
+			ItemId:  itemId,
+			Price:   item.Price[constValue.GoldItemId],
+			Area:    item.Area[0] * item.Area[1],
+			Quality: item.Quality,
+		})
+	}
+	return candidates
+}
+
+// observeRound 机器人获得本回合的词条和人物提示，与玩家的规则相同，使用机器人随机数
+func (r *Room) observeRound(roomConfig *config.Room, round *Round, robot *PlayerInfo) {
+	userId := robot.Player.UserId
+	abilityList := make([]*config.Ability, 0)
+	if slices.Contains(roomConfig.EntryRound, round.RoundIndex) && len(roomConfig.EntryList) > 0 {
+		entryAbilityId := uint32(roomConfig.EntryList[r.robotRng.Intn(len(roomConfig.EntryList))])
+		if ability := config.GetAbilityConfigById(entryAbilityId); ability != nil {
+			abilityList = append(abilityList, ability)
+		}
+	}
+	if hero := config.GetHeroConfigById(robot.HeroId); hero != nil {
+		for _, id := range hero.RAsMap[round.RoundIndex] {
+			if ability := config.GetAbilityConfigById(id); ability != nil {
+				abilityList = append(abilityList, ability)
+			}
+		}
+	}
+	for _, ability := range abilityList {
+		hint := r.applyAbility(ability, userId, 0)
+		robot.observeHint(ability, uint64(hint.Value))
+	}
+}
+
+// observeItem 机器人使用道具后获得的提示
+func (r *Room) observeItem(robot *PlayerInfo, itemId int, data *pbGo.UseItemResp) {
+	item := config.GetItemConfigById(itemId)
+	if item == nil || data == nil || len(data.HintList) == 0 {
 		return
 	}
+	if ability := config.GetAbilityConfigById(item.Ability); ability != nil {
+		robot.observeHint(ability, data.HintList[0].Value)
+	}
+}
 
-	robotOp := &Operation{
-		userId:    robot.Player.UserId,
-		goldValue: rand.Int63n(20000),
-		isBet:     true,
-		operation: PlayerOpBet,
+// observeHint 记录提示给出的总价值，平均价值和格子数量的提示不记录
+func (p *PlayerInfo) observeHint(ability *config.Ability, value uint64) {
+	if ability.ShowPrice != 1 || ability.ShowGridCount != 0 {
+		return
 	}
-	r.Action(roomConfig, robotOp)
+	p.robotTotals = append(p.robotTotals, int64(value/10000))
 }
 
 //func (r *Room) robotOp(roomConfig *config.Room) {
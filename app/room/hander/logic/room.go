@@ -1,10 +1,12 @@
 package logic
 
 import (
+	"context"
 	"fmt"
 	"gameServer/app/room/hander/config"
 	"gameServer/app/room/hander/maxRects"
 	"gameServer/common/constValue"
+	"gameServer/common/db/affinity"
 	"gameServer/common/db/items"
 	"gameServer/common/errorCode"
 	"gameServer/pkg/logger/log2"
@@ -12,7 +14,9 @@ import (
 	"gameServer/protobuf/protoHandlerInit"
 	"gameServer/service/common"
 	"gameServer/service/services/node"
+	"slices"
 	"sort"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -32,6 +36,9 @@ const (
 	PlayerOpBet     = 1 //发牌
 	PlayerOpAbstain = 2 //弃权
 	PlayerOpUseItem = 3 //使用道具
+
+	// 房间循环内数据库操作的最长时间，避免后端卡住时阻塞整个房间
+	dbTimeout = 3 * time.Second
 )
 
 var (
@@ -61,8 +68,10 @@ type PlayerInfo struct {
 	HeroId        int            //选择的人物
 	ChoiceItemMap map[int]int64  //选择的道具
 	UserItemMap   map[int]int8   //使用了道具
+	Rating        int64          //该类型房间的积分，匹配时读取
 
 	robotConfig *config.Robot //机器人配置
+	robotTotals []int64       //机器人获得的总价值提示，不保存到检查点
 
 	status uint8 // 0: 正常，1：已经离开
 
@@ -79,8 +88,11 @@ type Round struct {
 	Op    map[uint64]*Operation //所有玩家的操作,userid -本局玩家的操作
 	timer *time.Timer
 
+	robotTimers []*time.Timer // 机器人的思考计时
+
 	//timeOut   int64 //超时时间戳
 	creatTime int64 //创建时间戳 秒
+	deadline  int64 //超时时间戳 秒，启动计时时设置
 }
 
 // ================= 房间 =================
@@ -97,12 +109,28 @@ type Room struct {
 	roundList []*Round
 	maxRound  uint8
 
+	roomConfig *config.Room // 房间配置，私人房间为修改规则后的副本，创建后不再变化
+	private    *privateRoom // 私人房间信息，匹配创建的房间为 nil
+
 	// 命令
 	cmdChan chan interface{}
 
 	closeChan chan int32 //通知管理器删除
 
 	gridInfo *[]*maxRects.Placement //物品信息
+
+	seed     uint64          // 随机种子，记录到回放日志
+	rngSrc   *rand.PCGSource // 房间随机数状态，保存到检查点
+	rng      *rand.Rand      // 房间随机数，只在房间协程中使用
+	robotRng *rand.Rand      // 机器人随机数，只在房间协程中使用，不影响玩家看到的提示
+
+	recorder   *replayRecorder          // 回放日志，未开启时为 nil
+	replaying  bool                     // 回放中，不读写数据库、不推送、不启动计时
+	simulating bool                     // 离线模拟，同时处于回放中；机器人当作玩家，照常获得提示
+	settlement *pbGo.RoomSettlementInfo // 结算结果
+
+	spectators     map[uint64]*common.Player // 观战者，只在房间协程中使用
+	spectatorCount atomic.Int32              // 观战人数，供房间列表读取
 }
 
 // ================= 命令 =================
@@ -112,14 +140,19 @@ type JoinCmd struct {
 	Player     *PlayerInfo
 	roomConfig *config.Room
 	Resp       chan error
+
+	info *pbGo.PrivateRoomInfo // 私人房间加入后的信息
 }
 
 // 玩家操作
 type ActionCmd struct {
+	ctx        context.Context // 请求期限，数据库操作受其限制
 	UserId     uint64
 	roomConfig *config.Room
 	op         *Operation       //操作
 	Resp       chan *ActionResp //成功,0,其他为错误码
+
+	consumed bool // 使用道具时扣除是否成功，记录到回放日志
 }
 
 type ActionResp struct {
@@ -137,6 +170,23 @@ type timeoutCmd struct {
 type stop struct {
 }
 
+// 节点重启后继续当前回合
+type resumeCmd struct {
+	roomConfig *config.Room
+	snapshot   *roomSnapshot // 恢复所用的状态，记录到回放日志
+}
+
+// 获取房间快照，断线重连
+type snapshotCmd struct {
+	Player *common.Player // 重连后的玩家信息，替换房间内旧的
+	Resp   chan *SnapshotResp
+}
+
+type SnapshotResp struct {
+	Code uint16
+	Data *pbGo.GetRoomSnapshotResp
+}
+
 // 离开操作
 type LeaveCmd struct {
 	UserId uint64
@@ -145,16 +195,58 @@ type LeaveCmd struct {
 // ================= 创建房间 =================
 
 func NewRoom(roomId int32, cfg *config.Room, closeChan chan int32) *Room {
+	return NewPrivateRoom(roomId, cfg, nil, closeChan)
+}
+
+// NewPrivateRoom 创建私人房间，private 为 nil 时与匹配的房间相同
+func NewPrivateRoom(roomId int32, cfg *config.Room, private *privateRoom, closeChan chan int32) *Room {
+	r := newRoom(roomId, rand.Uint64(), cfg, closeChan)
+	r.private = private
+	r.recorder = newReplayRecorder(r, cfg)
+
+	go r.loop()
+
+	return r
+}
+
+// newRoom 按种子创建房间，同一种子和配置得到相同的藏品，不启动主循环
+func newRoom(roomId int32, seed uint64, cfg *config.Room, closeChan chan int32) *Room {
+	rngSrc := &rand.PCGSource{}
+	rngSrc.Seed(seed)
+	rng := rand.New(rngSrc)
 
 	// 分配藏品信息
 	sum := int(cfg.ItemSum)
 	itemList := make([]int, 0, sum)
 	maxLen := len(cfg.ItemList)
 	for i := 0; i < sum; i++ {
-		index := rand.Intn(maxLen)
+		index := rng.Intn(maxLen)
 		itemList = append(itemList, cfg.ItemList[index])
 	}
 
+	placements := packItems(itemList)
+
+	r := &Room{
+		roomId:      roomId,
+		roomType:    cfg.RoomType,
+		maxPlayer:   cfg.CapacityLimit,
+		createTime:  time.Now().Unix(),
+		playerInfos: make(PlayerInfos),
+		cmdChan:     make(chan interface{}, 100),
+		maxRound:    cfg.RoundLimit,
+		roomConfig:  cfg,
+		gridInfo:    &placements,
+		closeChan:   closeChan,
+		seed:        seed,
+		rngSrc:      rngSrc,
+		rng:         rng,
+		robotRng:    rand.New(rand.NewSource(seed + 1)),
+	}
+	return r
+}
+
+// packItems 按藏品 id 生成摆放位置，面积大的先放
+func packItems(itemList []int) []*maxRects.Placement {
 	gridInfo := make([]maxRects.Item, 0, len(itemList))
 	for _, itemId := range itemList {
 		info := config.GetItemConfigById(itemId)
@@ -172,30 +264,13 @@ func NewRoom(roomId int32, cfg *config.Room, closeChan chan int32) *Room {
 			Width:  info.Area[1],
 		})
 	}
-	//
 
 	// 优化排序
 	sort.Slice(gridInfo, func(i, j int) bool {
 		return gridInfo[i].Length*gridInfo[i].Width >
 			gridInfo[j].Length*gridInfo[j].Width
 	})
-	placements := maxRects.Pack(gridInfo)
-
-	r := &Room{
-		roomId:      roomId,
-		roomType:    cfg.RoomType,
-		maxPlayer:   cfg.CapacityLimit,
-		createTime:  time.Now().Unix(),
-		playerInfos: make(PlayerInfos),
-		cmdChan:     make(chan interface{}, 100),
-		maxRound:    cfg.RoundLimit,
-		gridInfo:    &placements,
-		closeChan:   closeChan,
-	}
-
-	go r.loop()
-
-	return r
+	return maxRects.Pack(gridInfo)
 }
 
 // ================= 主循环 =================
@@ -214,6 +289,28 @@ func (r *Room) loop() {
 
 		case *LeaveCmd:
 			r.handleLeave(c)
+
+		case *resumeCmd:
+			r.handleResume(c)
+
+		case *snapshotCmd:
+			r.handleSnapshot(c)
+
+		case *startPrivateCmd:
+			r.handleStartPrivate(c)
+
+		case *leavePrivateCmd:
+			r.handleLeavePrivate(c)
+
+		case *spectateCmd:
+			r.handleSpectate(c)
+
+		case *unspectateCmd:
+			r.handleUnspectate(c)
+
+		case *robotCmd:
+			r.handleRobot(c)
+
 		case *stop:
 			// 清空 channel（防止 goroutine 泄漏）
 			for {
@@ -224,18 +321,32 @@ func (r *Room) loop() {
 				}
 			}
 		}
+		r.record(cmd)
 	}
 }
 
 // ================= Join =================
 
 func (r *Room) handleJoin(c *JoinCmd) {
-	if len(r.playerInfos) >= r.maxPlayer {
-		c.Resp <- fmt.Errorf("room full")
+	if r.private != nil && r.roomStatus != RoomStatusWait {
+		c.Resp <- errRoomStarted
+		return
+	}
+	_, rejoin := r.playerInfos[c.Player.Player.UserId]
+	if !rejoin && len(r.playerInfos) >= r.maxPlayer {
+		c.Resp <- errRoomFull
 		return
 	}
 
 	r.playerInfos[c.Player.Player.UserId] = c.Player
+
+	// 私人房间由房主开始
+	if r.private != nil {
+		c.info = r.privateRoomInfo()
+		c.Resp <- nil
+		r.pushPrivateRoom()
+		return
+	}
 	c.Resp <- nil
 
 	//r.broadcast("player_join", c.Player.Player.UserId)
@@ -286,6 +397,7 @@ func (r *Room) handleAction(c *ActionCmd) {
 		round.Op[userId].goldValue = c.op.goldValue
 
 		//广播所有
+		receivers := make([]*common.Player, 0, len(r.playerInfos))
 		for _, one := range r.playerInfos {
 			if one.Player.UserId == userId { // 排除本人
 				continue
@@ -297,7 +409,11 @@ func (r *Room) handleAction(c *ActionCmd) {
 				continue
 			}
 
-			node.Push(one.Player, protoHandlerInit.BetPush, &pbGo.BetResp{
+			receivers = append(receivers, one.Player)
+		}
+		receivers = append(receivers, r.spectatorPlayers()...) // 观战者
+		if !r.replaying {
+			node.BroadcastAsync(receivers, protoHandlerInit.BetPush, &pbGo.BetResp{
 				PlayerInfo: &pbGo.PlayerInfo{
 					UserId: userId,
 				},
@@ -377,10 +493,18 @@ func (r *Room) handleAction(c *ActionCmd) {
 		// 广播
 		resp.Data = data
 
-		//消耗
-		ok = items.ConsumeItem(userId, map[int]int64{
-			itemId: 1,
-		})
+		//消耗，回放时使用记录的结果
+		if r.playerInfos[userId].playerType > 0 { // 机器人的道具不扣除
+			ok = true
+			c.consumed = true
+		} else if r.replaying {
+			ok = c.consumed
+		} else {
+			ok = items.ConsumeItem(c.ctx, userId, map[int]int64{
+				itemId: 1,
+			})
+			c.consumed = ok
+		}
 		if !ok {
 			resp.Code = errorCode.ErrorCode_GetConfigFailed
 			c.Resp <- resp
@@ -388,6 +512,10 @@ func (r *Room) handleAction(c *ActionCmd) {
 		}
 		r.playerInfos[userId].UserItemMap[c.op.itemId] = round.RoundIndex
 		round.Op[userId].itemId = c.op.itemId
+		// 回合中扣除的道具立即保存，节点在下一回合前退出时也能退还
+		if r.playerInfos[userId].playerType == 0 && !r.replaying {
+			r.checkpoint(c.roomConfig)
+		}
 	}
 	c.Resp <- resp
 
@@ -443,6 +571,10 @@ func (r *Room) handleTimeout(c *timeoutCmd) {
 
 // ================= 离开 =================
 func (r *Room) handleLeave(c *LeaveCmd) {
+	if r.private != nil && r.roomStatus == RoomStatusWait {
+		r.leavePrivate(c.UserId)
+		return
+	}
 	//if r.roomStatus == RoomStatusWait { //不可能
 	//	delete(r.playerInfos, c.UserId)
 	//} else { // 已经游戏中
@@ -455,6 +587,64 @@ func (r *Room) handleLeave(c *LeaveCmd) {
 	}
 }
 
+// ================= 快照 =================
+func (r *Room) handleSnapshot(c *snapshotCmd) {
+	userId := c.Player.UserId
+	info, ok := r.playerInfos[userId]
+	if !ok || r.roomStatus != RoomStatusPlay || len(r.roundList) == 0 {
+		c.Resp <- &SnapshotResp{Code: errorCode.ErrorCode_NotJoinRoom}
+		return
+	}
+	// 重连后网关可能变化，后续推送使用新的玩家信息
+	info.Player = c.Player
+
+	screenInfo := &pbGo.ScreenInfo{}
+	buildChangeScreenInfo(screenInfo, *r.gridInfo, userId)
+	c.Resp <- &SnapshotResp{Data: r.buildSnapshotResp(screenInfo)}
+}
+
+// buildSnapshotResp 房间当前状态，物品信息由调用方按玩家或观战者生成
+func (r *Room) buildSnapshotResp(screenInfo *pbGo.ScreenInfo) *pbGo.GetRoomSnapshotResp {
+	round := r.getCurrentRound()
+	data := &pbGo.GetRoomSnapshotResp{
+		RoomType:       r.roomType,
+		RoundIndex:     uint32(round.RoundIndex),
+		EndTimeOut:     round.deadline,
+		ScreenInfo:     screenInfo,
+		PlayerInfoList: r.buildPlayerInfoList(),
+		BetHistory:     make([]*pbGo.RoundBetInfo, 0, len(r.roundList)-1),
+	}
+
+	// 已结束回合的竞拍，本回合只给出已竞拍的玩家
+	for _, one := range r.roundList[:len(r.roundList)-1] {
+		data.BetHistory = append(data.BetHistory, &pbGo.RoundBetInfo{
+			RoundIndex:     uint32(one.RoundIndex),
+			PlayerInfoList: r.buildBetInfoList(one),
+		})
+	}
+	for uid, op := range round.Op {
+		if op.isBet {
+			data.BetUserIdList = append(data.BetUserIdList, uid)
+		}
+	}
+	sort.Slice(data.BetUserIdList, func(i, j int) bool {
+		return data.BetUserIdList[i] < data.BetUserIdList[j]
+	})
+	return data
+}
+
+// buildPlayerInfoList 房间内所有玩家及选择的人物，按玩家 id 排序
+func (r *Room) buildPlayerInfoList() []*pbGo.PlayerInfo {
+	list := make([]*pbGo.PlayerInfo, 0, len(r.playerInfos))
+	for _, userId := range r.sortedUserIds() {
+		list = append(list, &pbGo.PlayerInfo{
+			UserId: userId,
+			HeroId: uint32(r.playerInfos[userId].HeroId),
+		})
+	}
+	return list
+}
+
 // ================= 开始游戏 =================
 func (r *Room) startGame(roomConfig *config.Room) {
 	r.roomStatus = RoomStatusPlay
@@ -462,6 +652,9 @@ func (r *Room) startGame(roomConfig *config.Room) {
 		RoomType: r.roomType,
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
 	// 如果道具不足？？ todo
 	playerInfoList := make([]*pbGo.PlayerInfo, 0, r.maxPlayer)
 	for userId, p := range r.playerInfos {
@@ -470,13 +663,14 @@ func (r *Room) startGame(roomConfig *config.Room) {
 			HeroId:  uint32(p.HeroId),
 			BetInfo: make([]*pbGo.ItemInfo, 0),
 		})
-		if p.playerType > 0 {
+		if p.playerType > 0 || r.replaying {
 			continue
 		}
 		// 5. 匹配成功后扣道具
-		items.ConsumeItem(userId, roomConfig.Consume)
+		items.ConsumeItem(ctx, userId, roomConfig.Consume)
 	}
 	matchInfoPush.PlayerInfoList = playerInfoList
+	receivers := make([]*common.Player, 0, len(r.playerInfos))
 	for _, p := range r.playerInfos {
 		if p.playerType != 0 { //排除机器人
 			continue
@@ -485,19 +679,26 @@ func (r *Room) startGame(roomConfig *config.Room) {
 			continue
 		}
 
-		node.Push(p.Player, protoHandlerInit.MatchInfoPush, matchInfoPush)
+		receivers = append(receivers, p.Player)
+	}
+	if !r.replaying {
+		node.BroadcastAsync(receivers, protoHandlerInit.MatchInfoPush, matchInfoPush)
+		// 订阅房间类型主题，不阻塞房间协程
+		go node.Subscribe(receivers, common.RoomTypeTopic(r.roomType))
 	}
 
 	r.roundList = []*Round{}
 	r.nextRound(roomConfig, nil) //推送首轮
-	go r.startRobotActions(roomConfig)
 }
 
 // ================= 下一回合 =================
 func (r *Room) nextRound(roomConfig *config.Room, lastRound *Round) {
+	if lastRound != nil {
+		lastRound.stopRobots()
+	}
 
-	// 全是机器人则结束
-	earlyFinish := true
+	// 全是机器人则结束，离线模拟时除外
+	earlyFinish := !r.simulating
 	// 至少还有一个真实玩家在玩
 	for _, info := range r.playerInfos {
 		if info.playerType == 0 && info.status == PlayerStatusNormal {
@@ -513,6 +714,11 @@ func (r *Room) nextRound(roomConfig *config.Room, lastRound *Round) {
 		return
 	}
 
+	// 延长房间绑定
+	if !r.replaying {
+		r.refreshBinding()
+	}
+
 	// 生成新的下一轮
 	var (
 		// 从1开始
@@ -538,18 +744,56 @@ func (r *Room) nextRound(roomConfig *config.Room, lastRound *Round) {
 
 	r.roundList = append(r.roundList, round)
 
+	// 保存检查点，推送前保存，恢复时重新推送本回合
+	if !r.replaying {
+		r.checkpoint(roomConfig)
+	}
+	r.publishLive()
+
 	r.pushRoundInfo(roomConfig, nil)
 
 	// test
 	now := time.Now().Unix()
 	endTime = now + int64(roomConfig.Timeout)
 
-	// 启动超时
+	r.startTimer(roomConfig, round)
+	r.scheduleRobots(roomConfig, round)
+}
+
+// startTimer 启动回合超时
+func (r *Room) startTimer(roomConfig *config.Room, round *Round) {
+	index := round.RoundIndex
+	round.deadline = time.Now().Unix() + int64(roomConfig.Timeout)
+	if r.replaying { // 超时由回放日志给出
+		return
+	}
 	round.timer = time.AfterFunc(time.Duration(roomConfig.Timeout)*time.Second, func() {
 		r.cmdChan <- &timeoutCmd{RoundIndex: index, roomConfig: roomConfig} //发送信息通知
 	})
 }
 
+// ================= 恢复 =================
+func (r *Room) handleResume(c *resumeCmd) {
+	// 回合从恢复时重新计时
+	round := r.getCurrentRound()
+	round.creatTime = time.Now().Unix()
+
+	receivers := make([]*common.Player, 0, len(r.playerInfos))
+	for _, p := range r.playerInfos {
+		if p.playerType == 0 && p.status != PlayerStatusLeave {
+			receivers = append(receivers, p.Player)
+		}
+	}
+	if !r.replaying {
+		go node.Subscribe(receivers, common.RoomTypeTopic(r.roomType))
+	}
+	r.publishLive()
+
+	r.pushRoundInfo(c.roomConfig, nil)
+	r.startTimer(c.roomConfig, round)
+	r.scheduleRobots(c.roomConfig, round)
+}
+
 // 因为差距提前结束
 func isEarlyFinish(roomConfig *config.Room, lastRound *Round) bool {
 	if lastRound == nil {
@@ -600,9 +844,25 @@ func (r *Room) finishGame(roomConfig *config.Room) {
 		if round.timer != nil {
 			round.timer.Stop()
 		}
+		round.stopRobots()
 	}
 	// 计算结果
-	r.pushRoundInfo(roomConfig, r.calcResult())
+	r.settlement = r.calcResult()
+	r.pushRoundInfo(roomConfig, r.settlement)
+
+	r.dropLive()
+	if !r.replaying {
+		r.dropCheckpoint()
+
+		// 取消房间类型主题
+		players := make([]*common.Player, 0, len(r.playerInfos))
+		for _, p := range r.playerInfos {
+			if p.playerType == 0 {
+				players = append(players, p.Player)
+			}
+		}
+		go node.Unsubscribe(players, common.RoomTypeTopic(r.roomType))
+	}
 
 	// 清除房间 todo
 	r.cmdChan <- &stop{}    //必有,发送，停止主循环
@@ -617,13 +877,11 @@ func (r *Room) calcResult() *pbGo.RoomSettlementInfo {
 	maxV := int64(0)
 
 	curRound := r.getCurrentRound()
-	var (
-		targetUserId uint64
-		sumValue     = int64(0)
-	)
+	var targetUserId uint64
 
-	for uid, info := range curRound.Op {
-		v := info.goldValue
+	// 按玩家 id 顺序比较，出价相同时 id 小的获胜
+	for _, uid := range curRound.sortedUserIds() {
+		v := curRound.Op[uid].goldValue
 		if v > maxV {
 			itemInfo.ItemId = uint64(constValue.GoldItemId)
 			itemInfo.Count = v
@@ -637,18 +895,7 @@ func (r *Room) calcResult() *pbGo.RoomSettlementInfo {
 	betInfo = append(betInfo, itemInfo)
 	heroId := r.playerInfos[targetUserId].HeroId
 	// 利润
-	for _, info := range *r.gridInfo {
-		one := config.GetItemConfigById(info.Item.Id)
-		if one == nil {
-			log2.Get().Error("GetItemConfigById failed ", zap.Any("constValue", info.Item.Id))
-			continue
-		}
-		for id, value := range one.Price {
-			if id == constValue.GoldItemId {
-				sumValue += value
-			}
-		}
-	}
+	sumValue := r.sumValue()
 
 	// 响应
 	roomSettlementInfo.PlayerInfo = &pbGo.PlayerInfo{
@@ -662,12 +909,19 @@ func (r *Room) calcResult() *pbGo.RoomSettlementInfo {
 	}
 	roomSettlementInfo.Expenses = itemInfo
 	roomSettlementInfo.Profit = profit
+	ratings := r.calcRatings(targetUserId, profit.Count)
+	if r.replaying {
+		return roomSettlementInfo
+	}
+	r.saveRatings(ratings)
 
-	items.RewardItem(targetUserId, map[int]int64{
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	items.RewardItem(ctx, targetUserId, map[int]int64{
 		int(roomSettlementInfo.Profit.ItemId): sumValue,
 	})
 
-	items.ConsumeItem(targetUserId, map[int]int64{
+	items.ConsumeItem(ctx, targetUserId, map[int]int64{
 		int(roomSettlementInfo.Expenses.ItemId): roomSettlementInfo.Expenses.Count,
 	})
 	return roomSettlementInfo
@@ -675,6 +929,44 @@ func (r *Room) calcResult() *pbGo.RoomSettlementInfo {
 
 // ================= 工具 =================
 
+// sumValue 所有藏品的金币价值
+func (r *Room) sumValue() int64 {
+	sumValue := int64(0)
+	for _, info := range *r.gridInfo {
+		one := config.GetItemConfigById(info.Item.Id)
+		if one == nil {
+			log2.Get().Error("GetItemConfigById failed ", zap.Any("constValue", info.Item.Id))
+			continue
+		}
+		for id, value := range one.Price {
+			if id == constValue.GoldItemId {
+				sumValue += value
+			}
+		}
+	}
+	return sumValue
+}
+
+// sortedUserIds 房间内所有玩家 id，升序
+func (r *Room) sortedUserIds() []uint64 {
+	userIds := make([]uint64, 0, len(r.playerInfos))
+	for userId := range r.playerInfos {
+		userIds = append(userIds, userId)
+	}
+	slices.Sort(userIds)
+	return userIds
+}
+
+// sortedUserIds 本回合有操作的玩家 id，升序
+func (round *Round) sortedUserIds() []uint64 {
+	userIds := make([]uint64, 0, len(round.Op))
+	for userId := range round.Op {
+		userIds = append(userIds, userId)
+	}
+	slices.Sort(userIds)
+	return userIds
+}
+
 func (r *Room) getCurrentRound() *Round {
 	return r.roundList[len(r.roundList)-1]
 }
@@ -706,7 +998,7 @@ func (r *Room) Join(p *PlayerInfo, cfg *config.Room) error {
 	if r.roomStatus == RoomStatusClose {
 		return fmt.Errorf("room is bull")
 	}
-	resp := make(chan error)
+	resp := make(chan error, 1)
 	r.cmdChan <- &JoinCmd{
 		Player:     p,
 		roomConfig: cfg,
@@ -719,20 +1011,57 @@ func (r *Room) GettestG() []*maxRects.Placement {
 	return *r.gridInfo
 }
 
-func (r *Room) Action(roomConfig *config.Room, op *Operation) *ActionResp {
+// Action 玩家操作，ctx 结束时不再等待房间处理结果
+func (r *Room) Action(ctx context.Context, roomConfig *config.Room, op *Operation) *ActionResp {
 	if r.roomStatus == RoomStatusClose {
 		return &ActionResp{
 			Code: errorCode.ErrorCode_NotJoinRoom,
 		}
 	}
-	resp := make(chan *ActionResp)
-	r.cmdChan <- &ActionCmd{
+	resp := make(chan *ActionResp, 1) // 超时返回后，房间循环仍可写入
+	cmd := &ActionCmd{
+		ctx:        ctx,
 		UserId:     op.userId,
 		op:         op,
 		Resp:       resp,
 		roomConfig: roomConfig,
 	}
-	return <-resp
+	select {
+	case r.cmdChan <- cmd:
+	case <-ctx.Done():
+		return &ActionResp{
+			Code: errorCode.ErrorCode_Timeout,
+		}
+	}
+
+	select {
+	case res := <-resp:
+		return res
+	case <-ctx.Done():
+		return &ActionResp{
+			Code: errorCode.ErrorCode_Timeout,
+		}
+	}
+}
+
+// Snapshot 房间当前状态，ctx 结束时不再等待
+func (r *Room) Snapshot(ctx context.Context, player *common.Player) *SnapshotResp {
+	if r.roomStatus == RoomStatusClose {
+		return &SnapshotResp{Code: errorCode.ErrorCode_NotJoinRoom}
+	}
+	resp := make(chan *SnapshotResp, 1)
+	select {
+	case r.cmdChan <- &snapshotCmd{Player: player, Resp: resp}:
+	case <-ctx.Done():
+		return &SnapshotResp{Code: errorCode.ErrorCode_Timeout}
+	}
+
+	select {
+	case res := <-resp:
+		return res
+	case <-ctx.Done():
+		return &SnapshotResp{Code: errorCode.ErrorCode_Timeout}
+	}
 }
 
 func (r *Room) Leave(userId uint64) {
@@ -742,3 +1071,20 @@ func (r *Room) Leave(userId uint64) {
 	}
 	r.cmdChan <- &LeaveCmd{UserId: userId}
 }
+
+// refreshBinding 延长玩家与本节点房间的绑定，不阻塞房间协程
+func (r *Room) refreshBinding() {
+	userIds := make([]uint64, 0, len(r.playerInfos))
+	for userId, p := range r.playerInfos {
+		if p.playerType == 0 && p.status == PlayerStatusNormal {
+			userIds = append(userIds, userId)
+		}
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		defer cancel()
+		if err := affinity.Refresh(ctx, userIds); err != nil {
+			log2.Get().Warn("[room] refresh binding failed", zap.Int32("roomId", r.roomId), zap.Error(err))
+		}
+	}()
+}
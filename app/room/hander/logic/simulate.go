@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"gameServer/app/room/hander/config"
+	"gameServer/service/common"
+)
+
+// SimulateOption 离线模拟一个房间
+type SimulateOption struct {
+	Seed   uint64
+	Robots []*config.Robot // 每个座位的机器人配置，按配置的策略出价
+	Items  []int           // 藏品 id，为空时与线上房间相同按种子从房间配置中抽取
+}
+
+// SimulateResult 离线模拟一个房间的结果，座位按 SimulateOption.Robots 的顺序
+type SimulateResult struct {
+	Rounds      int   // 进行的回合数
+	EarlyFinish bool  // 未到最大回合就结束
+	Winner      int   // 获胜的座位，没有人出价时为 -1
+	Bid         int64 // 获胜者支付的金币
+	Value       int64 // 所有藏品的金币价值，获胜者获得
+	ItemsUsed   []int // 每个座位使用的道具数量
+}
+
+// Simulate 离线模拟一个全是机器人的房间，不读写数据库、不推送
+//
+// 与线上房间相同的回合、提示、提前结束和结算规则；每回合所有机器人依次按策略操作，不等待思考时间
+func Simulate(cfg *config.Room, opt SimulateOption) *SimulateResult {
+	r := newRoom(0, opt.Seed, cfg, make(chan int32, 1))
+	if len(opt.Items) > 0 {
+		placements := packItems(opt.Items)
+		r.gridInfo = &placements
+	}
+	r.replaying = true
+	r.simulating = true
+	r.maxPlayer = len(opt.Robots)
+
+	userIds := make([]uint64, 0, len(opt.Robots))
+	for i, robotConfig := range opt.Robots {
+		robot := createOneRobot(cfg, robotConfig).player
+		robot.Player = &common.Player{UserId: uint64(i + 1)} // 座位顺序即 id 顺序，出价相同时靠前的获胜
+		r.playerInfos[robot.Player.UserId] = robot
+		userIds = append(userIds, robot.Player.UserId)
+	}
+	r.startGame(cfg)
+
+	for r.roomStatus == RoomStatusPlay {
+		round := r.getCurrentRound()
+		for _, userId := range userIds {
+			if r.roomStatus != RoomStatusPlay || r.getCurrentRound() != round {
+				break
+			}
+			r.handleRobot(&robotCmd{UserId: userId, RoundIndex: round.RoundIndex, roomConfig: cfg})
+		}
+		// 都操作后已进入下一回合，否则未操作的按超时处理
+		if r.roomStatus == RoomStatusPlay && r.getCurrentRound() == round {
+			r.handleTimeout(&timeoutCmd{RoundIndex: round.RoundIndex, roomConfig: cfg})
+		}
+	}
+
+	res := &SimulateResult{
+		Rounds:      len(r.roundList),
+		EarlyFinish: len(r.roundList) < int(r.maxRound),
+		Winner:      -1,
+		Value:       r.sumValue(),
+		ItemsUsed:   make([]int, len(userIds)),
+	}
+	for i, userId := range userIds {
+		res.ItemsUsed[i] = len(r.playerInfos[userId].UserItemMap)
+	}
+	if s := r.settlement; s != nil {
+		res.Winner = int(s.PlayerInfo.UserId) - 1
+		res.Bid = s.Expenses.Count
+	}
+	return res
+}
@@ -0,0 +1,506 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gameServer/app/room/hander/config"
+	"gameServer/app/room/hander/maxRects"
+	"gameServer/common/db/affinity"
+	"gameServer/common/db/items"
+	"gameServer/common/db/snapshot"
+	config2 "gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/protobuf/pbGo"
+	"gameServer/protobuf/protoHandlerInit"
+	"gameServer/service/common"
+	"gameServer/service/services/node"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/exp/rand"
+)
+
+// SnapshotOption 房间状态检查点，在 [room.snapshot] 中配置
+type SnapshotOption struct {
+	// Enable 每回合开始时保存房间状态，节点重启后据此恢复或退还
+	Enable bool
+	// Restore 重启后恢复房间并继续当前回合，关闭时一律结算退还
+	Restore bool
+	// MaxAge 超过该时间的状态不再恢复，玩家大多已离开，直接退还
+	MaxAge time.Duration
+}
+
+// snapshotOption 从配置读取，未配置时关闭
+var snapshotOption = sync.OnceValue(func() SnapshotOption {
+	opt := SnapshotOption{MaxAge: 5 * time.Minute}
+	service := config2.Get().Service()
+	if service == nil {
+		return opt
+	}
+	opt.Enable = service.GetBool("snapshot.enable")
+	opt.Restore = service.GetBool("snapshot.restore")
+	if v := service.GetInt64("snapshot.maxage"); v > 0 {
+		opt.MaxAge = time.Duration(v) * time.Second
+	}
+	return opt
+})
+
+// roomSnapshot 回合开始时的房间状态，推送回合信息之前保存，恢复时重新开始该回合
+type roomSnapshot struct {
+	RoomId     int32
+	RoomType   uint32
+	CreateTime int64
+	SaveTime   int64         // 保存时间戳 秒
+	Consume    map[int]int64 // 入场消耗，退还时使用，不受配置变更影响
+	Seed       uint64        // 随机种子
+	Rng        []byte        // 随机数状态，恢复后结果与未中断时一致
+	Config     *config.Room  // 房间配置，私人房间的规则与配置表不同
+	Refunding  bool          // 已开始退还，不再恢复
+
+	Players  []*playerSnapshot
+	Rounds   []*roundSnapshot
+	GridInfo []*maxRects.Placement // 物品及每个玩家已显示的信息
+}
+
+type playerSnapshot struct {
+	Player        *common.Player
+	PlayerType    uint8
+	RobotType     uint8 // 机器人配置 id
+	HeroId        int
+	ChoiceItemMap map[int]int64
+	UserItemMap   map[int]int8
+	Status        uint8
+	Rating        int64
+
+	Refunded      bool         // 已全部退还
+	RefundedItems map[int]bool // 已退还的道具 id
+}
+
+func newPlayerSnapshot(p *PlayerInfo) *playerSnapshot {
+	ps := &playerSnapshot{
+		Player:        p.Player,
+		PlayerType:    p.playerType,
+		HeroId:        p.HeroId,
+		ChoiceItemMap: p.ChoiceItemMap,
+		UserItemMap:   p.UserItemMap,
+		Status:        p.status,
+		Rating:        p.Rating,
+	}
+	if p.robotConfig != nil {
+		ps.RobotType = p.robotConfig.RobotType
+	}
+	return ps
+}
+
+// playerInfo 按状态还原玩家，机器人配置不存在时返回错误
+func (ps *playerSnapshot) playerInfo() (*PlayerInfo, error) {
+	p := &PlayerInfo{
+		Player:        ps.Player,
+		playerType:    ps.PlayerType,
+		HeroId:        ps.HeroId,
+		ChoiceItemMap: ps.ChoiceItemMap,
+		UserItemMap:   ps.UserItemMap,
+		status:        ps.Status,
+		Rating:        ps.Rating,
+	}
+	if p.UserItemMap == nil {
+		p.UserItemMap = make(map[int]int8)
+	}
+	if p.playerType > 0 {
+		if p.robotConfig = config.GetRobotById(ps.RobotType); p.robotConfig == nil {
+			return nil, fmt.Errorf("robot config not found: %d", ps.RobotType)
+		}
+	}
+	return p, nil
+}
+
+type roundSnapshot struct {
+	RoundIndex int8
+	CreatTime  int64
+	Op         []*opSnapshot
+}
+
+type opSnapshot struct {
+	UserId    uint64
+	GoldValue int64
+	Operation int8
+	IsBet     bool
+	ItemId    int
+}
+
+// buildSnapshot 当前房间状态，只能在房间协程中调用
+func (r *Room) buildSnapshot(roomConfig *config.Room) *roomSnapshot {
+	s := &roomSnapshot{
+		RoomId:     r.roomId,
+		RoomType:   r.roomType,
+		CreateTime: r.createTime,
+		SaveTime:   time.Now().Unix(),
+		Consume:    roomConfig.Consume,
+		Seed:       r.seed,
+		Config:     roomConfig,
+		Players:    make([]*playerSnapshot, 0, len(r.playerInfos)),
+		Rounds:     make([]*roundSnapshot, 0, len(r.roundList)),
+		GridInfo:   *r.gridInfo,
+	}
+	s.Rng, _ = r.rngSrc.MarshalBinary()
+	for _, p := range r.playerInfos {
+		s.Players = append(s.Players, newPlayerSnapshot(p))
+	}
+	for _, round := range r.roundList {
+		rs := &roundSnapshot{
+			RoundIndex: round.RoundIndex,
+			CreatTime:  round.creatTime,
+			Op:         make([]*opSnapshot, 0, len(round.Op)),
+		}
+		for userId, op := range round.Op {
+			rs.Op = append(rs.Op, &opSnapshot{
+				UserId:    userId,
+				GoldValue: op.goldValue,
+				Operation: op.operation,
+				IsBet:     op.isBet,
+				ItemId:    op.itemId,
+			})
+		}
+		s.Rounds = append(s.Rounds, rs)
+	}
+	return s
+}
+
+// checkpoint 编码房间状态交给后台写入，不在房间协程中等待 ssdb；失败只记录日志，不影响本局进行
+func (r *Room) checkpoint(roomConfig *config.Room) {
+	if !snapshotOption().Enable {
+		return
+	}
+	data, err := json.Marshal(r.buildSnapshot(roomConfig))
+	if err != nil {
+		log2.Get().Error("[room] marshal snapshot failed", zap.Int32("roomId", r.roomId), zap.Error(err))
+		return
+	}
+	checkpointWriter().put(r.roomId, data)
+}
+
+// dropCheckpoint 房间结束后删除状态，与保存按顺序在后台执行，不会被未写入的保存覆盖
+func (r *Room) dropCheckpoint() {
+	if !snapshotOption().Enable {
+		return
+	}
+	checkpointWriter().put(r.roomId, nil)
+}
+
+// roomWriter 后台按房间写入，同一房间未写入的数据只保留最新的一次，保存和删除按顺序执行
+type roomWriter struct {
+	name    string
+	mu      sync.Mutex
+	pending map[int32][]byte // 房间 id-数据，nil 为删除
+	wake    chan struct{}
+	write   func(ctx context.Context, roomId int32, data []byte) error
+}
+
+func newRoomWriter(name string, write func(ctx context.Context, roomId int32, data []byte) error) *roomWriter {
+	w := &roomWriter{
+		name:    name,
+		pending: make(map[int32][]byte),
+		wake:    make(chan struct{}, 1),
+		write:   write,
+	}
+	go w.run()
+	return w
+}
+
+var checkpointWriter = sync.OnceValue(func() *roomWriter {
+	serverId := config2.Get().NodeID()
+	return newRoomWriter("snapshot", func(ctx context.Context, roomId int32, data []byte) error {
+		if data == nil {
+			return snapshot.Delete(ctx, serverId, roomId)
+		}
+		return snapshot.Save(ctx, serverId, roomId, data)
+	})
+})
+
+func (w *roomWriter) put(roomId int32, data []byte) {
+	w.mu.Lock()
+	w.pending[roomId] = data
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *roomWriter) run() {
+	for range w.wake {
+		w.mu.Lock()
+		batch := w.pending
+		w.pending = make(map[int32][]byte, len(batch))
+		w.mu.Unlock()
+
+		for roomId, data := range batch {
+			ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+			if err := w.write(ctx, roomId, data); err != nil {
+				log2.Get().Warn("[room] write failed", zap.String("name", w.name), zap.Int32("roomId", roomId), zap.Bool("delete", data == nil), zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
+
+// Recover 处理节点重启前未结束的房间，按房间 id 顺序逐个恢复或退还
+//
+// 在 rpc 客户端就绪之后、开始接受请求之前调用，恢复和退还时会向网关推送
+func Recover() {
+	opt := snapshotOption()
+	if !opt.Enable {
+		return
+	}
+	serverId := config2.Get().NodeID()
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	all, err := snapshot.LoadAll(ctx, serverId)
+	cancel()
+	if err != nil {
+		log2.Get().Error("[Recover] load snapshots failed", zap.Uint32("serverId", serverId), zap.Error(err))
+		return
+	}
+
+	roomIds := make([]int32, 0, len(all))
+	for roomId := range all {
+		roomIds = append(roomIds, roomId)
+	}
+	slices.Sort(roomIds)
+	// 新房间跳过所有快照中的 id，退还中和恢复失败的快照仍保存在原 id 下
+	if len(roomIds) > 0 {
+		roomManager.mu.Lock()
+		roomManager.nextRoomId = max(roomManager.nextRoomId, roomIds[len(roomIds)-1])
+		roomManager.mu.Unlock()
+	}
+
+	for _, roomId := range roomIds {
+		s := &roomSnapshot{}
+		if err = json.Unmarshal(all[roomId], s); err != nil {
+			log2.Get().Error("[Recover] invalid snapshot, dropped", zap.Int32("roomId", roomId), zap.ByteString("data", all[roomId]), zap.Error(err))
+			dropSnapshot(serverId, roomId)
+			continue
+		}
+		if opt.Restore && !s.Refunding && time.Since(time.Unix(s.SaveTime, 0)) <= opt.MaxAge {
+			if err = roomManager.restoreRoom(s); err == nil {
+				log2.Get().Info("[Recover] room restored", zap.Int32("roomId", roomId), zap.Int("round", len(s.Rounds)))
+				continue
+			}
+			log2.Get().Warn("[Recover] restore room failed, refund", zap.Int32("roomId", roomId), zap.Error(err))
+		}
+		refundRoom(serverId, s)
+	}
+}
+
+// restoreRoom 按状态重建房间并重新开始保存时的回合
+func (rm *RoomManager) restoreRoom(s *roomSnapshot) error {
+	roomConfig := s.Config
+	if roomConfig == nil {
+		roomConfig = config.GetRoomConfigByRoomId(s.RoomType)
+	}
+	if roomConfig == nil {
+		return fmt.Errorf("room config not found: %d", s.RoomType)
+	}
+	r, err := roomFromSnapshot(s, roomConfig, rm.roomCloseCh)
+	if err != nil {
+		return err
+	}
+	r.recorder = openReplayRecorder(r.roomId, r.createTime)
+
+	userIds := make([]uint64, 0, len(r.playerInfos))
+	rm.mu.Lock()
+	rm.rooms[r.roomId] = r
+	for userId, p := range r.playerInfos {
+		if p.playerType > 0 || p.status == PlayerStatusLeave {
+			continue
+		}
+		rm.playerRoom[userId] = r
+		rm.playerState[userId] = StateInRoom
+		userIds = append(userIds, userId)
+	}
+	rm.mu.Unlock()
+
+	// 重新绑定，宕机期间绑定可能已过期
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		defer cancel()
+		if err := affinity.Bind(ctx, userIds, config2.Get().NodeID(), r.roomId); err != nil {
+			log2.Get().Error("[restoreRoom] bind room failed", zap.Int32("roomId", r.roomId), zap.Error(err))
+		}
+	}()
+
+	go r.loop()
+	r.cmdChan <- &resumeCmd{roomConfig: roomConfig, snapshot: s}
+	return nil
+}
+
+// roomFromSnapshot 按状态创建房间，不启动主循环
+func roomFromSnapshot(s *roomSnapshot, roomConfig *config.Room, closeChan chan int32) (*Room, error) {
+	if len(s.Rounds) == 0 {
+		return nil, fmt.Errorf("no round in snapshot")
+	}
+	rngSrc := &rand.PCGSource{}
+	if err := rngSrc.UnmarshalBinary(s.Rng); err != nil {
+		return nil, fmt.Errorf("invalid rng state: %w", err)
+	}
+
+	gridInfo := s.GridInfo
+	r := &Room{
+		roomId:      s.RoomId,
+		roomType:    s.RoomType,
+		maxPlayer:   roomConfig.CapacityLimit,
+		createTime:  s.CreateTime,
+		playerInfos: make(PlayerInfos, len(s.Players)),
+		roomStatus:  RoomStatusPlay,
+		roundList:   make([]*Round, 0, len(s.Rounds)),
+		maxRound:    roomConfig.RoundLimit,
+		roomConfig:  roomConfig,
+		cmdChan:     make(chan interface{}, 100),
+		closeChan:   closeChan,
+		gridInfo:    &gridInfo,
+		seed:        s.Seed,
+		rngSrc:      rngSrc,
+		rng:         rand.New(rngSrc),
+		robotRng:    rand.New(rand.NewSource(s.Seed + uint64(len(s.Rounds)))),
+	}
+	for _, ps := range s.Players {
+		p, err := ps.playerInfo()
+		if err != nil {
+			return nil, err
+		}
+		r.playerInfos[p.Player.UserId] = p
+	}
+	for _, rs := range s.Rounds {
+		round := &Round{
+			RoundIndex: rs.RoundIndex,
+			creatTime:  rs.CreatTime,
+			Op:         make(map[uint64]*Operation, len(rs.Op)),
+		}
+		for _, op := range rs.Op {
+			round.Op[op.UserId] = &Operation{
+				userId:    op.UserId,
+				goldValue: op.GoldValue,
+				operation: op.Operation,
+				isBet:     op.IsBet,
+				itemId:    op.ItemId,
+			}
+		}
+		r.roundList = append(r.roundList, round)
+	}
+	return r, nil
+}
+
+// refundRoom 无法恢复的房间，退还入场和已使用的道具
+//
+// 退还失败时重试，每个玩家退还后保存进度，全部退还后才删除状态；
+// 仍失败的玩家留到下次重启处理，已退还的道具不会重复退还
+func refundRoom(serverId uint32, s *roomSnapshot) {
+	var roundIndex int8
+	if len(s.Rounds) > 0 {
+		roundIndex = s.Rounds[len(s.Rounds)-1].RoundIndex
+	}
+	done := true
+	for _, ps := range s.Players {
+		if ps.PlayerType > 0 || ps.Refunded {
+			continue
+		}
+		userId := ps.Player.UserId
+		if !refundPlayer(s, ps) {
+			done = false
+		}
+		s.Refunding = true // 已开始退还，下次重启不再恢复
+		if err := saveSnapshot(serverId, s); err != nil {
+			// 进度未保存，下次重启可能重复退还该玩家，需要人工核对
+			log2.Get().Error("[refundRoom] save refund progress failed", zap.Int32("roomId", s.RoomId), zap.Uint64("userId", userId), zap.Any("refunded", ps.RefundedItems), zap.Error(err))
+			return
+		}
+		if !ps.Refunded {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		if err := affinity.Unbind(ctx, userId, serverId, s.RoomId); err != nil {
+			log2.Get().Warn("[refundRoom] unbind room failed", zap.Uint64("userId", userId), zap.Int32("roomId", s.RoomId), zap.Error(err))
+		}
+		cancel()
+		if ps.Status != PlayerStatusLeave {
+			node.PushAsync(ps.Player, protoHandlerInit.RoundInfoPush, &pbGo.RoundInfoPush{
+				RoundIndex: uint32(roundIndex),
+				IsFinish:   true,
+			})
+		}
+	}
+	if !done {
+		log2.Get().Error("[refundRoom] refund incomplete, retry on next restart", zap.Int32("roomId", s.RoomId))
+		return
+	}
+	dropSnapshot(serverId, s.RoomId)
+	log2.Get().Info("[refundRoom] room refunded", zap.Int32("roomId", s.RoomId), zap.Any("consume", s.Consume))
+}
+
+// refundRetry 单个道具退还失败时的重试次数和间隔
+const (
+	refundRetry         = 3
+	refundRetryInterval = 500 * time.Millisecond
+)
+
+// refundPlayer 逐个道具退还，已退还的道具记录在 RefundedItems 中，全部退还后返回 true
+func refundPlayer(s *roomSnapshot, ps *playerSnapshot) bool {
+	refund := make(map[int]int64, len(s.Consume)+len(ps.UserItemMap))
+	for itemId, count := range s.Consume {
+		refund[itemId] += count
+	}
+	for itemId := range ps.UserItemMap {
+		refund[itemId]++
+	}
+	if ps.RefundedItems == nil {
+		ps.RefundedItems = make(map[int]bool, len(refund))
+	}
+
+	userId := ps.Player.UserId
+	for itemId, count := range refund {
+		if ps.RefundedItems[itemId] {
+			continue
+		}
+		var err error
+		for i := range refundRetry {
+			if i > 0 {
+				time.Sleep(refundRetryInterval)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+			_, err = items.AddItem(ctx, userId, itemId, count)
+			cancel()
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			log2.Get().Error("[refundRoom] refund failed", zap.Int32("roomId", s.RoomId), zap.Uint64("userId", userId), zap.Int("itemId", itemId), zap.Int64("count", count), zap.Error(err))
+			return false
+		}
+		ps.RefundedItems[itemId] = true
+	}
+	ps.Refunded = true
+	return true
+}
+
+func saveSnapshot(serverId uint32, s *roomSnapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	return snapshot.Save(ctx, serverId, s.RoomId, data)
+}
+
+func dropSnapshot(serverId uint32, roomId int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	if err := snapshot.Delete(ctx, serverId, roomId); err != nil {
+		log2.Get().Error("[Recover] delete snapshot failed", zap.Int32("roomId", roomId), zap.Error(err))
+	}
+}
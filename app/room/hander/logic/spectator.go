@@ -0,0 +1,433 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"gameServer/app/room/hander/config"
+	"gameServer/app/room/hander/maxRects"
+	"gameServer/common/db/affinity"
+	"gameServer/common/db/live"
+	"gameServer/common/errorCode"
+	config2 "gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/protobuf/pbGo"
+	"gameServer/protobuf/protoHandlerInit"
+	"gameServer/service/common"
+	"gameServer/service/services/node"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// spectatorLimit 每个房间的观战人数上限，在 [room.spectator] 中配置
+var spectatorLimit = sync.OnceValue(func() int {
+	limit := 50
+	service := config2.Get().Service()
+	if service == nil {
+		return limit
+	}
+	if v := service.GetInt("spectator.limit"); v > 0 {
+		limit = v
+	}
+	return limit
+})
+
+// ================= 观战命令 =================
+
+// 加入观战
+type spectateCmd struct {
+	Player *common.Player
+	Resp   chan *SnapshotResp
+}
+
+// 退出观战
+type unspectateCmd struct {
+	UserId uint64
+}
+
+func (r *Room) handleSpectate(c *spectateCmd) {
+	userId := c.Player.UserId
+	if r.roomStatus != RoomStatusPlay || len(r.roundList) == 0 {
+		c.Resp <- &SnapshotResp{Code: errorCode.ErrorCode_RoomNotFound}
+		return
+	}
+	if _, ok := r.playerInfos[userId]; ok {
+		c.Resp <- &SnapshotResp{Code: errorCode.ErrorCode_InRoom}
+		return
+	}
+	if _, ok := r.spectators[userId]; !ok && len(r.spectators) >= spectatorLimit() {
+		c.Resp <- &SnapshotResp{Code: errorCode.ErrorCode_SpectatorFull}
+		return
+	}
+	if r.spectators == nil {
+		r.spectators = make(map[uint64]*common.Player)
+	}
+	r.spectators[userId] = c.Player
+	r.spectatorCount.Store(int32(len(r.spectators)))
+	r.publishLive()
+
+	// 补发匹配信息，客户端据此进入房间界面
+	node.PushAsync(c.Player, protoHandlerInit.MatchInfoPush, &pbGo.MatchInfoPush{
+		RoomType:       r.roomType,
+		PlayerInfoList: r.buildPlayerInfoList(),
+	})
+
+	screenInfo := &pbGo.ScreenInfo{}
+	buildSpectatorScreenInfo(screenInfo, *r.gridInfo, r.activeBidderIds())
+	c.Resp <- &SnapshotResp{Data: r.buildSnapshotResp(screenInfo)}
+}
+
+func (r *Room) handleUnspectate(c *unspectateCmd) {
+	delete(r.spectators, c.UserId)
+	r.spectatorCount.Store(int32(len(r.spectators)))
+	r.publishLive()
+}
+
+// activeBidderIds 仍在房间中的真实玩家，升序
+func (r *Room) activeBidderIds() []uint64 {
+	userIds := make([]uint64, 0, len(r.playerInfos))
+	for _, userId := range r.sortedUserIds() {
+		p := r.playerInfos[userId]
+		if p.playerType == 0 && p.status == PlayerStatusNormal {
+			userIds = append(userIds, userId)
+		}
+	}
+	return userIds
+}
+
+// spectatorPlayers 所有观战者
+func (r *Room) spectatorPlayers() []*common.Player {
+	players := make([]*common.Player, 0, len(r.spectators))
+	for _, p := range r.spectators {
+		players = append(players, p)
+	}
+	return players
+}
+
+// pushSpectators 向观战者推送回合信息，不包含提示，物品信息只包含所有玩家都已看到的部分
+func (r *Room) pushSpectators(roomConfig *config.Room, roomSettlementInfo *pbGo.RoomSettlementInfo) {
+	if len(r.spectators) == 0 || r.replaying {
+		return
+	}
+	curRound := r.getCurrentRound()
+	changeScreenInfo := &pbGo.ScreenInfo{}
+	buildSpectatorScreenInfo(changeScreenInfo, *r.gridInfo, r.activeBidderIds())
+	if lastRound := r.getLastRound(); lastRound != nil {
+		changeScreenInfo.PlayerInfoList = r.buildBetInfoList(lastRound)
+	}
+	node.BroadcastAsync(r.spectatorPlayers(), protoHandlerInit.RoundInfoPush, &pbGo.RoundInfoPush{
+		EndTimeOut:       curRound.creatTime + int64(roomConfig.Timeout),
+		RoundIndex:       uint32(curRound.RoundIndex),
+		IsFinish:         roomSettlementInfo != nil,
+		ChangeScreenInfo: changeScreenInfo,
+		SettlementInfo:   roomSettlementInfo,
+	})
+}
+
+// buildSpectatorScreenInfo 观战者看到的物品信息
+//
+// 只包含所有玩家都已看到的格子和物品，变化回合取最后一个看到的玩家的回合，避免泄露单个玩家的提示
+func buildSpectatorScreenInfo(changeScreenInfo *pbGo.ScreenInfo, gridInfo []*maxRects.Placement, userIds []uint64) {
+	changeScreenInfo.GridList = make([]*pbGo.Grid, 0)
+	changeScreenInfo.AllGoods = make([]*pbGo.Goods, 0)
+	if len(userIds) == 0 {
+		return
+	}
+
+	for _, p := range gridInfo {
+		shows := make([]*maxRects.ShowInfo, 0, len(userIds))
+		for _, userId := range userIds {
+			showInfo := p.ShowInfoMap[userId]
+			if showInfo == nil {
+				break
+			}
+			shows = append(shows, showInfo)
+		}
+		if len(shows) < len(userIds) {
+			continue
+		}
+
+		// 品质变化
+		for index, qInfo := range shows[0].Quality {
+			roundIndex := qInfo.RoundIndex
+			for _, showInfo := range shows[1:] {
+				other, ok := showInfo.Quality[index]
+				if !ok {
+					roundIndex = 0
+					break
+				}
+				roundIndex = max(roundIndex, other.RoundIndex)
+			}
+			if roundIndex == 0 {
+				continue
+			}
+			changeScreenInfo.GridList = append(changeScreenInfo.GridList, &pbGo.Grid{
+				IndexId:     index,
+				ShowQuality: uint32(roundIndex),
+				QualityType: uint32(qInfo.QualityType),
+			})
+		}
+
+		// 轮廓和全显示
+		contour, all := shows[0].Contour, shows[0].All
+		for _, showInfo := range shows[1:] {
+			contour = commonRound(contour, showInfo.Contour)
+			all = commonRound(all, showInfo.All)
+		}
+		if contour == 0 && all == 0 {
+			continue
+		}
+		changeScreenInfo.AllGoods = append(changeScreenInfo.AllGoods, &pbGo.Goods{
+			ItemId:      uint32(p.Item.Id),
+			Index:       getIndexByXY(p.StartX, p.StartY),
+			ShowAll:     uint32(all),
+			ShowContour: uint32(contour),
+		})
+	}
+	sort.Slice(changeScreenInfo.GridList, func(i, j int) bool {
+		return changeScreenInfo.GridList[i].IndexId < changeScreenInfo.GridList[j].IndexId
+	})
+}
+
+// commonRound 两个玩家都看到时取较晚的回合，任一未看到时为 0
+func commonRound(a, b int8) int8 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return max(a, b)
+}
+
+// ================= 对外接口 =================
+
+// Spectate 加入观战，ctx 结束时不再等待
+func (r *Room) Spectate(ctx context.Context, player *common.Player) *SnapshotResp {
+	if r.roomStatus == RoomStatusClose {
+		return &SnapshotResp{Code: errorCode.ErrorCode_RoomNotFound}
+	}
+	resp := make(chan *SnapshotResp, 1)
+	select {
+	case r.cmdChan <- &spectateCmd{Player: player, Resp: resp}:
+	case <-ctx.Done():
+		return &SnapshotResp{Code: errorCode.ErrorCode_Timeout}
+	}
+
+	select {
+	case res := <-resp:
+		return res
+	case <-ctx.Done():
+		return &SnapshotResp{Code: errorCode.ErrorCode_Timeout}
+	}
+}
+
+// Unspectate 退出观战
+func (r *Room) Unspectate(userId uint64) {
+	if r.roomStatus == RoomStatusClose {
+		return
+	}
+	r.cmdChan <- &unspectateCmd{UserId: userId}
+}
+
+// SpectatorCount 观战人数
+func (r *Room) SpectatorCount() int {
+	return int(r.spectatorCount.Load())
+}
+
+// LiveRooms 正在进行中的某类型房间，不包含私人房间，按房间 id 排序
+func (rm *RoomManager) LiveRooms(roomType uint32) []*Room {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	rooms := make([]*Room, 0)
+	for _, r := range rm.rooms {
+		if r.roomType == roomType && r.roomStatus == RoomStatusPlay && r.private == nil {
+			rooms = append(rooms, r)
+		}
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].roomId < rooms[j].roomId
+	})
+	return rooms
+}
+
+// spectateRoom 房间的观战列表信息
+func (r *Room) spectateRoom() *pbGo.SpectateRoom {
+	return &pbGo.SpectateRoom{
+		RoomId:         r.roomId,
+		RoomType:       r.roomType,
+		PlayerInfoList: r.buildPlayerInfoList(),
+		SpectatorCount: uint32(r.SpectatorCount()),
+		ServerId:       config2.Get().NodeID(),
+	}
+}
+
+// ================= 跨节点观战 =================
+
+// liveTTL 房间每回合刷新列表信息，超过该时间未刷新的房间所在节点已异常退出
+const liveTTL = 5 * time.Minute
+
+// liveRoom 保存在 ssdb 中的可观战房间
+type liveRoom struct {
+	RoomType uint32
+	SaveTime int64  // 保存时间戳 秒
+	Room     []byte // pbGo.SpectateRoom
+}
+
+// liveWriter 后台写入本节点可观战的房间，房间结束后删除
+var liveWriter = sync.OnceValue(func() *roomWriter {
+	serverId := config2.Get().NodeID()
+	roomTypes := make(map[int32]uint32) // 已保存的房间 id-类型，删除时使用，只在写入协程中访问
+	return newRoomWriter("live", func(ctx context.Context, roomId int32, data []byte) error {
+		if data == nil {
+			roomType, ok := roomTypes[roomId]
+			if !ok {
+				return nil
+			}
+			delete(roomTypes, roomId)
+			return live.Delete(ctx, roomType, serverId, roomId)
+		}
+		lr := &liveRoom{}
+		if err := json.Unmarshal(data, lr); err != nil {
+			return err
+		}
+		roomTypes[roomId] = lr.RoomType
+		return live.Save(ctx, lr.RoomType, serverId, roomId, data)
+	})
+})
+
+// publishLive 更新房间在观战列表中的信息，其它节点据此列出并转发观战请求，私人房间不列出
+func (r *Room) publishLive() {
+	if r.replaying || r.private != nil || r.roomStatus != RoomStatusPlay {
+		return
+	}
+	room, err := proto.Marshal(r.spectateRoom())
+	if err != nil {
+		log2.Get().Error("[room] marshal live room failed", zap.Int32("roomId", r.roomId), zap.Error(err))
+		return
+	}
+	data, err := json.Marshal(&liveRoom{RoomType: r.roomType, SaveTime: time.Now().Unix(), Room: room})
+	if err != nil {
+		log2.Get().Error("[room] marshal live room failed", zap.Int32("roomId", r.roomId), zap.Error(err))
+		return
+	}
+	liveWriter().put(r.roomId, data)
+}
+
+// dropLive 房间结束后从观战列表中删除
+func (r *Room) dropLive() {
+	if r.replaying || r.private != nil {
+		return
+	}
+	liveWriter().put(r.roomId, nil)
+}
+
+// SpectateRoomList 所有节点上可观战的房间，按节点和房间 id 排序；本节点的房间使用最新的信息，
+// 读取失败时只返回本节点的房间
+func SpectateRoomList(ctx context.Context, roomType uint32) []*pbGo.SpectateRoom {
+	serverId := config2.Get().NodeID()
+	list := make([]*pbGo.SpectateRoom, 0)
+	for _, r := range roomManager.LiveRooms(roomType) {
+		// 开始后玩家不再变化，可在房间协程外读取
+		list = append(list, r.spectateRoom())
+	}
+
+	all, err := live.LoadAll(ctx, roomType)
+	if err != nil {
+		log2.Get().Warn("[SpectateRoomList] load live rooms failed", zap.Uint32("roomType", roomType), zap.Error(err))
+		return list
+	}
+	now := time.Now().Unix()
+	for at, data := range all {
+		if at.ServerId == serverId {
+			continue
+		}
+		lr := &liveRoom{}
+		room := &pbGo.SpectateRoom{}
+		if err = json.Unmarshal(data, lr); err == nil {
+			err = proto.Unmarshal(lr.Room, room)
+		}
+		if err != nil || time.Duration(now-lr.SaveTime)*time.Second > liveTTL {
+			// 节点异常退出留下的房间
+			if err = live.Delete(ctx, roomType, at.ServerId, at.RoomId); err != nil {
+				log2.Get().Warn("[SpectateRoomList] drop live room failed", zap.Uint32("serverId", at.ServerId), zap.Int32("roomId", at.RoomId), zap.Error(err))
+			}
+			continue
+		}
+		room.ServerId = at.ServerId
+		list = append(list, room)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ServerId != list[j].ServerId {
+			return list[i].ServerId < list[j].ServerId
+		}
+		return list[i].RoomId < list[j].RoomId
+	})
+	return list
+}
+
+// JoinSpectate 观战指定房间，roomId 为 0 时观战 targetUserId 所在的房间；已在其它房间观战时先退出
+//
+// 房间在其它节点时，通知网关把房间协议改为转发到该节点，返回 ErrorCode_Moved，由网关重新转发
+func JoinSpectate(ctx context.Context, player *common.Player, roomId int32, serverId uint32, targetUserId uint64) (*pbGo.GetRoomSnapshotResp, uint16) {
+	var (
+		room  *Room
+		local = config2.Get().NodeID()
+	)
+	if roomId > 0 {
+		if serverId > 0 && serverId != local {
+			return nil, moveSpectator(player, serverId)
+		}
+		room = roomManager.FindRoom(roomId)
+	} else if targetUserId > 0 {
+		room = roomManager.FindRoomByUserId(targetUserId)
+		if room == nil {
+			binding, err := affinity.Find(ctx, targetUserId)
+			if err != nil {
+				log2.Get().Warn("[JoinSpectate] find room binding failed", zap.Uint64("targetUserId", targetUserId), zap.Error(err))
+				return nil, errorCode.ErrorCode_DBError
+			}
+			if binding != nil && binding.ServerId != local {
+				return nil, moveSpectator(player, binding.ServerId)
+			}
+		}
+	}
+	if room == nil {
+		return nil, errorCode.ErrorCode_RoomNotFound
+	}
+
+	userId := player.UserId
+	roomManager.mu.Lock()
+	old := roomManager.spectatorRoom[userId]
+	roomManager.mu.Unlock()
+	if old != nil && old != room {
+		LeaveSpectate(userId)
+	}
+
+	resp := room.Spectate(ctx, player)
+	if resp.Code != 0 {
+		return nil, resp.Code
+	}
+	roomManager.mu.Lock()
+	roomManager.spectatorRoom[userId] = room
+	roomManager.mu.Unlock()
+	return resp.Data, 0
+}
+
+// moveSpectator 退出本节点的观战，并把玩家的房间协议绑定到 serverId 节点
+func moveSpectator(player *common.Player, serverId uint32) uint16 {
+	LeaveSpectate(player.UserId)
+	return moveToNode(player, serverId)
+}
+
+// LeaveSpectate 退出观战，未观战时忽略
+func LeaveSpectate(userId uint64) {
+	roomManager.mu.Lock()
+	room := roomManager.spectatorRoom[userId]
+	delete(roomManager.spectatorRoom, userId)
+	roomManager.mu.Unlock()
+
+	if room != nil {
+		room.Unspectate(userId)
+	}
+}
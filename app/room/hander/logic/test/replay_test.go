@@ -0,0 +1,185 @@
+package test
+
+import (
+	"encoding/json"
+	"gameServer/app/room/hander/config"
+	"gameServer/app/room/hander/logic"
+	"gameServer/pkg/excel/reader"
+	"gameServer/pkg/logger/log2"
+	"os"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/rand"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMain(m *testing.M) {
+	log2.Init(log2.Config{Level: zapcore.ErrorLevel, IsDocker: true})
+
+	r := reader.NewExcelReader("../../../../../excels")
+	allData, err := r.ReadAllExcels()
+	if err != nil {
+		panic(err)
+	}
+	// 只读取表格中存在的 sheet，与离线模拟相同
+	structs := make(map[string]interface{})
+	for sheetName, ptr := range config.GetAllExcelConfig() {
+		for _, sheets := range allData {
+			if _, ok := sheets[sheetName]; ok {
+				structs[sheetName] = ptr
+			}
+		}
+	}
+	if err = r.ReadSheetToStruct(allData, structs); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// replayLog 按行拼接回放日志
+type replayLog struct {
+	t     *testing.T
+	lines []string
+}
+
+func (l *replayLog) add(typ, key string, v any) {
+	e := map[string]any{"type": typ}
+	if key != "" {
+		e[key] = v
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		l.t.Fatal(err)
+	}
+	l.lines = append(l.lines, string(data))
+}
+
+func (l *replayLog) bet(userId uint64, gold int64) {
+	l.add("action", "action", map[string]any{"UserId": userId, "Operation": logic.PlayerOpBet, "GoldValue": gold})
+}
+
+func (l *replayLog) replay() *logic.ReplayResult {
+	res, err := logic.Replay(strings.NewReader(strings.Join(l.lines, "\n")))
+	if err != nil {
+		l.t.Fatal(err)
+	}
+	return res
+}
+
+// abilityItem 有能力配置的道具
+func abilityItem(t *testing.T) int {
+	for _, item := range config.GetAllItemConfig() {
+		if item.Ability > 0 && config.GetAbilityConfigById(item.Ability) != nil {
+			return item.Id
+		}
+	}
+	t.Skip("no item with ability")
+	return 0
+}
+
+// 同一日志多次回放结算一致，记录结算后 Match
+func TestReplayDeterminism(t *testing.T) {
+	cfg := config.GetRoomConfigByRoomId(1)
+	if cfg == nil {
+		t.Fatal("room config 1 not found")
+	}
+	itemId := abilityItem(t)
+
+	l := &replayLog{t: t}
+	l.add("create", "create", map[string]any{"RoomId": 1, "Seed": 7, "Config": cfg})
+	for userId := uint64(1); userId <= uint64(cfg.CapacityLimit); userId++ {
+		l.add("join", "join", map[string]any{"UserId": userId, "ChoiceItemMap": map[int]int64{itemId: 1}})
+	}
+	// 道具提示使用房间随机数，回放时需与记录一致
+	l.add("action", "action", map[string]any{"UserId": 1, "Operation": logic.PlayerOpUseItem, "ItemId": itemId, "Consumed": true})
+	for round := int64(1); round <= int64(cfg.RoundLimit); round++ {
+		for userId := uint64(1); userId <= uint64(cfg.CapacityLimit); userId++ {
+			l.bet(userId, round*100+int64(userId)*10)
+		}
+	}
+
+	first := l.replay()
+	if first.Replayed == nil {
+		t.Fatal("no settlement")
+	}
+	if first.Finished || first.Match() {
+		t.Fatal("unsettled log must not match")
+	}
+	second := l.replay()
+	if !proto.Equal(first.Replayed, second.Replayed) {
+		t.Fatalf("replay differs: %v, %v", first.Replayed, second.Replayed)
+	}
+
+	settle, err := protojson.Marshal(first.Replayed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.add("settle", "settle", json.RawMessage(settle))
+	if res := l.replay(); !res.Match() {
+		t.Fatalf("recorded %v, replayed %v", res.Recorded, res.Replayed)
+	}
+}
+
+// 从回合中途的检查点恢复，恢复前的出价仍然有效
+func TestReplayRestore(t *testing.T) {
+	cfg := config.GetRoomConfigByRoomId(1)
+	if cfg == nil {
+		t.Fatal("room config 1 not found")
+	}
+	itemId := abilityItem(t)
+
+	src := &rand.PCGSource{}
+	src.Seed(7)
+	rng, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	players := make([]map[string]any, 0, cfg.CapacityLimit)
+	for userId := uint64(1); userId <= uint64(cfg.CapacityLimit); userId++ {
+		players = append(players, map[string]any{
+			"Player":        map[string]any{"UserId": userId},
+			"ChoiceItemMap": map[int]int64{itemId: 1},
+		})
+	}
+	players[0]["UserItemMap"] = map[int]int8{itemId: 1} // 回合中已使用并扣除
+
+	l := &replayLog{t: t}
+	l.add("restore", "restore", map[string]any{
+		"RoomId":   3,
+		"RoomType": cfg.RoomType,
+		"Seed":     7,
+		"Rng":      rng,
+		"Config":   cfg,
+		"Consume":  cfg.Consume,
+		"Players":  players,
+		"Rounds": []map[string]any{{
+			"RoundIndex": 1,
+			"Op":         []map[string]any{{"UserId": 1, "GoldValue": 500, "Operation": logic.PlayerOpBet, "IsBet": true, "ItemId": itemId}},
+		}},
+		"GridInfo": []any{},
+	})
+	// 第一回合其余玩家出价，恢复的出价保留时本回合结束
+	l.bet(2, 100)
+	l.bet(3, 200)
+	l.bet(4, 300)
+	// 第二回合差距足够大，提前结束
+	l.bet(1, 300)
+	l.bet(2, 2000)
+	l.bet(3, 300)
+	l.bet(4, 200)
+
+	res := l.replay()
+	if res.RoomId != 3 {
+		t.Fatalf("room id %d", res.RoomId)
+	}
+	s := res.Replayed
+	if s == nil {
+		t.Fatal("room not settled, restored bet lost")
+	}
+	if s.PlayerInfo.UserId != 2 || s.Expenses.Count != 2000 {
+		t.Fatalf("winner %d bid %d, want 2 bid 2000", s.PlayerInfo.UserId, s.Expenses.Count)
+	}
+}
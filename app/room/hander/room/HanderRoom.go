@@ -10,6 +10,7 @@ import (
 	"gameServer/pkg/logger/log2"
 	"gameServer/protobuf/pbGo"
 	"gameServer/service/common"
+	"math"
 
 	"go.uber.org/zap"
 )
@@ -18,49 +19,74 @@ type HandlerRoom struct { //必须大写,必须使用指针
 }
 
 // 开始匹配 1001
-func (h *HandlerRoom) StartMatchHandler(_ context.Context, player *common.Player, req *pbGo.StartMatchReq, resp *pbGo.StartMatchResp) *common.ErrorInfo {
-	userId := player.UserId
+func (h *HandlerRoom) StartMatchHandler(ctx context.Context, player *common.Player, req *pbGo.StartMatchReq, resp *pbGo.StartMatchResp) *common.ErrorInfo {
 	// 1. 检查是否已在匹配中
-	roomConfig := config.GetRoomConfigByRoomId(req.RoomType)
-	if roomConfig == nil {
-		log2.Get().Error("[StartMatchHandler] GetRoomConfigByRoomId false ", zap.Any("UserId", userId))
+	roomConfig, errInfo := verifyEntry(ctx, player.UserId, req.RoomType)
+	if errInfo != nil {
+		return errInfo
+	}
+	// 立马响应条件满足
+	//resp = &pbGo.StartMatchResp{}
+	// 进入匹配，按积分撮合
+	info := newPlayerInfo(player, req.HeroId, req.ItemInfoList)
+	rating, err := logic.LoadRating(ctx, player.UserId, roomConfig.RoomType)
+	if err != nil {
 		return &common.ErrorInfo{
+			Code: errorCode.ErrorCode_DBError,
+		}
+	}
+	info.Rating = rating
+	if code := logic.StartMatch(ctx, info, roomConfig); code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	return nil
+}
+
+// verifyEntry 房间配置和入场消耗，道具在开始时扣除
+func verifyEntry(ctx context.Context, userId uint64, roomType uint32) (*config.Room, *common.ErrorInfo) {
+	roomConfig := config.GetRoomConfigByRoomId(roomType)
+	if roomConfig == nil {
+		log2.Get().Error("[verifyEntry] GetRoomConfigByRoomId false ", zap.Any("UserId", userId))
+		return nil, &common.ErrorInfo{
 			Code: errorCode.ErrorCode_GetConfigFailed,
 		}
 	}
 	consume := roomConfig.Consume
 	if consume == nil {
-		log2.Get().Warn("[StartMatchHandler] GetRoomConfigByRoomId consume is null  ", zap.Any("UserId", userId))
-		return &common.ErrorInfo{
+		log2.Get().Warn("[verifyEntry] GetRoomConfigByRoomId consume is null  ", zap.Any("UserId", userId))
+		return nil, &common.ErrorInfo{
 			Code: errorCode.ErrorCode_GetConfigFailed,
 		}
 	}
 	//2. 检查是否满足条件
-	ok := items.VerifyItem(userId, consume)
+	ok := items.VerifyItem(ctx, userId, consume)
 	if !ok {
-		return &common.ErrorInfo{
+		return nil, &common.ErrorInfo{
 			Code: errorCode.ErrorCode_ItemNotEnough,
 		}
 	}
-	// 立马响应条件满足
-	//resp = &pbGo.StartMatchResp{}
+	return roomConfig, nil
+}
+
+// newPlayerInfo 进入房间的玩家，选择的人物和道具
+func newPlayerInfo(player *common.Player, heroId uint32, itemInfoList []*pbGo.ItemInfo) *logic.PlayerInfo {
 	itemMap := make(map[int]int64)
-	for _, info := range req.ItemInfoList {
+	for _, info := range itemInfoList {
 		itemMap[int(info.ItemId)] = info.Count
 	}
-	// 进入匹配
-	logic.StartMatch(&logic.PlayerInfo{
-		HeroId:        int(req.HeroId),
+	return &logic.PlayerInfo{
+		HeroId:        int(heroId),
 		ChoiceItemMap: itemMap,
 		Player:        player,
 		UserItemMap:   make(map[int]int8),
-	}, roomConfig)
-	return nil
+	}
 }
 
 // 取消匹配 1003
-func (h *HandlerRoom) CancelMatchHandler(_ context.Context, player *common.Player, _ *pbGo.CancelMatchReq, resp *pbGo.CancelMatchResp) *common.ErrorInfo {
-	logic.CancelMatch(player.UserId)
+func (h *HandlerRoom) CancelMatchHandler(ctx context.Context, player *common.Player, _ *pbGo.CancelMatchReq, resp *pbGo.CancelMatchResp) *common.ErrorInfo {
+	logic.CancelMatch(ctx, player.UserId)
 	//if !ok {
 	//	return &common.ErrorInfo{
 	//		Code: errorCode.ErrorCode_NotJoinRoom,
@@ -70,7 +96,7 @@ func (h *HandlerRoom) CancelMatchHandler(_ context.Context, player *common.Playe
 }
 
 // 竞拍 1004
-func (h *HandlerRoom) BetHandler(_ context.Context, player *common.Player, req *pbGo.BetReq, resp *pbGo.BetResp) *common.ErrorInfo {
+func (h *HandlerRoom) BetHandler(ctx context.Context, player *common.Player, req *pbGo.BetReq, resp *pbGo.BetResp) *common.ErrorInfo {
 	count := int64(0)
 	// 如果在房间中
 	for _, info := range req.BetInfo {
@@ -78,7 +104,7 @@ func (h *HandlerRoom) BetHandler(_ context.Context, player *common.Player, req *
 			count = info.Count
 		}
 	}
-	code := logic.BetOp(player.UserId, count)
+	code := logic.BetOp(ctx, player.UserId, count)
 	if code > 0 {
 		return &common.ErrorInfo{
 			Code: code,
@@ -94,8 +120,8 @@ func (h *HandlerRoom) BetHandler(_ context.Context, player *common.Player, req *
 }
 
 // 使用道具 1006
-func (h *HandlerRoom) UseItemHandler(_ context.Context, player *common.Player, req *pbGo.UseItemReq, resp *pbGo.UseItemResp) *common.ErrorInfo {
-	respData, code := logic.UserItem(player.UserId, int(req.Item.ItemId), req.Item.Count)
+func (h *HandlerRoom) UseItemHandler(ctx context.Context, player *common.Player, req *pbGo.UseItemReq, resp *pbGo.UseItemResp) *common.ErrorInfo {
+	respData, code := logic.UserItem(ctx, player.UserId, int(req.Item.ItemId), req.Item.Count)
 	if code != 0 {
 		return &common.ErrorInfo{
 			Code: code,
@@ -106,3 +132,152 @@ func (h *HandlerRoom) UseItemHandler(_ context.Context, player *common.Player, r
 	resp.ItemInfoList = respData.ItemInfoList
 	return nil
 }
+
+// 房间快照 1007，断线重连后重绘房间
+func (h *HandlerRoom) GetRoomSnapshotHandler(ctx context.Context, player *common.Player, _ *pbGo.GetRoomSnapshotReq, resp *pbGo.GetRoomSnapshotResp) *common.ErrorInfo {
+	data, code := logic.GetRoomSnapshot(ctx, player)
+	if code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	resp.RoomType = data.RoomType
+	resp.RoundIndex = data.RoundIndex
+	resp.EndTimeOut = data.EndTimeOut
+	resp.ScreenInfo = data.ScreenInfo
+	resp.PlayerInfoList = data.PlayerInfoList
+	resp.BetHistory = data.BetHistory
+	resp.BetUserIdList = data.BetUserIdList
+	return nil
+}
+
+// 可观战的房间列表 1008
+func (h *HandlerRoom) SpectateRoomListHandler(ctx context.Context, _ *common.Player, req *pbGo.SpectateRoomListReq, resp *pbGo.SpectateRoomListResp) *common.ErrorInfo {
+	resp.RoomList = logic.SpectateRoomList(ctx, req.RoomType)
+	return nil
+}
+
+// 加入观战 1009，返回房间快照，之后与玩家一样收到回合推送
+func (h *HandlerRoom) JoinSpectateHandler(ctx context.Context, player *common.Player, req *pbGo.JoinSpectateReq, resp *pbGo.JoinSpectateResp) *common.ErrorInfo {
+	data, code := logic.JoinSpectate(ctx, player, req.RoomId, req.ServerId, req.UserId)
+	if code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	resp.Snapshot = data
+	return nil
+}
+
+// 退出观战 1010
+func (h *HandlerRoom) LeaveSpectateHandler(_ context.Context, player *common.Player, _ *pbGo.LeaveSpectateReq, _ *pbGo.LeaveSpectateResp) *common.ErrorInfo {
+	logic.LeaveSpectate(player.UserId)
+	return nil
+}
+
+// 创建私人房间 1011
+func (h *HandlerRoom) CreatePrivateRoomHandler(ctx context.Context, player *common.Player, req *pbGo.CreatePrivateRoomReq, resp *pbGo.CreatePrivateRoomResp) *common.ErrorInfo {
+	roomConfig, errInfo := verifyEntry(ctx, player.UserId, req.RoomType)
+	if errInfo != nil {
+		return errInfo
+	}
+	if req.RoundLimit > math.MaxUint8 {
+		return &common.ErrorInfo{
+			Code: errorCode.ErrorCode_RuleOutOfRange,
+		}
+	}
+	info, code := logic.CreatePrivateRoom(ctx, newPlayerInfo(player, req.HeroId, req.ItemInfoList), roomConfig,
+		uint8(req.RoundLimit), int(req.Timeout), req.RobotFill)
+	if code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	resp.Room = info
+	return nil
+}
+
+// 邀请码加入私人房间 1012
+func (h *HandlerRoom) JoinPrivateRoomHandler(ctx context.Context, player *common.Player, req *pbGo.JoinPrivateRoomReq, resp *pbGo.JoinPrivateRoomResp) *common.ErrorInfo {
+	roomType, code := logic.PrivateRoomType(ctx, player, req.Code)
+	if code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	if _, errInfo := verifyEntry(ctx, player.UserId, roomType); errInfo != nil {
+		return errInfo
+	}
+	info, code := logic.JoinPrivateRoom(ctx, newPlayerInfo(player, req.HeroId, req.ItemInfoList), req.Code)
+	if code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	resp.Room = info
+	return nil
+}
+
+// 房主开始私人房间 1013
+func (h *HandlerRoom) StartPrivateRoomHandler(ctx context.Context, player *common.Player, _ *pbGo.StartPrivateRoomReq, _ *pbGo.StartPrivateRoomResp) *common.ErrorInfo {
+	if code := logic.StartPrivateRoom(ctx, player.UserId); code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	return nil
+}
+
+// 离开私人房间 1014
+func (h *HandlerRoom) LeavePrivateRoomHandler(ctx context.Context, player *common.Player, _ *pbGo.LeavePrivateRoomReq, _ *pbGo.LeavePrivateRoomResp) *common.ErrorInfo {
+	if code := logic.LeavePrivateRoom(ctx, player.UserId); code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	return nil
+}
+
+// 创建队伍 1016
+func (h *HandlerRoom) CreatePartyHandler(ctx context.Context, player *common.Player, _ *pbGo.CreatePartyReq, resp *pbGo.CreatePartyResp) *common.ErrorInfo {
+	info, code := logic.CreateParty(ctx, player.UserId)
+	if code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	resp.Party = info
+	return nil
+}
+
+// 邀请组队 1017
+func (h *HandlerRoom) InvitePartyHandler(ctx context.Context, player *common.Player, req *pbGo.InvitePartyReq, _ *pbGo.InvitePartyResp) *common.ErrorInfo {
+	if code := logic.InviteParty(ctx, player.UserId, req.UserId); code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	return nil
+}
+
+// 接受组队邀请 1018
+func (h *HandlerRoom) AcceptPartyHandler(ctx context.Context, player *common.Player, req *pbGo.AcceptPartyReq, resp *pbGo.AcceptPartyResp) *common.ErrorInfo {
+	info, code := logic.AcceptParty(ctx, player.UserId, req.PartyId)
+	if code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	resp.Party = info
+	return nil
+}
+
+// 离开队伍 1019
+func (h *HandlerRoom) LeavePartyHandler(ctx context.Context, player *common.Player, _ *pbGo.LeavePartyReq, _ *pbGo.LeavePartyResp) *common.ErrorInfo {
+	if code := logic.LeaveParty(ctx, player.UserId); code != 0 {
+		return &common.ErrorInfo{
+			Code: code,
+		}
+	}
+	return nil
+}
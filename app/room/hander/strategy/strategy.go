@@ -0,0 +1,107 @@
+package strategy
+
+import (
+	"sort"
+
+	"golang.org/x/exp/rand"
+)
+
+// 策略名，在机器人配置的 strategy 列中填写
+const (
+	NameRandom     = "random"     // 随机出价，不弃权、不使用道具
+	NameValue      = "value"      // 按估值出价
+	NameCautious   = "cautious"   // 按估值出价，压价更多
+	NameAggressive = "aggressive" // 按估值出价，不压价
+
+	// DefaultName 未配置或配置的策略不存在时使用
+	DefaultName = NameValue
+)
+
+// Candidate 房间可能出现的一个藏品，按房间配置的藏品集合生成，重复的藏品出现的概率更高
+type Candidate struct {
+	ItemId  int
+	Price   int64  // 金币价值
+	Area    uint32 // 占用格子数
+	Quality int8   // 品质
+}
+
+// Item 机器人已看到的一个藏品，未看到的部分为 0
+type Item struct {
+	ItemId  int    // 全显示后的藏品 id
+	Area    uint32 // 显示轮廓后的格子数
+	Quality int8   // 显示品质后的品质
+}
+
+// View 机器人做决定时能看到的信息，只包含该机器人已获得的提示
+type View struct {
+	RoundIndex int8  // 当前回合，从 1 开始
+	RoundLimit uint8 // 最大回合数
+	ItemSum    int   // 藏品数量
+
+	Candidates []Candidate // 可能出现的藏品
+	Items      []Item      // 已看到的藏品
+	Totals     []int64     // 价值提示给出的部分藏品的总价值
+
+	LastBids    []int64 // 上一回合其他玩家的出价
+	ChoiceItems []int   // 还可以使用的道具
+	ItemUsed    bool    // 本回合已使用道具
+}
+
+// Decision 机器人一个回合的操作
+//
+// UseItem 大于 0 时先使用道具，再按使用后的信息重新决定出价
+type Decision struct {
+	UseItem int
+	Abstain bool
+	Bet     int64
+}
+
+// Strategy 机器人策略，只按 View 和随机数决定，不读取房间状态，可以离线模拟
+type Strategy interface {
+	Decide(view *View, rng *rand.Rand) Decision
+}
+
+// Option 机器人配置中与策略有关的参数
+type Option struct {
+	Vibration uint8 // 出价的波动百分比
+}
+
+// Factory 按机器人配置创建策略
+type Factory func(opt Option) Strategy
+
+var registry = map[string]Factory{
+	NameRandom: func(Option) Strategy { return &Random{Max: 20000} },
+	NameValue: func(opt Option) Strategy {
+		return &Value{Shade: 10, Vibration: opt.Vibration, Explore: 50}
+	},
+	NameCautious: func(opt Option) Strategy {
+		return &Value{Shade: 25, Vibration: opt.Vibration, Explore: 30}
+	},
+	NameAggressive: func(opt Option) Strategy {
+		return &Value{Shade: 0, Vibration: opt.Vibration, Explore: 70}
+	},
+}
+
+// Register 注册策略，同名时替换，在启动时调用
+func Register(name string, f Factory) {
+	registry[name] = f
+}
+
+// Names 已注册的策略名，升序
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New 按名字创建策略，名字为空或未注册时使用 DefaultName
+func New(name string, opt Option) Strategy {
+	f, ok := registry[name]
+	if !ok {
+		f = registry[DefaultName]
+	}
+	return f(opt)
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"gameServer/app/room/hander/strategy"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+var candidates = []strategy.Candidate{
+	{ItemId: 1, Price: 500, Area: 1, Quality: 1},
+	{ItemId: 2, Price: 800, Area: 2, Quality: 1},
+	{ItemId: 3, Price: 1500, Area: 2, Quality: 2},
+	{ItemId: 4, Price: 3000, Area: 4, Quality: 3},
+	{ItemId: 5, Price: 6000, Area: 6, Quality: 4},
+}
+
+func TestEstimate(t *testing.T) {
+	view := &strategy.View{ItemSum: 3, Candidates: candidates}
+	value, unknown := strategy.Estimate(view)
+	if value != 3*2360 || unknown != 100 {
+		t.Fatalf("nothing seen: value %d unknown %d", value, unknown)
+	}
+
+	view.Items = []strategy.Item{{ItemId: 5}, {Area: 2}, {Area: 2, Quality: 2}}
+	value, unknown = strategy.Estimate(view)
+	if value != 6000+1150+1500 || unknown != 66 {
+		t.Fatalf("all seen: value %d unknown %d", value, unknown)
+	}
+
+	// 总价值提示作为下限
+	view.Totals = []int64{10000}
+	if value, _ = strategy.Estimate(view); value != 10000 {
+		t.Fatalf("total hint: value %d", value)
+	}
+}
+
+func TestValueDecide(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := strategy.New(strategy.NameValue, strategy.Option{Vibration: 10})
+
+	view := &strategy.View{ItemSum: 2, Candidates: candidates, ChoiceItems: []int{7}}
+	if d := s.Decide(view, rng); d.UseItem != 7 {
+		t.Fatalf("should use item when nothing seen: %+v", d)
+	}
+
+	view.ItemUsed = true
+	view.Items = []strategy.Item{{ItemId: 4}, {ItemId: 5}}
+	for range 100 {
+		d := s.Decide(view, rng)
+		if d.Abstain || d.Bet <= 0 || d.Bet > 9000 {
+			t.Fatalf("bet out of range: %+v", d)
+		}
+	}
+
+	view.LastBids = []int64{10000}
+	if d := s.Decide(view, rng); !d.Abstain {
+		t.Fatalf("should abstain when outbid: %+v", d)
+	}
+}
+
+// 模拟房间：每回合向估值机器人多显示一部分藏品，最后一回合出价最高的获胜
+func simulate(rng *rand.Rand, rooms int, players []strategy.Strategy) (wins []int, profits []int64) {
+	const (
+		itemSum    = 8
+		roundLimit = 4
+	)
+	wins = make([]int, len(players))
+	profits = make([]int64, len(players))
+	for range rooms {
+		items := make([]strategy.Candidate, itemSum)
+		var value int64
+		for i := range items {
+			items[i] = candidates[rng.Intn(len(candidates))]
+			value += items[i].Price
+		}
+		bids := make([]int64, len(players))
+		abstained := make([]bool, len(players))
+		for round := 1; round <= roundLimit; round++ {
+			seen := make([]strategy.Item, 0, itemSum)
+			for _, item := range items[:itemSum*round/roundLimit] {
+				seen = append(seen, strategy.Item{ItemId: item.ItemId})
+			}
+			last := bids
+			bids = make([]int64, len(players))
+			for i, s := range players {
+				if abstained[i] {
+					continue
+				}
+				view := &strategy.View{
+					RoundIndex: int8(round),
+					RoundLimit: roundLimit,
+					ItemSum:    itemSum,
+					Candidates: candidates,
+					Items:      seen,
+					ItemUsed:   true,
+				}
+				for j, bid := range last {
+					if j != i && bid > 0 {
+						view.LastBids = append(view.LastBids, bid)
+					}
+				}
+				d := s.Decide(view, rng)
+				if d.Abstain {
+					abstained[i] = true
+					continue
+				}
+				bids[i] = d.Bet
+			}
+		}
+		winner := -1
+		for i, bid := range bids {
+			if bid > 0 && (winner < 0 || bid > bids[winner]) {
+				winner = i
+			}
+		}
+		if winner >= 0 {
+			wins[winner]++
+			profits[winner] += value - bids[winner]
+		}
+	}
+	return wins, profits
+}
+
+func TestSimulatedRooms(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	players := []strategy.Strategy{
+		strategy.New(strategy.NameValue, strategy.Option{Vibration: 10}),
+		strategy.New(strategy.NameRandom, strategy.Option{}),
+		strategy.New(strategy.NameCautious, strategy.Option{Vibration: 10}),
+		strategy.New(strategy.NameAggressive, strategy.Option{Vibration: 10}),
+	}
+	wins, profits := simulate(rng, 2000, players)
+	t.Logf("wins %v profits %v", wins, profits)
+	for i, name := range []string{"value", "random", "cautious", "aggressive"} {
+		if name != "random" && profits[i] < 0 {
+			t.Errorf("%s loses money: %d in %d wins", name, profits[i], wins[i])
+		}
+	}
+}
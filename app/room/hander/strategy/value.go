@@ -0,0 +1,124 @@
+package strategy
+
+import (
+	"golang.org/x/exp/rand"
+)
+
+// Random 在 [1, Max] 中随机出价，不弃权、不使用道具，与早期的机器人相同
+type Random struct {
+	Max int64
+}
+
+func (s *Random) Decide(_ *View, rng *rand.Rand) Decision {
+	if s.Max <= 0 {
+		return Decision{Abstain: true}
+	}
+	return Decision{Bet: rng.Int63n(s.Max) + 1}
+}
+
+// Value 按已看到的信息估算总价值出价
+//
+// 按未确认的藏品比例压低估值作为最高价，出价为最高价压低 Shade% 后再上下波动 Vibration%，不超过最高价；
+// 未确认的藏品超过 Explore% 且还有道具时先使用一个道具，不超过时上一回合的出价比最高价高出 Vibration% 以上则弃权
+type Value struct {
+	Shade     uint8
+	Vibration uint8
+	Explore   uint8
+}
+
+func (s *Value) Decide(view *View, rng *rand.Rand) Decision {
+	estimate, unknown := Estimate(view)
+	if !view.ItemUsed && len(view.ChoiceItems) > 0 && unknown > int(s.Explore) {
+		return Decision{UseItem: view.ChoiceItems[rng.Intn(len(view.ChoiceItems))]}
+	}
+
+	var highest int64
+	for _, bid := range view.LastBids {
+		highest = max(highest, bid)
+	}
+	// 愿意出的最高价，未确认的藏品越多越低，全部未确认时为估值的四分之三
+	limit := estimate * int64(400-unknown) / 400
+	if limit <= 0 {
+		return Decision{Abstain: true}
+	}
+	// 已确认足够多的藏品，估值较可靠时才因为出价过高弃权，弃权后不能再出价
+	if unknown <= int(s.Explore) && highest > limit+limit*int64(s.Vibration)/100 {
+		return Decision{Abstain: true}
+	}
+
+	bid := limit * int64(100-min(s.Shade, 100)) / 100
+	if v := int64(s.Vibration); v > 0 {
+		bid += bid * (rng.Int63n(2*v+1) - v) / 100
+	}
+	// 低于上一回合的最高价时跟到刚好超过，仍不超过最高价
+	bid = max(bid, highest+1)
+	return Decision{Bet: min(bid, limit)}
+}
+
+// Estimate 估算所有藏品的总价值和未确认价值（未全显示）的藏品百分比
+//
+// 全显示的藏品取实际价值，只看到轮廓或品质的按同面积、同品质的候选藏品的平均价值，
+// 未看到的按所有候选藏品的平均价值；价值提示给出的总价值作为下限
+func Estimate(view *View) (value int64, unknown int) {
+	all := average(view.Candidates, Item{})
+	sum := 0.0
+	known := 0
+	for _, item := range view.Items {
+		sum += itemValue(view.Candidates, item, all)
+		if item.ItemId > 0 {
+			known++
+		}
+	}
+	sum += float64(max(view.ItemSum-len(view.Items), 0)) * all
+
+	value = int64(sum + 0.5)
+	for _, total := range view.Totals {
+		value = max(value, total)
+	}
+	if n := max(view.ItemSum, len(view.Items)); n > 0 {
+		unknown = (n - known) * 100 / n
+	}
+	return value, unknown
+}
+
+// itemValue 一个已看到的藏品的估值，没有符合的候选藏品时放宽品质条件
+func itemValue(candidates []Candidate, item Item, all float64) float64 {
+	if item.ItemId > 0 {
+		for _, c := range candidates {
+			if c.ItemId == item.ItemId {
+				return float64(c.Price)
+			}
+		}
+	}
+	if v := average(candidates, item); v > 0 {
+		return v
+	}
+	if item.Quality > 0 {
+		if v := average(candidates, Item{Area: item.Area}); v > 0 {
+			return v
+		}
+	}
+	return all
+}
+
+// average 面积和品质符合的候选藏品的平均价值，为 0 的条件不限制
+func average(candidates []Candidate, item Item) float64 {
+	var (
+		sum int64
+		n   int
+	)
+	for _, c := range candidates {
+		if item.Area > 0 && c.Area != item.Area {
+			continue
+		}
+		if item.Quality > 0 && c.Quality != item.Quality {
+			continue
+		}
+		sum += c.Price
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return float64(sum) / float64(n)
+}
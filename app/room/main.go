@@ -2,6 +2,7 @@ package main
 
 import (
 	"gameServer/app/room/hander"
+	"gameServer/app/room/hander/logic"
 	"gameServer/app/room/hander/room"
 	"gameServer/common/db/heros"
 	"gameServer/common/db/items"
@@ -93,7 +94,8 @@ func main() {
 	}
 	// 添加rpcx 服务
 	nodeServer := node.NewServer()
-	err = nodeServer.Start(f)
+	// rpc 客户端就绪后恢复或退还重启前未结束的房间，再启动跨节点匹配池，两者都需要向网关推送
+	err = nodeServer.Start(f, logic.Recover, logic.StartPool)
 	if err != nil {
 		panic(err)
 	}
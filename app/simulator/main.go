@@ -0,0 +1,245 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gameServer/app/room/hander/config"
+	"gameServer/app/room/hander/logic"
+	"gameServer/app/room/hander/normalDistribution"
+	"gameServer/app/room/hander/strategy"
+	config2 "gameServer/common/config"
+	"gameServer/pkg/excel/reader"
+	"gameServer/pkg/logger/log2"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/rand"
+)
+
+// 房间经济离线模拟：按表格配置模拟大量全是机器人的房间，统计利润、回合数、提前结束和金币产出消耗
+//
+//	go run ./app/simulator -n 10000 -strategies value,random,cautious,aggressive -format csv -out sim.csv
+//
+// 需在包含 excels 目录的路径下运行，或通过 -excels 指定；房间配置了 roomItemAllocation 时按正态分布抽取藏品，
+// 此时 normalDistribution 使用全局随机数，相同种子的结果不完全一致
+func main() {
+	var (
+		excels     = flag.String("excels", "./excels", "表格目录")
+		n          = flag.Int("n", 10000, "每种房间模拟的房间数")
+		roomType   = flag.Uint("room", 0, "只模拟该类型的房间，0 为所有")
+		strategies = flag.String("strategies", "", "每个座位的策略，逗号分隔、依次循环；为空时使用机器人配置的策略")
+		seed       = flag.Uint64("seed", 1, "随机种子")
+		alloc      = flag.Bool("alloc", true, "房间配置了 roomItemAllocation 时按正态分布抽取藏品")
+		format     = flag.String("format", "json", "输出格式 json 或 csv")
+		out        = flag.String("out", "", "输出文件，为空时输出到标准输出")
+		workers    = flag.Int("workers", runtime.NumCPU(), "并发模拟的协程数")
+		logPath    = flag.String("logPath", "./logs/", "日志路径")
+	)
+	flag.Parse()
+	if *n <= 0 || *workers <= 0 || (*format != "json" && *format != "csv") {
+		flag.Usage()
+		os.Exit(2)
+	}
+	names, err := parseStrategies(*strategies)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	log2.Init(log2.Config{Level: zapcore.ErrorLevel, LogDir: *logPath})
+	if err = loadExcels(*excels); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	rand.Seed(*seed)
+
+	reports := make([]*RoomReport, 0)
+	for _, cfg := range config.GetAllRoomConfig() {
+		if *roomType > 0 && cfg.RoomType != uint32(*roomType) {
+			continue
+		}
+		if cfg.CapacityLimit <= 0 || len(cfg.ItemList) == 0 {
+			fmt.Fprintf(os.Stderr, "room %d skipped: no seats or items\n", cfg.RoomType)
+			continue
+		}
+		sim := newSimulation(cfg, names, *seed, *alloc)
+		reports = append(reports, sim.run(*n, *workers))
+	}
+	if len(reports) == 0 {
+		fmt.Fprintln(os.Stderr, "no room simulated")
+		os.Exit(1)
+	}
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		w = file
+	}
+	if *format == "csv" {
+		err = writeCSV(w, reports)
+	} else {
+		err = writeJSON(w, reports)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// parseStrategies 检查策略名都已注册
+func parseStrategies(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	names := strings.Split(s, ",")
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+		if !slices.Contains(strategy.Names(), names[i]) {
+			return nil, fmt.Errorf("unknown strategy %q, registered: %s", names[i], strings.Join(strategy.Names(), ","))
+		}
+	}
+	return names, nil
+}
+
+// loadExcels 读取表格配置，所有表格中都没有的 sheet 跳过
+func loadExcels(dir string) error {
+	r := reader.NewExcelReader(dir)
+	allData, err := r.ReadAllExcels()
+	if err != nil {
+		return err
+	}
+	if len(allData) == 0 {
+		return fmt.Errorf("no excel found in %s", dir)
+	}
+	structs := make(map[string]interface{})
+	for sheetName, ptr := range config.GetAllExcelConfig() {
+		found := true
+		for _, sheets := range allData {
+			if _, ok := sheets[sheetName]; !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			structs[sheetName] = ptr
+		} else {
+			fmt.Fprintf(os.Stderr, "sheet %s not found, skipped\n", sheetName)
+		}
+	}
+	if err = r.ReadSheetToStruct(allData, structs); err != nil {
+		return err
+	}
+	config.InitRoomItemAllocationConfig()
+	return nil
+}
+
+// simulation 一种房间的模拟
+type simulation struct {
+	cfg        *config.Room
+	strategies []string // 每个座位的策略，为空时使用机器人配置的策略
+	seed       uint64
+
+	dists      []normalDistribution.NormalDistribution // 藏品总价值的分布，为空时均匀抽取
+	candidates []*config2.Item                         // 房间可能出现的藏品
+}
+
+func newSimulation(cfg *config.Room, strategies []string, seed uint64, alloc bool) *simulation {
+	sim := &simulation{cfg: cfg, strategies: strategies, seed: seed}
+	if !alloc {
+		return sim
+	}
+	allocation := config.GetRoomItemAllocationConfigById(int(cfg.RoomType))
+	if allocation == nil {
+		return sim
+	}
+	for _, nd := range allocation.NDList() {
+		lower, upper := min(nd.Lower, nd.Upper), max(nd.Lower, nd.Upper)
+		if lower == upper {
+			upper++ // 三角分布需要非空区间
+		}
+		sim.dists = append(sim.dists, normalDistribution.NormalDistribution{P: nd.P, Lower: lower, Upper: upper})
+	}
+	for _, itemId := range cfg.ItemList {
+		if item := config.GetItemConfigById(itemId); item != nil {
+			sim.candidates = append(sim.candidates, item)
+		}
+	}
+	if len(sim.candidates) == 0 {
+		sim.dists = nil
+	}
+	return sim
+}
+
+// room 模拟第 i 个房间，返回结果和每个座位的策略名
+func (sim *simulation) room(i int) (*logic.SimulateResult, []string) {
+	seed := sim.seed ^ uint64(sim.cfg.RoomType)<<40 ^ uint64(i)
+	rng := rand.New(rand.NewSource(seed))
+
+	var (
+		seats = sim.cfg.CapacityLimit
+		opt   = logic.SimulateOption{Seed: seed, Robots: make([]*config.Robot, 0, seats)}
+		names = make([]string, 0, seats)
+	)
+	for seat := range seats {
+		robotConfig := &config.Robot{}
+		if l := len(sim.cfg.RobotList); l > 0 {
+			if c := config.GetRobotById(sim.cfg.RobotList[rng.Intn(l)]); c != nil {
+				copied := *c
+				robotConfig = &copied
+			}
+		}
+		if len(sim.strategies) > 0 {
+			robotConfig.Strategy = sim.strategies[seat%len(sim.strategies)]
+		}
+		if !slices.Contains(strategy.Names(), robotConfig.Strategy) {
+			robotConfig.Strategy = strategy.DefaultName
+		}
+		opt.Robots = append(opt.Robots, robotConfig)
+		names = append(names, robotConfig.Strategy)
+	}
+
+	if len(sim.dists) > 0 {
+		// SampleItems 会对候选藏品排序，每次使用副本
+		items := normalDistribution.SampleItems(sim.dists, slices.Clone(sim.candidates), int64(sim.cfg.ItemSum))
+		for _, item := range items {
+			opt.Items = append(opt.Items, item.Id)
+		}
+	}
+	return logic.Simulate(sim.cfg, opt), names
+}
+
+// run 并发模拟 n 个房间并汇总
+func (sim *simulation) run(n, workers int) *RoomReport {
+	var (
+		results = make([]*logic.SimulateResult, n)
+		names   = make([][]string, n)
+		next    = make(chan int)
+		wg      sync.WaitGroup
+	)
+	for range min(workers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], names[i] = sim.room(i)
+			}
+		}()
+	}
+	for i := range n {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return summarize(sim.cfg, len(sim.dists) > 0, results, names)
+}
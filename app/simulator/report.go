@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"gameServer/app/room/hander/config"
+	"gameServer/app/room/hander/logic"
+	"gameServer/common/constValue"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// Distribution 一组数值的分布
+type Distribution struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	Min   int64   `json:"min"`
+	P10   int64   `json:"p10"`
+	P25   int64   `json:"p25"`
+	P50   int64   `json:"p50"`
+	P75   int64   `json:"p75"`
+	P90   int64   `json:"p90"`
+	Max   int64   `json:"max"`
+}
+
+// Gold 金币产出和消耗，产出为获胜者得到的藏品价值，消耗为获胜者的出价和入场消耗
+type Gold struct {
+	Reward int64 `json:"reward"`
+	Bid    int64 `json:"bid"`
+	Entry  int64 `json:"entry"`
+	Net    int64 `json:"net"` // 产出减消耗，为正时金币增加
+}
+
+// StrategyReport 使用某个策略的座位的统计
+type StrategyReport struct {
+	Strategy  string       `json:"strategy"`
+	Seats     int          `json:"seats"`
+	Wins      int          `json:"wins"`
+	WinRate   float64      `json:"winRate"`
+	Profit    Distribution `json:"profit"` // 获胜时的利润
+	LossRate  float64      `json:"lossRate"`
+	Gold      Gold         `json:"gold"`
+	ItemsUsed int          `json:"itemsUsed"`
+}
+
+// RoomReport 一种房间的统计
+type RoomReport struct {
+	RoomType   uint32 `json:"roomType"`
+	Rooms      int    `json:"rooms"`
+	Allocation bool   `json:"allocation"` // 按 roomItemAllocation 的正态分布抽取藏品
+
+	Rounds    Distribution `json:"rounds"`
+	RoundHist map[int]int  `json:"roundHist"` // 回合数-房间数
+	EarlyRate float64      `json:"earlyRate"` // 提前结束的比例
+	NoBidRate float64      `json:"noBidRate"` // 没有人出价的比例
+
+	Value     Distribution `json:"value"`  // 藏品总价值
+	Profit    Distribution `json:"profit"` // 获胜者的利润
+	LossRate  float64      `json:"lossRate"`
+	Gold      Gold         `json:"gold"`
+	ItemsUsed int          `json:"itemsUsed"`
+
+	Strategies []*StrategyReport `json:"strategies"`
+}
+
+func summarize(cfg *config.Room, allocation bool, results []*logic.SimulateResult, names [][]string) *RoomReport {
+	var (
+		report = &RoomReport{
+			RoomType:   cfg.RoomType,
+			Rooms:      len(results),
+			Allocation: allocation,
+			RoundHist:  make(map[int]int),
+		}
+		entry      = cfg.Consume[constValue.GoldItemId]
+		rounds     = make([]int64, 0, len(results))
+		values     = make([]int64, 0, len(results))
+		profits    = make([]int64, 0, len(results))
+		early      int
+		noBid      int
+		byStrategy = make(map[string]*StrategyReport)
+		stProfits  = make(map[string][]int64)
+	)
+	for i, res := range results {
+		rounds = append(rounds, int64(res.Rounds))
+		values = append(values, res.Value)
+		report.RoundHist[res.Rounds]++
+		if res.EarlyFinish {
+			early++
+		}
+		report.Gold.Entry += entry * int64(len(names[i]))
+		for seat, name := range names[i] {
+			st := byStrategy[name]
+			if st == nil {
+				st = &StrategyReport{Strategy: name}
+				byStrategy[name] = st
+			}
+			st.Seats++
+			st.Gold.Entry += entry
+			st.ItemsUsed += res.ItemsUsed[seat]
+			report.ItemsUsed += res.ItemsUsed[seat]
+		}
+		if res.Winner < 0 {
+			noBid++
+			continue
+		}
+		profit := res.Value - res.Bid
+		profits = append(profits, profit)
+		report.Gold.Reward += res.Value
+		report.Gold.Bid += res.Bid
+
+		name := names[i][res.Winner]
+		st := byStrategy[name]
+		st.Wins++
+		st.Gold.Reward += res.Value
+		st.Gold.Bid += res.Bid
+		stProfits[name] = append(stProfits[name], profit)
+	}
+
+	report.Rounds = distribution(rounds)
+	report.Value = distribution(values)
+	report.Profit = distribution(profits)
+	report.LossRate = lossRate(profits)
+	report.EarlyRate = rate(early, len(results))
+	report.NoBidRate = rate(noBid, len(results))
+	report.Gold.Net = report.Gold.Reward - report.Gold.Bid - report.Gold.Entry
+
+	for name, st := range byStrategy {
+		st.WinRate = rate(st.Wins, st.Seats)
+		st.Profit = distribution(stProfits[name])
+		st.LossRate = lossRate(stProfits[name])
+		st.Gold.Net = st.Gold.Reward - st.Gold.Bid - st.Gold.Entry
+		report.Strategies = append(report.Strategies, st)
+	}
+	slices.SortFunc(report.Strategies, func(a, b *StrategyReport) int {
+		if a.Strategy < b.Strategy {
+			return -1
+		}
+		if a.Strategy > b.Strategy {
+			return 1
+		}
+		return 0
+	})
+	return report
+}
+
+func distribution(values []int64) Distribution {
+	d := Distribution{Count: len(values)}
+	if len(values) == 0 {
+		return d
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	sum := 0.0
+	for _, v := range sorted {
+		sum += float64(v)
+	}
+	at := func(p float64) int64 {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+	d.Mean = sum / float64(len(sorted))
+	d.Min, d.Max = sorted[0], sorted[len(sorted)-1]
+	d.P10, d.P25, d.P50, d.P75, d.P90 = at(0.1), at(0.25), at(0.5), at(0.75), at(0.9)
+	return d
+}
+
+func lossRate(profits []int64) float64 {
+	loss := 0
+	for _, p := range profits {
+		if p < 0 {
+			loss++
+		}
+	}
+	return rate(loss, len(profits))
+}
+
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+func writeJSON(w io.Writer, reports []*RoomReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
+
+// writeCSV 每种房间一行汇总（strategy 为 all），之后每个策略一行；房间级的列在策略行中为空
+func writeCSV(w io.Writer, reports []*RoomReport) error {
+	maxRounds := 0
+	for _, report := range reports {
+		for rounds := range report.RoundHist {
+			maxRounds = max(maxRounds, rounds)
+		}
+	}
+	header := []string{
+		"roomType", "strategy", "rooms", "seats", "wins", "winRate",
+		"roundsMean", "earlyRate", "noBidRate", "valueMean",
+		"profitMean", "profitMin", "profitP10", "profitP25", "profitP50", "profitP75", "profitP90", "profitMax", "lossRate",
+		"goldReward", "goldBid", "goldEntry", "goldNet", "itemsUsed",
+	}
+	for rounds := 1; rounds <= maxRounds; rounds++ {
+		header = append(header, "rounds"+strconv.Itoa(rounds))
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, report := range reports {
+		row := []string{
+			uintStr(report.RoomType), "all", strconv.Itoa(report.Rooms), "", "", "",
+			floatStr(report.Rounds.Mean), floatStr(report.EarlyRate), floatStr(report.NoBidRate), floatStr(report.Value.Mean),
+		}
+		row = append(row, profitColumns(report.Profit, report.LossRate)...)
+		row = append(row, goldColumns(report.Gold, report.ItemsUsed)...)
+		for rounds := 1; rounds <= maxRounds; rounds++ {
+			row = append(row, strconv.Itoa(report.RoundHist[rounds]))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+
+		for _, st := range report.Strategies {
+			row = []string{
+				uintStr(report.RoomType), st.Strategy, "", strconv.Itoa(st.Seats), strconv.Itoa(st.Wins), floatStr(st.WinRate),
+				"", "", "", "",
+			}
+			row = append(row, profitColumns(st.Profit, st.LossRate)...)
+			row = append(row, goldColumns(st.Gold, st.ItemsUsed)...)
+			for rounds := 1; rounds <= maxRounds; rounds++ {
+				row = append(row, "")
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func profitColumns(d Distribution, lossRate float64) []string {
+	return []string{
+		floatStr(d.Mean), intStr(d.Min), intStr(d.P10), intStr(d.P25), intStr(d.P50), intStr(d.P75), intStr(d.P90), intStr(d.Max),
+		floatStr(lossRate),
+	}
+}
+
+func goldColumns(g Gold, itemsUsed int) []string {
+	return []string{intStr(g.Reward), intStr(g.Bid), intStr(g.Entry), intStr(g.Net), strconv.Itoa(itemsUsed)}
+}
+
+func intStr(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func uintStr(v uint32) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+func floatStr(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
@@ -0,0 +1,102 @@
+package affinity
+
+import (
+	"context"
+	"fmt"
+	"gameServer/pkg/cache/ssdb"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/seefan/gossdb/v2/client"
+)
+
+const (
+	bindingKey = "RoomBinding:UserId:%d"
+
+	// BindingTTL 绑定有效期，超过一局的最长时间，房间异常未清理时自动过期
+	BindingTTL = 30 * time.Minute
+)
+
+// Binding 玩家与房间节点的绑定，玩家重连后网关据此路由回原房间
+type Binding struct {
+	ServerId uint32 // 房间节点 id
+	RoomId   int32  // 节点内房间 id
+}
+
+func getBindingKey(userId uint64) string {
+	return fmt.Sprintf(bindingKey, userId)
+}
+
+// Bind 绑定玩家到房间节点
+func Bind(ctx context.Context, userIds []uint64, serverId uint32, roomId int32) error {
+	value := strconv.FormatUint(uint64(serverId), 10) + ":" + strconv.Itoa(int(roomId))
+	for _, userId := range userIds {
+		key := getBindingKey(userId)
+		_, err := ssdb.Do(ctx, func() (struct{}, error) {
+			return struct{}{}, ssdb.GetClient().Set(key, value, int64(BindingTTL/time.Second))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Refresh 延长绑定有效期
+func Refresh(ctx context.Context, userIds []uint64) error {
+	for _, userId := range userIds {
+		key := getBindingKey(userId)
+		_, err := ssdb.Do(ctx, func() (bool, error) {
+			return ssdb.GetClient().Expire(key, int64(BindingTTL/time.Second))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unbind 解除绑定，只删除仍指向该房间的绑定，避免误删玩家新开的房间
+func Unbind(ctx context.Context, userId uint64, serverId uint32, roomId int32) error {
+	binding, err := Find(ctx, userId)
+	if err != nil || binding == nil {
+		return err
+	}
+	if binding.ServerId != serverId || binding.RoomId != roomId {
+		return nil
+	}
+	key := getBindingKey(userId)
+	_, err = ssdb.Do(ctx, func() (struct{}, error) {
+		return struct{}{}, ssdb.GetClient().Del(key)
+	})
+	return err
+}
+
+// Find 查找玩家的房间绑定，不存在时返回 nil
+func Find(ctx context.Context, userId uint64) (*Binding, error) {
+	key := getBindingKey(userId)
+	value, err := ssdb.Do(ctx, func() (client.Value, error) {
+		return ssdb.GetClient().Get(key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if value.IsEmpty() {
+		return nil, nil
+	}
+
+	serverId, roomId, ok := strings.Cut(value.String(), ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid room binding: %s", value.String())
+	}
+	sid, err := strconv.ParseUint(serverId, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	rid, err := strconv.Atoi(roomId)
+	if err != nil {
+		return nil, err
+	}
+	return &Binding{ServerId: uint32(sid), RoomId: int32(rid)}, nil
+}
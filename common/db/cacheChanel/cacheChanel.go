@@ -2,4 +2,6 @@ package cacheChanel
 
 var (
 	ItemChanel = "item"
+	// TopicChanel 主题推送，所有网关订阅
+	TopicChanel = "topic"
 )
@@ -1,6 +1,7 @@
 package heros
 
 import (
+	"context"
 	"fmt"
 	"gameServer/pkg/cache/ssdb"
 	"gameServer/pkg/redis"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/patrickmn/go-cache"
+	"github.com/seefan/gossdb/v2/client"
 )
 
 var (
@@ -68,11 +70,13 @@ func getKey(userId uint64) string {
 //	return true
 //}
 
-func UnLockCharacter(userId uint64, idList []int) bool {
+func UnLockCharacter(ctx context.Context, userId uint64, idList []int) bool {
 	key := getKey(userId)
 	for _, id := range idList {
 		h := hero{Id: id, Skins: []uint32{}}
-		err := ssdb.GetClient().HSet(key, strconv.Itoa(id), h)
+		_, err := ssdb.Do(ctx, func() (struct{}, error) {
+			return struct{}{}, ssdb.GetClient().HSet(key, strconv.Itoa(id), h)
+		})
 		if err != nil {
 			return false
 		}
@@ -95,7 +99,7 @@ func UnLockCharacter(userId uint64, idList []int) bool {
 //}
 
 // 获取所有
-func GetAllUnLockCharacter(userId uint64) []*hero {
+func GetAllUnLockCharacter(ctx context.Context, userId uint64) []*hero {
 	key := getKey(userId)
 	// 本地缓存
 	allHero, found := localCache.Get(key)
@@ -105,7 +109,9 @@ func GetAllUnLockCharacter(userId uint64) []*hero {
 		}
 		return nil
 	}
-	val, err := ssdb.GetClient().HGetAll(key)
+	val, err := ssdb.Do(ctx, func() (map[string]client.Value, error) {
+		return ssdb.GetClient().HGetAll(key)
+	})
 	if err != nil {
 		return nil
 	}
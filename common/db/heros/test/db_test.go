@@ -33,7 +33,7 @@ func TestRun2(t *testing.T) {
 	defer cleanup()
 
 	// ========== 获取数据 ==========
-	allItems, err := items.GetAllItems(123456)
+	allItems, err := items.GetAllItems(context.Background(), 123456)
 	if err != nil {
 		t.Fatalf("获取物品失败: %v", err)
 	}
@@ -75,8 +75,8 @@ func TestRun2(t *testing.T) {
 	close(semaphore)
 
 	// ========== 验证结果 ==========
-	character := heros.GetAllUnLockCharacter(123456)
-	character = heros.GetAllUnLockCharacter(123456)
+	character := heros.GetAllUnLockCharacter(context.Background(), 123456)
+	character = heros.GetAllUnLockCharacter(context.Background(), 123456)
 
 	//finalItems, err := items.GetAllItems(123456)
 	//if err != nil {
@@ -142,7 +142,7 @@ func cleanup() {
 
 func saveWithRetry(roleID uint64, itemID int) error {
 	const maxRetries = 3
-	flag := heros.UnLockCharacter(roleID, []int{itemID})
+	flag := heros.UnLockCharacter(context.Background(), roleID, []int{itemID})
 	if !flag {
 		return nil
 	}
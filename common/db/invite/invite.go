@@ -0,0 +1,73 @@
+package invite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gameServer/pkg/redis"
+	"strconv"
+	"strings"
+	"time"
+
+	redis2 "github.com/redis/go-redis/v9"
+)
+
+const (
+	codeKey = "PrivateRoom:Code:%s" // 邀请码-房间位置，所有节点共用，保证邀请码唯一
+
+	// CodeTTL 邀请码有效期，房间开始或销毁时删除，节点异常退出时自动过期
+	CodeTTL = 30 * time.Minute
+)
+
+// Room 私人房间所在的节点和房间
+type Room struct {
+	ServerId uint32 // 房间节点 id
+	RoomId   int32  // 节点内房间 id
+}
+
+func getCodeKey(code string) string {
+	return fmt.Sprintf(codeKey, code)
+}
+
+// Reserve 为节点上的房间占用邀请码，已被其它房间占用时返回 false
+func Reserve(ctx context.Context, code string, serverId uint32, roomId int32) (bool, error) {
+	value := strconv.FormatUint(uint64(serverId), 10) + ":" + strconv.Itoa(int(roomId))
+	return redis.GetRedisClient().SetNX(ctx, getCodeKey(code), value, CodeTTL).Result()
+}
+
+// Find 邀请码对应的房间，不存在或已过期时返回 nil
+func Find(ctx context.Context, code string) (*Room, error) {
+	value, err := redis.GetRedisClient().Get(ctx, getCodeKey(code)).Result()
+	if errors.Is(err, redis2.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	serverId, roomId, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid invite code: %s", value)
+	}
+	sid, err := strconv.ParseUint(serverId, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	rid, err := strconv.Atoi(roomId)
+	if err != nil {
+		return nil, err
+	}
+	return &Room{ServerId: uint32(sid), RoomId: int32(rid)}, nil
+}
+
+// Release 邀请码失效，只删除仍指向该房间的邀请码，避免误删过期后被其它房间占用的邀请码
+func Release(ctx context.Context, code string, serverId uint32, roomId int32) error {
+	room, err := Find(ctx, code)
+	if err != nil || room == nil {
+		return err
+	}
+	if room.ServerId != serverId || room.RoomId != roomId {
+		return nil
+	}
+	return redis.GetRedisClient().Del(ctx, getCodeKey(code)).Err()
+}
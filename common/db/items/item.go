@@ -1,6 +1,7 @@
 package items
 
 import (
+	"context"
 	"fmt"
 	"gameServer/common/db/cacheChanel"
 	"gameServer/pkg/cache/ssdb"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/patrickmn/go-cache"
+	"github.com/seefan/gossdb/v2/client"
 	"go.uber.org/zap"
 )
 
@@ -32,8 +34,8 @@ func getItemKey(userId uint64) string {
 }
 
 // 验证自身道具是否充足
-func VerifyItem(userId uint64, itemMap map[int]int64) bool {
-	itemInfos, err := GetAllItems(userId)
+func VerifyItem(ctx context.Context, userId uint64, itemMap map[int]int64) bool {
+	itemInfos, err := GetAllItems(ctx, userId)
 	if err != nil {
 		log2.Get().Warn("get ItemInfo false", zap.Any("err:", err))
 		return false
@@ -50,17 +52,19 @@ func VerifyItem(userId uint64, itemMap map[int]int64) bool {
 	return true
 }
 
-func GetItem(userId uint64, itemId int) (int64, error) {
-	items, err := GetAllItems(userId)
+func GetItem(ctx context.Context, userId uint64, itemId int) (int64, error) {
+	items, err := GetAllItems(ctx, userId)
 	if v, ok := items[itemId]; ok {
 		return v, nil
 	}
 	return 0, err
 }
 
-func GetItemNoCache(userId uint64, itemId int) (int64, error) {
+func GetItemNoCache(ctx context.Context, userId uint64, itemId int) (int64, error) {
 	//value, err := ssdb.GetClient().HGet(getItemKey(userId), strconv.Itoa(itemId))
-	value, err := ssdb.GetClient().HGet(getItemKey(userId), strconv.Itoa(itemId))
+	value, err := ssdb.Do(ctx, func() (client.Value, error) {
+		return ssdb.GetClient().HGet(getItemKey(userId), strconv.Itoa(itemId))
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -70,9 +74,14 @@ func GetItemNoCache(userId uint64, itemId int) (int64, error) {
 	return value.Int64(), nil
 }
 
-func RewardItem(userId uint64, itemMap map[int]int64) bool {
+func RewardItem(ctx context.Context, userId uint64, itemMap map[int]int64) bool {
+	// 期限只在开始前检查，多个道具要么都写入要么都不写
+	ctx, err := ssdb.Detach(ctx)
+	if err != nil {
+		return false
+	}
 	for itemId, delta := range itemMap {
-		_, err := AddItem(userId, itemId, delta)
+		_, err := AddItem(ctx, userId, itemId, delta)
 		if err != nil {
 			return false
 		}
@@ -80,7 +89,7 @@ func RewardItem(userId uint64, itemMap map[int]int64) bool {
 	return true
 }
 
-func GetAllItems(userId uint64) (map[int]int64, error) {
+func GetAllItems(ctx context.Context, userId uint64) (map[int]int64, error) {
 	key := getItemKey(userId)
 	//  本地缓存（返回副本）
 	if v, ok := itemCache.Get(key); ok {
@@ -88,7 +97,9 @@ func GetAllItems(userId uint64) (map[int]int64, error) {
 	}
 
 	// miss -> 查SSDB
-	vals, err := ssdb.GetClient().HGetAll(key)
+	vals, err := ssdb.Do(ctx, func() (map[string]client.Value, error) {
+		return ssdb.GetClient().HGetAll(key)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -108,13 +119,15 @@ func GetAllItems(userId uint64) (map[int]int64, error) {
 	return cloneMap(result), nil
 }
 
-func AddItem(userId uint64, itemId int, delta int64) (int64, error) {
+func AddItem(ctx context.Context, userId uint64, itemId int, delta int64) (int64, error) {
 	key := getItemKey(userId)
-	newCount, err := ssdb.GetClient().HIncr(
-		key,
-		strconv.Itoa(itemId),
-		delta,
-	)
+	newCount, err := ssdb.Do(ctx, func() (int64, error) {
+		return ssdb.GetClient().HIncr(
+			key,
+			strconv.Itoa(itemId),
+			delta,
+		)
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -129,9 +142,14 @@ func AddItem(userId uint64, itemId int, delta int64) (int64, error) {
 	return newCount, nil
 }
 
-func ConsumeItem(userId uint64, itemMap map[int]int64) bool {
+func ConsumeItem(ctx context.Context, userId uint64, itemMap map[int]int64) bool {
+	// 期限只在开始前检查，多个道具要么都写入要么都不写
+	ctx, err := ssdb.Detach(ctx)
+	if err != nil {
+		return false
+	}
 	for itemId, delta := range itemMap {
-		_, err := AddItem(userId, itemId, -delta)
+		_, err := AddItem(ctx, userId, itemId, -delta)
 		if err != nil {
 			return false
 		}
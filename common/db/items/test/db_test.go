@@ -27,7 +27,7 @@ func TestRun2(t *testing.T) {
 	defer cleanup()
 
 	// ========== 获取数据 ==========
-	allItems, err := items.GetAllItems(123456)
+	allItems, err := items.GetAllItems(ctx, 123456)
 	if err != nil {
 		t.Fatalf("获取物品失败: %v", err)
 	}
@@ -69,7 +69,7 @@ func TestRun2(t *testing.T) {
 	close(semaphore)
 
 	// ========== 验证结果 ==========
-	finalItems, err := items.GetAllItems(123456)
+	finalItems, err := items.GetAllItems(ctx, 123456)
 	if err != nil {
 		t.Fatalf("获取最终物品失败: %v", err)
 	}
@@ -132,7 +132,7 @@ func cleanup() {
 
 func saveWithRetry(ctx context.Context, roleID uint64, itemID int, count int64) error {
 	const maxRetries = 3
-	_, err := items.AddItem(roleID, itemID, count)
+	_, err := items.AddItem(ctx, roleID, itemID, count)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,76 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"gameServer/pkg/cache/ssdb"
+	"strconv"
+	"strings"
+
+	"github.com/seefan/gossdb/v2/client"
+)
+
+const (
+	// 每种房间类型一个 hash，字段为 节点 id:房间 id，值为房间信息
+	liveKey = "LiveRoom:RoomType:%d"
+)
+
+// Room 正在进行中的房间位置
+type Room struct {
+	ServerId uint32 // 房间节点 id
+	RoomId   int32  // 节点内房间 id
+}
+
+func getLiveKey(roomType uint32) string {
+	return fmt.Sprintf(liveKey, roomType)
+}
+
+func getField(serverId uint32, roomId int32) string {
+	return strconv.FormatUint(uint64(serverId), 10) + ":" + strconv.Itoa(int(roomId))
+}
+
+// Save 保存进行中的房间，覆盖上一次的信息
+func Save(ctx context.Context, roomType uint32, serverId uint32, roomId int32, data []byte) error {
+	key := getLiveKey(roomType)
+	_, err := ssdb.Do(ctx, func() (struct{}, error) {
+		return struct{}{}, ssdb.GetClient().HSet(key, getField(serverId, roomId), data)
+	})
+	return err
+}
+
+// Delete 删除房间，房间结束或信息过期后调用
+func Delete(ctx context.Context, roomType uint32, serverId uint32, roomId int32) error {
+	key := getLiveKey(roomType)
+	_, err := ssdb.Do(ctx, func() (struct{}, error) {
+		return struct{}{}, ssdb.GetClient().HDel(key, getField(serverId, roomId))
+	})
+	return err
+}
+
+// LoadAll 所有节点上该类型进行中的房间，房间位置-房间信息
+func LoadAll(ctx context.Context, roomType uint32) (map[Room][]byte, error) {
+	key := getLiveKey(roomType)
+	values, err := ssdb.Do(ctx, func() (map[string]client.Value, error) {
+		return ssdb.GetClient().HGetAll(key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[Room][]byte, len(values))
+	for field, value := range values {
+		serverId, roomId, ok := strings.Cut(field, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid live room field: %s", field)
+		}
+		sid, err := strconv.ParseUint(serverId, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid live room field: %s", field)
+		}
+		rid, err := strconv.ParseInt(roomId, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid live room field: %s", field)
+		}
+		all[Room{ServerId: uint32(sid), RoomId: int32(rid)}] = value.Bytes()
+	}
+	return all, nil
+}
@@ -0,0 +1,291 @@
+package matchPool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gameServer/pkg/redis"
+	"strconv"
+	"strings"
+	"time"
+
+	redis2 "github.com/redis/go-redis/v9"
+)
+
+const (
+	ticketKey = "MatchPool:RoomType:%d"        // 每种房间类型一个 hash，字段为玩家 id，值为匹配票据
+	userKey   = "MatchPool:User"               // 玩家 id-房间类型，取消匹配时据此找到票据
+	lockKey   = "MatchPool:Lock"               // 撮合锁，同一时间只有一个节点撮合
+	nodeKey   = "MatchPool:Node"               // 节点 id-负载，节点定时上报
+	assignKey = "MatchPool:Assign:ServerId:%d" // 分配给节点的桌，节点按顺序取出后创建房间
+	ackKey    = "MatchPool:Ack:ServerId:%d"    // 节点已取出、房间创建完成前的桌，创建后确认删除
+)
+
+// NodeLoad 节点上报的负载
+type NodeLoad struct {
+	Rooms int64 // 房间数
+	Time  int64 // 上报时间 秒
+}
+
+func getTicketKey(roomType uint32) string {
+	return fmt.Sprintf(ticketKey, roomType)
+}
+
+func getAssignKey(serverId uint32) string {
+	return fmt.Sprintf(assignKey, serverId)
+}
+
+func getAckKey(serverId uint32) string {
+	return fmt.Sprintf(ackKey, serverId)
+}
+
+// Add 加入匹配池，已在其它类型房间匹配时先移除
+func Add(ctx context.Context, roomType uint32, userId uint64, ticket []byte) error {
+	if _, err := Remove(ctx, userId); err != nil {
+		return err
+	}
+	field := strconv.FormatUint(userId, 10)
+	_, err := redis.GetRedisClient().TxPipelined(ctx, func(pipe redis2.Pipeliner) error {
+		pipe.HSet(ctx, getTicketKey(roomType), field, ticket)
+		pipe.HSet(ctx, userKey, field, roomType)
+		return nil
+	})
+	return err
+}
+
+// Remove 移出匹配池，票据已被撮合或不存在时返回 false
+func Remove(ctx context.Context, userId uint64) (bool, error) {
+	field := strconv.FormatUint(userId, 10)
+	roomType, err := redis.GetRedisClient().HGet(ctx, userKey, field).Uint64()
+	if errors.Is(err, redis2.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var n *redis2.IntCmd
+	_, err = redis.GetRedisClient().TxPipelined(ctx, func(pipe redis2.Pipeliner) error {
+		n = pipe.HDel(ctx, getTicketKey(uint32(roomType)), field)
+		pipe.HDel(ctx, userKey, field)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return n.Val() > 0, nil
+}
+
+// LoadAll 某类型房间的所有票据，玩家 id-票据
+func LoadAll(ctx context.Context, roomType uint32) (map[uint64][]byte, error) {
+	values, err := redis.GetRedisClient().HGetAll(ctx, getTicketKey(roomType)).Result()
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[uint64][]byte, len(values))
+	for field, value := range values {
+		userId, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match ticket field: %s", field)
+		}
+		all[userId] = []byte(value)
+	}
+	return all, nil
+}
+
+// takeScript 原子地移出票据，只返回仍在池中的玩家，已取消的玩家不会被分配
+var takeScript = redis2.NewScript(`
+local taken = {}
+for i = 2, #ARGV do
+	if redis.call('HDEL', KEYS[1], ARGV[i]) == 1 then
+		if redis.call('HGET', KEYS[2], ARGV[i]) == ARGV[1] then
+			redis.call('HDEL', KEYS[2], ARGV[i])
+		end
+		taken[#taken + 1] = ARGV[i]
+	end
+end
+return taken
+`)
+
+// Take 撮合成功后移出票据，返回成功移出的玩家
+func Take(ctx context.Context, roomType uint32, userIds []uint64) ([]uint64, error) {
+	args := make([]any, 0, len(userIds)+1)
+	args = append(args, strconv.FormatUint(uint64(roomType), 10))
+	for _, userId := range userIds {
+		args = append(args, strconv.FormatUint(userId, 10))
+	}
+	fields, err := takeScript.Run(ctx, redis.GetRedisClient(), []string{getTicketKey(roomType), userKey}, args...).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+	taken := make([]uint64, 0, len(fields))
+	for _, field := range fields {
+		userId, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		taken = append(taken, userId)
+	}
+	return taken, nil
+}
+
+// Restore 放回未能成桌的票据，玩家期间已重新匹配时不覆盖
+func Restore(ctx context.Context, roomType uint32, tickets map[uint64][]byte) error {
+	for userId, ticket := range tickets {
+		field := strconv.FormatUint(userId, 10)
+		ok, err := redis.GetRedisClient().HSetNX(ctx, userKey, field, roomType).Result()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err = redis.GetRedisClient().HSet(ctx, getTicketKey(roomType), field, ticket).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TryLock 获取或延长撮合锁，持有者为节点 id
+func TryLock(ctx context.Context, serverId uint32, ttl time.Duration) (bool, error) {
+	owner := strconv.FormatUint(uint64(serverId), 10)
+	ok, err := redis.GetRedisClient().SetNX(ctx, lockKey, owner, ttl).Result()
+	if err != nil || ok {
+		return ok, err
+	}
+	cur, err := redis.GetRedisClient().Get(ctx, lockKey).Result()
+	if errors.Is(err, redis2.Nil) {
+		return false, nil
+	}
+	if err != nil || cur != owner {
+		return false, err
+	}
+	return true, redis.GetRedisClient().PExpire(ctx, lockKey, ttl).Err()
+}
+
+// Heartbeat 上报节点负载
+func Heartbeat(ctx context.Context, serverId uint32, rooms int64) error {
+	value := strconv.FormatInt(rooms, 10) + ":" + strconv.FormatInt(time.Now().Unix(), 10)
+	return redis.GetRedisClient().HSet(ctx, nodeKey, strconv.FormatUint(uint64(serverId), 10), value).Err()
+}
+
+// Nodes 所有上报过的节点负载，节点 id-负载
+func Nodes(ctx context.Context) (map[uint32]NodeLoad, error) {
+	values, err := redis.GetRedisClient().HGetAll(ctx, nodeKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[uint32]NodeLoad, len(values))
+	for field, value := range values {
+		serverId, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match node field: %s", field)
+		}
+		rooms, t, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid match node load: %s", value)
+		}
+		load := NodeLoad{}
+		if load.Rooms, err = strconv.ParseInt(rooms, 10, 64); err != nil {
+			return nil, err
+		}
+		if load.Time, err = strconv.ParseInt(t, 10, 64); err != nil {
+			return nil, err
+		}
+		nodes[uint32(serverId)] = load
+	}
+	return nodes, nil
+}
+
+// assignScript 原子地移出一桌的票据并分配给节点，任一玩家已不在池中时不修改，返回 0
+var assignScript = redis2.NewScript(`
+for i = 3, #ARGV do
+	if redis.call('HEXISTS', KEYS[1], ARGV[i]) == 0 then
+		return 0
+	end
+end
+for i = 3, #ARGV do
+	redis.call('HDEL', KEYS[1], ARGV[i])
+	if redis.call('HGET', KEYS[2], ARGV[i]) == ARGV[1] then
+		redis.call('HDEL', KEYS[2], ARGV[i])
+	end
+end
+redis.call('RPUSH', KEYS[3], ARGV[2])
+return 1
+`)
+
+// Assign 移出一桌玩家的票据并把桌分配给节点，期间有玩家取消时返回 false，票据不变
+func Assign(ctx context.Context, serverId uint32, roomType uint32, userIds []uint64, table []byte) (bool, error) {
+	args := make([]any, 0, len(userIds)+2)
+	args = append(args, strconv.FormatUint(uint64(roomType), 10), table)
+	for _, userId := range userIds {
+		args = append(args, strconv.FormatUint(userId, 10))
+	}
+	n, err := assignScript.Run(ctx, redis.GetRedisClient(), []string{getTicketKey(roomType), userKey, getAssignKey(serverId)}, args...).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Acquire 取出分配给节点的一桌，同时放入待确认列表，房间创建后调用 Ack；没有时返回 nil
+func Acquire(ctx context.Context, serverId uint32) ([]byte, error) {
+	value, err := redis.GetRedisClient().LMove(ctx, getAssignKey(serverId), getAckKey(serverId), "LEFT", "RIGHT").Result()
+	if errors.Is(err, redis2.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+// Ack 确认一桌已创建房间，从待确认列表删除
+func Ack(ctx context.Context, serverId uint32, table []byte) error {
+	return redis.GetRedisClient().LRem(ctx, getAckKey(serverId), 1, table).Err()
+}
+
+// Unacked 节点已取出但未确认的桌，节点重启后据此继续创建
+func Unacked(ctx context.Context, serverId uint32) ([][]byte, error) {
+	return lrange(ctx, getAckKey(serverId))
+}
+
+// Pending 分配给节点但未确认的所有桌，包括未取出和未确认的
+func Pending(ctx context.Context, serverId uint32) ([][]byte, error) {
+	assigned, err := lrange(ctx, getAssignKey(serverId))
+	if err != nil {
+		return nil, err
+	}
+	unacked, err := lrange(ctx, getAckKey(serverId))
+	if err != nil {
+		return nil, err
+	}
+	return append(assigned, unacked...), nil
+}
+
+// Discard 从节点的分配和待确认列表删除一桌
+func Discard(ctx context.Context, serverId uint32, table []byte) error {
+	_, err := redis.GetRedisClient().TxPipelined(ctx, func(pipe redis2.Pipeliner) error {
+		pipe.LRem(ctx, getAssignKey(serverId), 1, table)
+		pipe.LRem(ctx, getAckKey(serverId), 1, table)
+		return nil
+	})
+	return err
+}
+
+// RemoveNode 删除节点的负载记录，节点恢复后重新上报
+func RemoveNode(ctx context.Context, serverId uint32) error {
+	return redis.GetRedisClient().HDel(ctx, nodeKey, strconv.FormatUint(uint64(serverId), 10)).Err()
+}
+
+func lrange(ctx context.Context, key string) ([][]byte, error) {
+	values, err := redis.GetRedisClient().LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	tables := make([][]byte, 0, len(values))
+	for _, value := range values {
+		tables = append(tables, []byte(value))
+	}
+	return tables, nil
+}
@@ -0,0 +1,171 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"gameServer/common/db/matchPool"
+	"gameServer/pkg/redis"
+	"os"
+	"slices"
+	"testing"
+)
+
+const (
+	roomType = 9901 // 测试用房间类型，不与配置冲突
+	serverId = 9901 // 测试用节点 id
+)
+
+var ready bool
+
+func TestMain(m *testing.M) {
+	func() {
+		defer func() {
+			if err := recover(); err != nil {
+				fmt.Println("redis not available:", err)
+			}
+		}()
+		redis.NewRedisClient("127.0.0.1:16379")
+		ready = true
+	}()
+	os.Exit(m.Run())
+}
+
+func setup(t *testing.T) context.Context {
+	if !ready {
+		t.Skip("redis not available")
+	}
+	ctx := context.Background()
+	cleanup := func() {
+		redis.GetRedisClient().Del(ctx,
+			fmt.Sprintf("MatchPool:RoomType:%d", roomType),
+			fmt.Sprintf("MatchPool:Assign:ServerId:%d", serverId),
+			fmt.Sprintf("MatchPool:Ack:ServerId:%d", serverId))
+		for _, userId := range []uint64{1, 2, 3} {
+			_, _ = matchPool.Remove(ctx, userId)
+		}
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+	return ctx
+}
+
+func TestTakeRestore(t *testing.T) {
+	ctx := setup(t)
+	for _, userId := range []uint64{1, 2} {
+		if err := matchPool.Add(ctx, roomType, userId, []byte{byte(userId)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	taken, err := matchPool.Take(ctx, roomType, []uint64{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(taken)
+	if !slices.Equal(taken, []uint64{1, 2}) {
+		t.Fatalf("taken %v, want [1 2]", taken)
+	}
+	if ok, _ := matchPool.Remove(ctx, 1); ok {
+		t.Fatal("taken ticket still in pool")
+	}
+
+	// 玩家 2 期间重新匹配，放回时不覆盖
+	if err = matchPool.Add(ctx, roomType, 2, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	if err = matchPool.Restore(ctx, roomType, map[uint64][]byte{1: {1}, 2: {2}}); err != nil {
+		t.Fatal(err)
+	}
+	all, err := matchPool.LoadAll(ctx, roomType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(all[1]) != "\x01" || string(all[2]) != "new" {
+		t.Fatalf("tickets after restore: %q", all)
+	}
+}
+
+func TestAssignAck(t *testing.T) {
+	ctx := setup(t)
+	for _, userId := range []uint64{1, 2} {
+		if err := matchPool.Add(ctx, roomType, userId, []byte{byte(userId)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 玩家 3 不在池中，整桌不分配，票据不变
+	ok, err := matchPool.Assign(ctx, serverId, roomType, []uint64{1, 3}, []byte("t0"))
+	if err != nil || ok {
+		t.Fatalf("assign with missing ticket: %v %v", ok, err)
+	}
+	if all, _ := matchPool.LoadAll(ctx, roomType); len(all) != 2 {
+		t.Fatalf("tickets changed: %q", all)
+	}
+
+	ok, err = matchPool.Assign(ctx, serverId, roomType, []uint64{1, 2}, []byte("t1"))
+	if err != nil || !ok {
+		t.Fatalf("assign: %v %v", ok, err)
+	}
+	if all, _ := matchPool.LoadAll(ctx, roomType); len(all) != 0 {
+		t.Fatalf("tickets not taken: %q", all)
+	}
+
+	table, err := matchPool.Acquire(ctx, serverId)
+	if err != nil || string(table) != "t1" {
+		t.Fatalf("acquire: %q %v", table, err)
+	}
+	if table, _ = matchPool.Acquire(ctx, serverId); table != nil {
+		t.Fatalf("acquire from empty list: %q", table)
+	}
+	// 创建房间前节点退出，桌仍在待确认列表中
+	unacked, err := matchPool.Unacked(ctx, serverId)
+	if err != nil || len(unacked) != 1 || string(unacked[0]) != "t1" {
+		t.Fatalf("unacked: %q %v", unacked, err)
+	}
+	if err = matchPool.Ack(ctx, serverId, []byte("t1")); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := matchPool.Pending(ctx, serverId); len(pending) != 0 {
+		t.Fatalf("pending after ack: %q", pending)
+	}
+}
+
+func TestDiscard(t *testing.T) {
+	ctx := setup(t)
+	for _, userId := range []uint64{1, 2} {
+		if err := matchPool.Add(ctx, roomType, userId, []byte{byte(userId)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ok, err := matchPool.Assign(ctx, serverId, roomType, []uint64{1}, []byte("t1")); err != nil || !ok {
+		t.Fatalf("assign: %v %v", ok, err)
+	}
+	if ok, err := matchPool.Assign(ctx, serverId, roomType, []uint64{2}, []byte("t2")); err != nil || !ok {
+		t.Fatalf("assign: %v %v", ok, err)
+	}
+	if _, err := matchPool.Acquire(ctx, serverId); err != nil {
+		t.Fatal(err)
+	}
+
+	// 节点失效时，未取出和未确认的桌都需要放回
+	pending, err := matchPool.Pending(ctx, serverId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, b := range pending {
+		got = append(got, string(b))
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"t1", "t2"}) {
+		t.Fatalf("pending %q, want [t1 t2]", got)
+	}
+	for _, b := range pending {
+		if err = matchPool.Discard(ctx, serverId, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if pending, _ = matchPool.Pending(ctx, serverId); len(pending) != 0 {
+		t.Fatalf("pending after discard: %q", pending)
+	}
+}
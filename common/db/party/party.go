@@ -0,0 +1,184 @@
+package party
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gameServer/pkg/redis"
+	"slices"
+	"time"
+
+	redis2 "github.com/redis/go-redis/v9"
+)
+
+const (
+	partyKey  = "Party:Id:%d"     // 队伍信息 json
+	userKey   = "Party:UserId:%d" // 玩家所在的队伍 id
+	nextIdKey = "Party:NextId"    // 自增的队伍 id
+
+	// PartyTTL 队伍有效期，每次修改后延长，队员都断线不再操作时自动解散
+	PartyTTL = 2 * time.Hour
+
+	// 并发修改冲突时的重试次数
+	updateRetry = 10
+)
+
+var (
+	ErrNotFound = errors.New("party not found")
+	ErrInParty  = errors.New("already in another party")
+	ErrConflict = errors.New("party update conflict")
+)
+
+// Party 队伍，队员可能在不同的房间节点上，保存在 redis 中
+type Party struct {
+	PartyId  uint64
+	LeaderId uint64
+	Members  []uint64 // 队员，按加入顺序，包含队长
+	Invites  []uint64 // 已邀请、未接受的玩家
+
+	RoomType uint32                     // 准备的房间类型
+	Ready    map[uint64]json.RawMessage // 已准备的队员-匹配信息
+	TicketId uint64                     // 全部准备后的匹配票据，为 0 时未在匹配
+}
+
+func getPartyKey(partyId uint64) string {
+	return fmt.Sprintf(partyKey, partyId)
+}
+
+func getUserKey(userId uint64) string {
+	return fmt.Sprintf(userKey, userId)
+}
+
+// Create 创建队伍，玩家已在其它队伍中时返回 ErrInParty
+func Create(ctx context.Context, leaderId uint64) (*Party, error) {
+	if partyId, err := FindId(ctx, leaderId); err != nil {
+		return nil, err
+	} else if partyId > 0 {
+		return nil, ErrInParty
+	}
+	partyId, err := redis.GetRedisClient().Incr(ctx, nextIdKey).Uint64()
+	if err != nil {
+		return nil, err
+	}
+	p := &Party{PartyId: partyId, LeaderId: leaderId, Members: []uint64{leaderId}}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := redis.GetRedisClient().SetNX(ctx, getUserKey(leaderId), partyId, PartyTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInParty
+	}
+	if err = redis.GetRedisClient().Set(ctx, getPartyKey(partyId), b, PartyTTL).Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// FindId 玩家所在的队伍 id，不在队伍中时为 0
+func FindId(ctx context.Context, userId uint64) (uint64, error) {
+	partyId, err := redis.GetRedisClient().Get(ctx, getUserKey(userId)).Uint64()
+	if errors.Is(err, redis2.Nil) {
+		return 0, nil
+	}
+	return partyId, err
+}
+
+// Get 队伍信息，不存在时返回 ErrNotFound
+func Get(ctx context.Context, partyId uint64) (*Party, error) {
+	return get(ctx, redis.GetRedisClient(), partyId)
+}
+
+func get(ctx context.Context, c redis2.Cmdable, partyId uint64) (*Party, error) {
+	b, err := c.Get(ctx, getPartyKey(partyId)).Bytes()
+	if errors.Is(err, redis2.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	p := &Party{}
+	if err = json.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Update 读取、修改并保存队伍，并发修改时重试
+//
+// fn 返回错误时不保存；修改后没有队员时解散队伍。新加入的队员已在其它队伍中时返回 ErrInParty，
+// 离开的队员同时删除玩家所在的队伍
+func Update(ctx context.Context, partyId uint64, fn func(p *Party) error) (*Party, error) {
+	key := getPartyKey(partyId)
+	var result *Party
+	txf := func(tx *redis2.Tx) error {
+		p, err := get(ctx, tx, partyId)
+		if err != nil {
+			return err
+		}
+		old := slices.Clone(p.Members)
+		if err = fn(p); err != nil {
+			return err
+		}
+		var joined, left []uint64
+		for _, userId := range p.Members {
+			if !slices.Contains(old, userId) {
+				joined = append(joined, userId)
+			}
+		}
+		for _, userId := range old {
+			if !slices.Contains(p.Members, userId) {
+				left = append(left, userId)
+			}
+		}
+		for _, userId := range joined {
+			if err = tx.Watch(ctx, getUserKey(userId)).Err(); err != nil {
+				return err
+			}
+			cur, err := FindId(ctx, userId)
+			if err != nil {
+				return err
+			}
+			if cur > 0 && cur != partyId {
+				return ErrInParty
+			}
+		}
+		b, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis2.Pipeliner) error {
+			if len(p.Members) == 0 {
+				pipe.Del(ctx, key)
+			} else {
+				pipe.Set(ctx, key, b, PartyTTL)
+			}
+			for _, userId := range p.Members {
+				pipe.Set(ctx, getUserKey(userId), partyId, PartyTTL)
+			}
+			for _, userId := range left {
+				pipe.Del(ctx, getUserKey(userId))
+			}
+			return nil
+		})
+		result = p
+		return err
+	}
+
+	for range updateRetry {
+		err := redis.GetRedisClient().Watch(ctx, txf, key)
+		if errors.Is(err, redis2.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, ErrConflict
+}
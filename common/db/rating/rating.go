@@ -0,0 +1,64 @@
+package rating
+
+import (
+	"context"
+	"fmt"
+	"gameServer/pkg/cache/ssdb"
+	"strconv"
+
+	"github.com/seefan/gossdb/v2/client"
+)
+
+const (
+	// 每个玩家一个 hash，字段为房间类型，值为该类型的积分
+	ratingKey = "Rating:UserId:%d"
+)
+
+func getRatingKey(userId uint64) string {
+	return fmt.Sprintf(ratingKey, userId)
+}
+
+// Get 玩家某类型房间的积分，没有记录时 ok 为 false
+func Get(ctx context.Context, userId uint64, roomType uint32) (rating int64, ok bool, err error) {
+	all, err := Load(ctx, userId)
+	if err != nil {
+		return 0, false, err
+	}
+	rating, ok = all[roomType]
+	return rating, ok, nil
+}
+
+// Load 玩家所有类型房间的积分，房间类型-积分
+func Load(ctx context.Context, userId uint64) (map[uint32]int64, error) {
+	key := getRatingKey(userId)
+	values, err := ssdb.Do(ctx, func() (map[string]client.Value, error) {
+		return ssdb.GetClient().HGetAll(key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[uint32]int64, len(values))
+	for field, value := range values {
+		roomType, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rating field: %s", field)
+		}
+		all[uint32(roomType)] = value.Int64()
+	}
+	return all, nil
+}
+
+// Save 保存一局结束后的积分，玩家 id-积分
+func Save(ctx context.Context, roomType uint32, ratings map[uint64]int64) error {
+	field := strconv.FormatUint(uint64(roomType), 10)
+	for userId, rating := range ratings {
+		key := getRatingKey(userId)
+		_, err := ssdb.Do(ctx, func() (struct{}, error) {
+			return struct{}{}, ssdb.GetClient().HSet(key, field, rating)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,12 +1,14 @@
 package reward
 
 import (
+	"context"
 	"fmt"
 	"gameServer/pkg/cache/ssdb"
 	"strconv"
 	"time"
 
 	"github.com/patrickmn/go-cache"
+	"github.com/seefan/gossdb/v2/client"
 )
 
 var (
@@ -25,10 +27,12 @@ func getKey(userId uint64) string {
 }
 
 // 奖励
-func SaveRewardInfo(userId uint64, id int) bool {
+func SaveRewardInfo(ctx context.Context, userId uint64, id int) bool {
 	key := getKey(userId)
 	r := reward{Id: id, Timestamp: time.Now().Unix()}
-	err := ssdb.GetClient().HSet(key, strconv.Itoa(id), r)
+	_, err := ssdb.Do(ctx, func() (struct{}, error) {
+		return struct{}{}, ssdb.GetClient().HSet(key, strconv.Itoa(id), r)
+	})
 	if err != nil {
 		return false
 	}
@@ -38,7 +42,7 @@ func SaveRewardInfo(userId uint64, id int) bool {
 	return true
 }
 
-func GetAllRewardInfo(userId uint64) map[int]*reward {
+func GetAllRewardInfo(ctx context.Context, userId uint64) map[int]*reward {
 	key := getKey(userId)
 	infos, ok := rewardCache.Get(key)
 	if ok {
@@ -47,7 +51,9 @@ func GetAllRewardInfo(userId uint64) map[int]*reward {
 		}
 		return nil
 	}
-	val, err := ssdb.GetClient().HGetAll(key)
+	val, err := ssdb.Do(ctx, func() (map[string]client.Value, error) {
+		return ssdb.GetClient().HGetAll(key)
+	})
 	if err != nil {
 		return nil
 	}
@@ -28,7 +28,7 @@ func TestRun2(t *testing.T) {
 	defer cleanup()
 
 	// ========== 获取数据 ==========
-	allItems, err := items.GetAllItems(123456)
+	allItems, err := items.GetAllItems(context.Background(), 123456)
 	if err != nil {
 		t.Fatalf("获取物品失败: %v", err)
 	}
@@ -42,7 +42,7 @@ func TestRun2(t *testing.T) {
 
 	var wg sync.WaitGroup
 	wg.Add(goroutineCount)
-	rewardInfo := reward.GetAllRewardInfo(123456)
+	rewardInfo := reward.GetAllRewardInfo(context.Background(), 123456)
 	t.Logf("rrr: %+v", rewardInfo)
 
 	// 使用有缓冲通道控制并发速率
@@ -55,7 +55,7 @@ func TestRun2(t *testing.T) {
 			defer wg.Done()
 			defer func() { <-semaphore }() // 释放令牌
 			saveWithRetry(123456, i)
-			rewardInfo = reward.GetAllRewardInfo(123456)
+			rewardInfo = reward.GetAllRewardInfo(context.Background(), 123456)
 
 			//if err := saveWithRetry(123456, -1); err != nil {
 			//	t.Errorf("协程 %d 执行失败: %v", index, err)
@@ -71,7 +71,7 @@ func TestRun2(t *testing.T) {
 	close(semaphore)
 
 	// ========== 验证结果 ==========
-	rewardInfo = reward.GetAllRewardInfo(123456)
+	rewardInfo = reward.GetAllRewardInfo(context.Background(), 123456)
 
 	//finalItems, err := items.GetAllItems(123456)
 	//if err != nil {
@@ -136,6 +136,6 @@ func cleanup() {
 }
 
 func saveWithRetry(userId uint64, id int) {
-	reward.SaveRewardInfo(userId, id)
+	reward.SaveRewardInfo(context.Background(), userId, id)
 
 }
@@ -0,0 +1,57 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"gameServer/pkg/cache/ssdb"
+	"strconv"
+
+	"github.com/seefan/gossdb/v2/client"
+)
+
+const (
+	// 每个房间节点一个 hash，字段为房间 id，值为房间状态
+	snapshotKey = "RoomSnapshot:ServerId:%d"
+)
+
+func getSnapshotKey(serverId uint32) string {
+	return fmt.Sprintf(snapshotKey, serverId)
+}
+
+// Save 保存房间状态，覆盖上一次的状态
+func Save(ctx context.Context, serverId uint32, roomId int32, data []byte) error {
+	key := getSnapshotKey(serverId)
+	_, err := ssdb.Do(ctx, func() (struct{}, error) {
+		return struct{}{}, ssdb.GetClient().HSet(key, strconv.Itoa(int(roomId)), data)
+	})
+	return err
+}
+
+// Delete 删除房间状态，房间结束或已处理后调用
+func Delete(ctx context.Context, serverId uint32, roomId int32) error {
+	key := getSnapshotKey(serverId)
+	_, err := ssdb.Do(ctx, func() (struct{}, error) {
+		return struct{}{}, ssdb.GetClient().HDel(key, strconv.Itoa(int(roomId)))
+	})
+	return err
+}
+
+// LoadAll 节点上所有未结束房间的状态，房间 id-状态
+func LoadAll(ctx context.Context, serverId uint32) (map[int32][]byte, error) {
+	key := getSnapshotKey(serverId)
+	values, err := ssdb.Do(ctx, func() (map[string]client.Value, error) {
+		return ssdb.GetClient().HGetAll(key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[int32][]byte, len(values))
+	for field, value := range values {
+		roomId, err := strconv.ParseInt(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid room snapshot field: %s", field)
+		}
+		all[int32(roomId)] = value.Bytes()
+	}
+	return all, nil
+}
@@ -35,7 +35,7 @@ func TestRun2(t *testing.T) {
 	defer cleanup()
 
 	// ========== 获取数据 ==========
-	allItems, err := items.GetAllItems(123456)
+	allItems, err := items.GetAllItems(context.Background(), 123456)
 	if err != nil {
 		t.Fatalf("获取物品失败: %v", err)
 	}
@@ -76,7 +76,7 @@ func TestRun2(t *testing.T) {
 	close(semaphore)
 
 	// ========== 验证结果 ==========
-	cache, err := user.GetAllUserNoCache()
+	cache, err := user.GetAllUserNoCache(context.Background())
 	if err != nil {
 		return
 	}
@@ -143,7 +143,7 @@ func cleanup() {
 }
 
 func saveWithRetry(openid, loginType string) error {
-	_, err := user.CreateUser(openid, loginType)
+	_, err := user.CreateUser(context.Background(), openid, loginType)
 	if err != nil {
 		panic(err)
 		return err
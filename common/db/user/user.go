@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"fmt"
 	cachex "gameServer/pkg/cache/cacheX"
 	"gameServer/pkg/cache/ssdb"
@@ -8,6 +9,8 @@ import (
 	"gameServer/pkg/random/snowflake"
 	"strconv"
 	"time"
+
+	"github.com/seefan/gossdb/v2/client"
 )
 
 const (
@@ -55,8 +58,10 @@ func getUserInfoKey(userId uint64) string {
 }
 
 // 查 ol
-func FindOL(openid, loginType string) (error, *OL) {
-	ol, err := olCache.Get(GetOLKey(openid, loginType))
+func FindOL(ctx context.Context, openid, loginType string) (error, *OL) {
+	ol, err := ssdb.Do(ctx, func() (*OL, error) {
+		return olCache.Get(GetOLKey(openid, loginType))
+	})
 	if err != nil {
 		return err, nil
 	}
@@ -64,9 +69,11 @@ func FindOL(openid, loginType string) (error, *OL) {
 }
 
 // 查表数据 userid
-func FindUser(userId uint64) (*UserInfo, error) {
+func FindUser(ctx context.Context, userId uint64) (*UserInfo, error) {
 	userIdKey := getUserInfoKey(userId)
-	userInfo, err := userInfoCache.Get(userIdKey)
+	userInfo, err := ssdb.Do(ctx, func() (*UserInfo, error) {
+		return userInfoCache.Get(userIdKey)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +89,10 @@ func FindUser(userId uint64) (*UserInfo, error) {
 //	return userList, nil
 //}
 
-func GetAllUserNoCache() (map[uint64]struct{}, error) {
-	dbVal, err := ssdb.GetClient().HGetAll(allUser)
+func GetAllUserNoCache(ctx context.Context) (map[uint64]struct{}, error) {
+	dbVal, err := ssdb.Do(ctx, func() (map[string]client.Value, error) {
+		return ssdb.GetClient().HGetAll(allUser)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +108,7 @@ func GetAllUserNoCache() (map[uint64]struct{}, error) {
 	return allUserMap, nil
 }
 
-func CreateUser(openid, loginType string) (*UserInfo, error) {
+func CreateUser(ctx context.Context, openid, loginType string) (*UserInfo, error) {
 	olKey := GetOLKey(openid, loginType)
 	userId := node.Generate()
 	ol := &OL{
@@ -117,20 +126,23 @@ func CreateUser(openid, loginType string) (*UserInfo, error) {
 		Openid:         openid,
 		CreatTimestamp: uint64(time.Now().UnixMilli()),
 	}
-	// 写入ol
-	err = olCache.SetNX(olKey, ol)
-	if err != nil {
-		return nil, err
-	}
-	err = userInfoCache.SetNX(getUserInfoKey(userId), userInfo)
-	if err != nil {
-		return nil, err
-	}
+	// 三次写入在同一次 Do 中完成，开始后不会因为 ctx 过期只写入一部分
+	return ssdb.Do(ctx, func() (*UserInfo, error) {
+		// 写入ol
+		err := olCache.SetNX(olKey, ol)
+		if err != nil {
+			return nil, err
+		}
+		err = userInfoCache.SetNX(getUserInfoKey(userId), userInfo)
+		if err != nil {
+			return nil, err
+		}
 
-	// 4. 加入 AllUser（Hash模拟Set）
-	err = ssdb.GetClient().HSet(allUser, strconv.FormatUint(ol.UserId, 10), 1)
-	if err != nil {
-		return nil, err
-	}
-	return userInfo, nil
+		// 4. 加入 AllUser（Hash模拟Set）
+		err = ssdb.GetClient().HSet(allUser, strconv.FormatUint(ol.UserId, 10), 1)
+		if err != nil {
+			return nil, err
+		}
+		return userInfo, nil
+	})
 }
@@ -19,18 +19,36 @@ const (
 	ErrorCode_CreatUserFailed     uint16 = 116 // 创建用户失败
 	ErrorCode_DBError             uint16 = 117 // 数据库错误
 	ErrorCode_ReqIsNull           uint16 = 118 // 请求参数是null
+	ErrorCode_Timeout             uint16 = 119 // 请求超时
 
 	//config
 	ErrorCode_GetConfigFailed uint16 = 200 // 获取配置失败
 	ErrorCode_ConfigError     uint16 = 201 // 获取配置有误
 
 	// room
-	ErrorCode_NotJoinRoom    uint16 = 1000 // 没有加入任何房间
-	ErrorCode_UseNullItem    uint16 = 1001 // 使用空道具
-	ErrorCode_ReusingItem    uint16 = 1002 // 重复使用道具
-	ErrorCode_UseItemNumErr  uint16 = 1003 // 重复使用道具数量有错误
-	ErrorCode_AlreadyBet     uint16 = 1004 //已经竞拍过了
-	ErrorCode_AlreadyAbstain uint16 = 1005 //已经竞拍弃权了
+	ErrorCode_NotJoinRoom     uint16 = 1000 // 没有加入任何房间
+	ErrorCode_UseNullItem     uint16 = 1001 // 使用空道具
+	ErrorCode_ReusingItem     uint16 = 1002 // 重复使用道具
+	ErrorCode_UseItemNumErr   uint16 = 1003 // 重复使用道具数量有错误
+	ErrorCode_AlreadyBet      uint16 = 1004 //已经竞拍过了
+	ErrorCode_AlreadyAbstain  uint16 = 1005 //已经竞拍弃权了
+	ErrorCode_RoomNotFound    uint16 = 1006 // 房间不存在或已结束
+	ErrorCode_SpectatorFull   uint16 = 1007 // 观战人数已满
+	ErrorCode_InRoom          uint16 = 1008 // 正在房间中竞拍
+	ErrorCode_RoomFull        uint16 = 1009 // 房间已满
+	ErrorCode_RoomStarted     uint16 = 1010 // 房间已开始
+	ErrorCode_NotRoomOwner    uint16 = 1011 // 不是房主
+	ErrorCode_RuleOutOfRange  uint16 = 1012 // 自定义规则超出配置范围
+	ErrorCode_PlayerNotEnough uint16 = 1013 // 人数不足，不能开始
+	ErrorCode_InParty         uint16 = 1014 // 已在队伍中
+	ErrorCode_PartyNotFound   uint16 = 1015 // 队伍不存在或已解散
+	ErrorCode_NotPartyLeader  uint16 = 1016 // 不是队长
+	ErrorCode_PartyFull       uint16 = 1017 // 队伍已满
+	ErrorCode_NotInvited      uint16 = 1018 // 没有被邀请
+	ErrorCode_PartyMatching   uint16 = 1019 // 队伍正在匹配
+	ErrorCode_PartyTooLarge   uint16 = 1020 // 队伍人数超过房间人数上限
+	ErrorCode_PartyDisabled   uint16 = 1021 // 组队未开启
+	ErrorCode_Moved           uint16 = 1022 // 房间在其它节点，网关已改为转发到该节点，由网关重新转发
 
 	// item
 	ErrorCode_ItemNotEnough uint16 = 2000 // 道具不足
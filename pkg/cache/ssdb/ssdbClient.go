@@ -1,6 +1,7 @@
 package ssdb
 
 import (
+	"context"
 	"errors"
 
 	"github.com/seefan/gossdb/v2"
@@ -73,3 +74,25 @@ func GetClient() *pool.Client {
 func Close() {
 	ssdbPool.Close()
 }
+
+// Do 在 ctx 未过期时执行一次 ssdb 操作
+//
+// gossdb 本身不支持 context，单次读写的最长时间由连接配置的 ReadWriteTimeout 限制。
+// ctx 只在开始前检查：已开始的操作不会被放弃，返回的一定是实际执行的结果，
+// 避免写入在后台完成而调用方已按失败处理。多次写入需要一起完成时放在同一个 fn 中。
+func Do[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+	return fn()
+}
+
+// Detach 开始一组需要一起完成的写入：ctx 已过期时直接返回错误，
+// 否则返回忽略取消的 ctx，后续每一步都会执行完，不会在期限到达时只写入一半
+func Detach(ctx context.Context) (context.Context, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return context.WithoutCancel(ctx), nil
+}
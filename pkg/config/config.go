@@ -6,6 +6,7 @@ import (
 	"gameServer/pkg/logger/log2"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
@@ -18,10 +19,14 @@ import (
 // - service: 一个服务内通用的部分，例如在 [gate] 中的配置
 // - node: 每一个服务单独使用的部分，例如在 [gate-1], [gate-2] 中的配置
 type Config struct {
+	v           *viper.Viper
 	common      *viper.Viper
 	service     *viper.Viper
 	node        *viper.Viper
+	nodeName    string
 	serviceName string
+	// overrides 代码中覆盖的配置项，完整路径-值，重新加载后仍然生效
+	overrides map[string]any
 
 	// isTest 当前是否是测试用例
 	isTest bool
@@ -59,7 +64,12 @@ func Init(nodeName, serviceName, filePath, fileName, fileType string) error {
 	return nil
 }
 
-// New 读取配置
+// SetDefault 设置全局配置，同一进程运行多个服务时，config.Get() 返回其中一个服务的配置
+func SetDefault(c *Config) {
+	globalConfig = c
+}
+
+// New 读取配置，每次调用独立读取，同一进程内可以同时存在多个服务的配置
 //
 // - nodeName: 节点名字，eg: gate-1, gate-2, robot-1
 // - serviceName: 服务名称，eg: gate, robot, role
@@ -74,23 +84,26 @@ func New(nodeName, serviceName, filePath, fileName, fileType string) (*Config, e
 		return nil, errors.New("miss fileName")
 	}
 
-	viper.SetConfigName(fileName)
-	viper.SetConfigType(fileType)
-	viper.AddConfigPath(filePath)
+	v := viper.New()
+	v.SetConfigName(fileName)
+	v.SetConfigType(fileType)
+	v.AddConfigPath(filePath)
 
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		return nil, err
 	}
-	v := viper.GetViper()
 
 	c := &Config{
+		v:           v,
+		nodeName:    nodeName,
 		serviceName: serviceName,
+		overrides:   make(map[string]any),
 	}
-	c.read(v, nodeName, serviceName)
+	c.read()
 
-	viper.WatchConfig()
-	viper.OnConfigChange(func(_ fsnotify.Event) {
-		c.read(v, nodeName, serviceName)
+	v.WatchConfig()
+	v.OnConfigChange(func(_ fsnotify.Event) {
+		c.read()
 
 		log2.Get().Info("config reload")
 	})
@@ -98,18 +111,39 @@ func New(nodeName, serviceName, filePath, fileName, fileType string) (*Config, e
 	return c, nil
 }
 
-func (c *Config) read(viper *viper.Viper, nodeName, serviceName string) {
+// Set 覆盖配置项，优先于配置文件
+//
+//   - key: 完整路径，第一段为 common、服务名称或节点名称，eg: common.rpclocal
+func (c *Config) Set(key string, value any) {
+	c.overrides[strings.ToLower(key)] = value
+	c.read()
+}
+
+func (c *Config) read() {
 	// 通用配置
-	c.common = viper.Sub("common")
+	c.common = c.v.Sub("common")
 
 	// 服务配置
-	if len(serviceName) > 0 {
-		c.service = viper.Sub(serviceName)
+	if len(c.serviceName) > 0 {
+		c.service = c.v.Sub(c.serviceName)
 	}
 
 	// 节点配置
-	if len(nodeName) > 0 {
-		c.node = viper.Sub(nodeName)
+	if len(c.nodeName) > 0 {
+		c.node = c.v.Sub(c.nodeName)
+	}
+
+	// viper 对整段的 Sub 不合并 Set 的值，直接写到各段
+	for key, value := range c.overrides {
+		section, field, _ := strings.Cut(key, ".")
+		switch section {
+		case "common":
+			c.common.Set(field, value)
+		case strings.ToLower(c.serviceName):
+			c.service.Set(field, value)
+		case strings.ToLower(c.nodeName):
+			c.node.Set(field, value)
+		}
 	}
 }
 
@@ -120,6 +154,9 @@ func (c *Config) Common() *viper.Viper {
 
 // Service 服务配置
 func (c *Config) Service() *viper.Viper {
+	if c == nil { // 未初始化，如离线工具
+		return nil
+	}
 	return c.service
 }
 
@@ -254,6 +291,36 @@ func (c *Config) RPCHeart() int {
 	return c.common.GetInt("rpcheart")
 }
 
+// RPCLocal 使用进程内 rpc 传输，网关和节点在同一进程内运行，不依赖 etcd
+func (c *Config) RPCLocal() bool {
+	return c.common.GetBool("rpclocal")
+}
+
+// RPCCodec 服务间 rpc 序列化方式，protobuf 或 msgpack，未配置时为 protobuf
+func (c *Config) RPCCodec() string {
+	return c.common.GetString("rpccodec")
+}
+
+// RPCTimeout 网关转发请求的默认超时，未配置时为 3 秒
+func (c *Config) RPCTimeout() time.Duration {
+	if c.service != nil {
+		if v := c.service.GetInt64("rpctimeout"); v > 0 {
+			return time.Duration(v) * time.Millisecond
+		}
+	}
+	return 3 * time.Second
+}
+
+// ProtocolTimeout 指定协议的超时，在 [gate.protocoltimeout] 中按协议号配置，单位毫秒，未配置时使用 RPCTimeout
+func (c *Config) ProtocolTimeout(protocol uint16) time.Duration {
+	if c.service != nil {
+		if v := c.service.GetInt64("protocoltimeout." + strconv.Itoa(int(protocol))); v > 0 {
+			return time.Duration(v) * time.Millisecond
+		}
+	}
+	return c.RPCTimeout()
+}
+
 // ServiceName 服务名称，如 gate, game
 func (c *Config) ServiceName() string {
 	return c.serviceName
@@ -274,11 +341,34 @@ func (c *Config) NodeName() string {
 	return c.node.GetString("name")
 }
 
+// ServiceGroup 服务组名，写入 etcd 元数据用于路由，未配置 group 时为服务名称
+func (c *Config) ServiceGroup() string {
+	if c.service != nil {
+		if v := c.service.GetString("group"); v != "" {
+			return v
+		}
+	}
+	return c.serviceName
+}
+
+// NodeWeight 节点容量权重，未配置时为 100
+func (c *Config) NodeWeight() int {
+	if v := c.node.GetInt("weight"); v > 0 {
+		return v
+	}
+	return 100
+}
+
 // NodeVersion 节点版本号
 func (c *Config) NodeVersion() uint32 {
 	return c.node.GetUint32("version")
 }
 
+// NodeRPCAddress 节点 rpc 监听地址，如 127.0.0.1:9101，未配置时使用随机端口
+func (c *Config) NodeRPCAddress() string {
+	return c.node.GetString("rpcaddr")
+}
+
 // PProfAddress pprof 地址
 func (c *Config) PProfAddress() string {
 	return c.node.GetString("pprofaddr")
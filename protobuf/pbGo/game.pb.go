@@ -537,6 +537,1585 @@ func (x *UseItemResp) GetHintList() []*Hint {
 	return nil
 }
 
+// 房间快照请求 1007，断线重连后获取当前房间的全部状态，据此重绘
+type GetRoomSnapshotReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetRoomSnapshotReq) Reset() {
+	*x = GetRoomSnapshotReq{}
+	mi := &file_game_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomSnapshotReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomSnapshotReq) ProtoMessage() {}
+
+func (x *GetRoomSnapshotReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomSnapshotReq.ProtoReflect.Descriptor instead.
+func (*GetRoomSnapshotReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{10}
+}
+
+type GetRoomSnapshotResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomType       uint32          `protobuf:"varint,1,opt,name=roomType,proto3" json:"roomType,omitempty"`                  // 房间类型
+	RoundIndex     uint32          `protobuf:"varint,2,opt,name=roundIndex,proto3" json:"roundIndex,omitempty"`              //第几轮
+	EndTimeOut     int64           `protobuf:"varint,3,opt,name=endTimeOut,proto3" json:"endTimeOut,omitempty"`              //本局结束倒计时，与 roundInfoPush 一致
+	ScreenInfo     *ScreenInfo     `protobuf:"bytes,4,opt,name=screenInfo,proto3" json:"screenInfo,omitempty"`               //对本玩家已显示的所有物品和格子
+	PlayerInfoList []*PlayerInfo   `protobuf:"bytes,5,rep,name=playerInfoList,proto3" json:"playerInfoList,omitempty"`       //房间内所有玩家
+	BetHistory     []*RoundBetInfo `protobuf:"bytes,6,rep,name=betHistory,proto3" json:"betHistory,omitempty"`               //已结束回合的竞拍记录
+	BetUserIdList  []uint64        `protobuf:"varint,7,rep,packed,name=betUserIdList,proto3" json:"betUserIdList,omitempty"` //本回合已竞拍的玩家
+}
+
+func (x *GetRoomSnapshotResp) Reset() {
+	*x = GetRoomSnapshotResp{}
+	mi := &file_game_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomSnapshotResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomSnapshotResp) ProtoMessage() {}
+
+func (x *GetRoomSnapshotResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomSnapshotResp.ProtoReflect.Descriptor instead.
+func (*GetRoomSnapshotResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetRoomSnapshotResp) GetRoomType() uint32 {
+	if x != nil {
+		return x.RoomType
+	}
+	return 0
+}
+
+func (x *GetRoomSnapshotResp) GetRoundIndex() uint32 {
+	if x != nil {
+		return x.RoundIndex
+	}
+	return 0
+}
+
+func (x *GetRoomSnapshotResp) GetEndTimeOut() int64 {
+	if x != nil {
+		return x.EndTimeOut
+	}
+	return 0
+}
+
+func (x *GetRoomSnapshotResp) GetScreenInfo() *ScreenInfo {
+	if x != nil {
+		return x.ScreenInfo
+	}
+	return nil
+}
+
+func (x *GetRoomSnapshotResp) GetPlayerInfoList() []*PlayerInfo {
+	if x != nil {
+		return x.PlayerInfoList
+	}
+	return nil
+}
+
+func (x *GetRoomSnapshotResp) GetBetHistory() []*RoundBetInfo {
+	if x != nil {
+		return x.BetHistory
+	}
+	return nil
+}
+
+func (x *GetRoomSnapshotResp) GetBetUserIdList() []uint64 {
+	if x != nil {
+		return x.BetUserIdList
+	}
+	return nil
+}
+
+// 一个回合的竞拍记录
+type RoundBetInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoundIndex     uint32        `protobuf:"varint,1,opt,name=roundIndex,proto3" json:"roundIndex,omitempty"`        //第几轮
+	PlayerInfoList []*PlayerInfo `protobuf:"bytes,2,rep,name=playerInfoList,proto3" json:"playerInfoList,omitempty"` //玩家竞拍花费和使用的道具
+}
+
+func (x *RoundBetInfo) Reset() {
+	*x = RoundBetInfo{}
+	mi := &file_game_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoundBetInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoundBetInfo) ProtoMessage() {}
+
+func (x *RoundBetInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoundBetInfo.ProtoReflect.Descriptor instead.
+func (*RoundBetInfo) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RoundBetInfo) GetRoundIndex() uint32 {
+	if x != nil {
+		return x.RoundIndex
+	}
+	return 0
+}
+
+func (x *RoundBetInfo) GetPlayerInfoList() []*PlayerInfo {
+	if x != nil {
+		return x.PlayerInfoList
+	}
+	return nil
+}
+
+// 可观战房间列表 1008，只包含处理请求的房间节点上的房间
+type SpectateRoomListReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomType uint32 `protobuf:"varint,1,opt,name=roomType,proto3" json:"roomType,omitempty"` // 房间类型
+}
+
+func (x *SpectateRoomListReq) Reset() {
+	*x = SpectateRoomListReq{}
+	mi := &file_game_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpectateRoomListReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpectateRoomListReq) ProtoMessage() {}
+
+func (x *SpectateRoomListReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpectateRoomListReq.ProtoReflect.Descriptor instead.
+func (*SpectateRoomListReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SpectateRoomListReq) GetRoomType() uint32 {
+	if x != nil {
+		return x.RoomType
+	}
+	return 0
+}
+
+type SpectateRoomListResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomList []*SpectateRoom `protobuf:"bytes,1,rep,name=roomList,proto3" json:"roomList,omitempty"`
+}
+
+func (x *SpectateRoomListResp) Reset() {
+	*x = SpectateRoomListResp{}
+	mi := &file_game_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpectateRoomListResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpectateRoomListResp) ProtoMessage() {}
+
+func (x *SpectateRoomListResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpectateRoomListResp.ProtoReflect.Descriptor instead.
+func (*SpectateRoomListResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SpectateRoomListResp) GetRoomList() []*SpectateRoom {
+	if x != nil {
+		return x.RoomList
+	}
+	return nil
+}
+
+// 可观战房间
+type SpectateRoom struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId         int32         `protobuf:"varint,1,opt,name=roomId,proto3" json:"roomId,omitempty"`
+	RoomType       uint32        `protobuf:"varint,2,opt,name=roomType,proto3" json:"roomType,omitempty"`
+	PlayerInfoList []*PlayerInfo `protobuf:"bytes,3,rep,name=playerInfoList,proto3" json:"playerInfoList,omitempty"`  //竞拍的玩家
+	SpectatorCount uint32        `protobuf:"varint,4,opt,name=spectatorCount,proto3" json:"spectatorCount,omitempty"` //观战人数
+	ServerId       uint32        `protobuf:"varint,5,opt,name=serverId,proto3" json:"serverId,omitempty"`             //房间所在节点
+}
+
+func (x *SpectateRoom) Reset() {
+	*x = SpectateRoom{}
+	mi := &file_game_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpectateRoom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpectateRoom) ProtoMessage() {}
+
+func (x *SpectateRoom) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpectateRoom.ProtoReflect.Descriptor instead.
+func (*SpectateRoom) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SpectateRoom) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *SpectateRoom) GetRoomType() uint32 {
+	if x != nil {
+		return x.RoomType
+	}
+	return 0
+}
+
+func (x *SpectateRoom) GetPlayerInfoList() []*PlayerInfo {
+	if x != nil {
+		return x.PlayerInfoList
+	}
+	return nil
+}
+
+func (x *SpectateRoom) GetSpectatorCount() uint32 {
+	if x != nil {
+		return x.SpectatorCount
+	}
+	return 0
+}
+
+func (x *SpectateRoom) GetServerId() uint32 {
+	if x != nil {
+		return x.ServerId
+	}
+	return 0
+}
+
+// 观战请求 1009，加入后推送 MatchInfoPush、回合信息和结算，只能看到所有玩家都已看到的物品信息
+type JoinSpectateReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId   int32  `protobuf:"varint,1,opt,name=roomId,proto3" json:"roomId,omitempty"`     // 房间id
+	UserId   uint64 `protobuf:"varint,2,opt,name=userId,proto3" json:"userId,omitempty"`     // 观看该玩家所在的房间，roomId 为 0 时使用
+	ServerId uint32 `protobuf:"varint,3,opt,name=serverId,proto3" json:"serverId,omitempty"` // 房间所在节点，与 roomId 一起使用，见 spectateRoom
+}
+
+func (x *JoinSpectateReq) Reset() {
+	*x = JoinSpectateReq{}
+	mi := &file_game_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinSpectateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinSpectateReq) ProtoMessage() {}
+
+func (x *JoinSpectateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinSpectateReq.ProtoReflect.Descriptor instead.
+func (*JoinSpectateReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *JoinSpectateReq) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *JoinSpectateReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *JoinSpectateReq) GetServerId() uint32 {
+	if x != nil {
+		return x.ServerId
+	}
+	return 0
+}
+
+type JoinSpectateResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Snapshot *GetRoomSnapshotResp `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"` //房间当前状态
+}
+
+func (x *JoinSpectateResp) Reset() {
+	*x = JoinSpectateResp{}
+	mi := &file_game_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinSpectateResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinSpectateResp) ProtoMessage() {}
+
+func (x *JoinSpectateResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinSpectateResp.ProtoReflect.Descriptor instead.
+func (*JoinSpectateResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *JoinSpectateResp) GetSnapshot() *GetRoomSnapshotResp {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+// 退出观战 1010
+type LeaveSpectateReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LeaveSpectateReq) Reset() {
+	*x = LeaveSpectateReq{}
+	mi := &file_game_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveSpectateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveSpectateReq) ProtoMessage() {}
+
+func (x *LeaveSpectateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveSpectateReq.ProtoReflect.Descriptor instead.
+func (*LeaveSpectateReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{18}
+}
+
+type LeaveSpectateResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LeaveSpectateResp) Reset() {
+	*x = LeaveSpectateResp{}
+	mi := &file_game_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveSpectateResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveSpectateResp) ProtoMessage() {}
+
+func (x *LeaveSpectateResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveSpectateResp.ProtoReflect.Descriptor instead.
+func (*LeaveSpectateResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{19}
+}
+
+// 创建私人房间 1011，房主通过邀请码邀请好友，手动开始；规则为 0 时使用配置表的值
+type CreatePrivateRoomReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomType     uint32      `protobuf:"varint,1,opt,name=roomType,proto3" json:"roomType,omitempty"`        // 房间类型,对应配置表roomId
+	HeroId       uint32      `protobuf:"varint,2,opt,name=heroId,proto3" json:"heroId,omitempty"`            // 选择的人物
+	ItemInfoList []*ItemInfo `protobuf:"bytes,3,rep,name=itemInfoList,proto3" json:"itemInfoList,omitempty"` //选择的道具
+	RoundLimit   uint32      `protobuf:"varint,4,opt,name=roundLimit,proto3" json:"roundLimit,omitempty"`    // 最大局数
+	Timeout      uint32      `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"`          // 操作超时 秒
+	RobotFill    bool        `protobuf:"varint,6,opt,name=robotFill,proto3" json:"robotFill,omitempty"`      // 开始时用机器人补满空位
+}
+
+func (x *CreatePrivateRoomReq) Reset() {
+	*x = CreatePrivateRoomReq{}
+	mi := &file_game_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePrivateRoomReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePrivateRoomReq) ProtoMessage() {}
+
+func (x *CreatePrivateRoomReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePrivateRoomReq.ProtoReflect.Descriptor instead.
+func (*CreatePrivateRoomReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CreatePrivateRoomReq) GetRoomType() uint32 {
+	if x != nil {
+		return x.RoomType
+	}
+	return 0
+}
+
+func (x *CreatePrivateRoomReq) GetHeroId() uint32 {
+	if x != nil {
+		return x.HeroId
+	}
+	return 0
+}
+
+func (x *CreatePrivateRoomReq) GetItemInfoList() []*ItemInfo {
+	if x != nil {
+		return x.ItemInfoList
+	}
+	return nil
+}
+
+func (x *CreatePrivateRoomReq) GetRoundLimit() uint32 {
+	if x != nil {
+		return x.RoundLimit
+	}
+	return 0
+}
+
+func (x *CreatePrivateRoomReq) GetTimeout() uint32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *CreatePrivateRoomReq) GetRobotFill() bool {
+	if x != nil {
+		return x.RobotFill
+	}
+	return false
+}
+
+type CreatePrivateRoomResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Room *PrivateRoomInfo `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+}
+
+func (x *CreatePrivateRoomResp) Reset() {
+	*x = CreatePrivateRoomResp{}
+	mi := &file_game_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePrivateRoomResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePrivateRoomResp) ProtoMessage() {}
+
+func (x *CreatePrivateRoomResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePrivateRoomResp.ProtoReflect.Descriptor instead.
+func (*CreatePrivateRoomResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CreatePrivateRoomResp) GetRoom() *PrivateRoomInfo {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+// 通过邀请码加入私人房间 1012，只能加入处理请求的房间节点上的房间
+type JoinPrivateRoomReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code         string      `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`                 // 邀请码
+	HeroId       uint32      `protobuf:"varint,2,opt,name=heroId,proto3" json:"heroId,omitempty"`            // 选择的人物
+	ItemInfoList []*ItemInfo `protobuf:"bytes,3,rep,name=itemInfoList,proto3" json:"itemInfoList,omitempty"` //选择的道具
+}
+
+func (x *JoinPrivateRoomReq) Reset() {
+	*x = JoinPrivateRoomReq{}
+	mi := &file_game_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinPrivateRoomReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinPrivateRoomReq) ProtoMessage() {}
+
+func (x *JoinPrivateRoomReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinPrivateRoomReq.ProtoReflect.Descriptor instead.
+func (*JoinPrivateRoomReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *JoinPrivateRoomReq) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *JoinPrivateRoomReq) GetHeroId() uint32 {
+	if x != nil {
+		return x.HeroId
+	}
+	return 0
+}
+
+func (x *JoinPrivateRoomReq) GetItemInfoList() []*ItemInfo {
+	if x != nil {
+		return x.ItemInfoList
+	}
+	return nil
+}
+
+type JoinPrivateRoomResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Room *PrivateRoomInfo `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+}
+
+func (x *JoinPrivateRoomResp) Reset() {
+	*x = JoinPrivateRoomResp{}
+	mi := &file_game_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinPrivateRoomResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinPrivateRoomResp) ProtoMessage() {}
+
+func (x *JoinPrivateRoomResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinPrivateRoomResp.ProtoReflect.Descriptor instead.
+func (*JoinPrivateRoomResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *JoinPrivateRoomResp) GetRoom() *PrivateRoomInfo {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+// 房主开始私人房间 1013，开始后推送 MatchInfoPush，与匹配的房间相同
+type StartPrivateRoomReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StartPrivateRoomReq) Reset() {
+	*x = StartPrivateRoomReq{}
+	mi := &file_game_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartPrivateRoomReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartPrivateRoomReq) ProtoMessage() {}
+
+func (x *StartPrivateRoomReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartPrivateRoomReq.ProtoReflect.Descriptor instead.
+func (*StartPrivateRoomReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{24}
+}
+
+type StartPrivateRoomResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StartPrivateRoomResp) Reset() {
+	*x = StartPrivateRoomResp{}
+	mi := &file_game_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartPrivateRoomResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartPrivateRoomResp) ProtoMessage() {}
+
+func (x *StartPrivateRoomResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartPrivateRoomResp.ProtoReflect.Descriptor instead.
+func (*StartPrivateRoomResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{25}
+}
+
+// 离开未开始的私人房间 1014，房主离开时由下一个玩家成为房主
+type LeavePrivateRoomReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LeavePrivateRoomReq) Reset() {
+	*x = LeavePrivateRoomReq{}
+	mi := &file_game_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeavePrivateRoomReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeavePrivateRoomReq) ProtoMessage() {}
+
+func (x *LeavePrivateRoomReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeavePrivateRoomReq.ProtoReflect.Descriptor instead.
+func (*LeavePrivateRoomReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{26}
+}
+
+type LeavePrivateRoomResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LeavePrivateRoomResp) Reset() {
+	*x = LeavePrivateRoomResp{}
+	mi := &file_game_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeavePrivateRoomResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeavePrivateRoomResp) ProtoMessage() {}
+
+func (x *LeavePrivateRoomResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeavePrivateRoomResp.ProtoReflect.Descriptor instead.
+func (*LeavePrivateRoomResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{27}
+}
+
+// 私人房间成员变化推送 1015
+type PrivateRoomPush struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Room *PrivateRoomInfo `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+}
+
+func (x *PrivateRoomPush) Reset() {
+	*x = PrivateRoomPush{}
+	mi := &file_game_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrivateRoomPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrivateRoomPush) ProtoMessage() {}
+
+func (x *PrivateRoomPush) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrivateRoomPush.ProtoReflect.Descriptor instead.
+func (*PrivateRoomPush) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *PrivateRoomPush) GetRoom() *PrivateRoomInfo {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+// 私人房间信息
+type PrivateRoomInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code           string        `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"` // 邀请码
+	RoomType       uint32        `protobuf:"varint,2,opt,name=roomType,proto3" json:"roomType,omitempty"`
+	OwnerId        uint64        `protobuf:"varint,3,opt,name=ownerId,proto3" json:"ownerId,omitempty"`              // 房主
+	RoundLimit     uint32        `protobuf:"varint,4,opt,name=roundLimit,proto3" json:"roundLimit,omitempty"`        // 最大局数
+	Timeout        uint32        `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"`              // 操作超时 秒
+	RobotFill      bool          `protobuf:"varint,6,opt,name=robotFill,proto3" json:"robotFill,omitempty"`          // 开始时用机器人补满空位
+	Capacity       uint32        `protobuf:"varint,7,opt,name=capacity,proto3" json:"capacity,omitempty"`            // 人数上限
+	PlayerInfoList []*PlayerInfo `protobuf:"bytes,8,rep,name=playerInfoList,proto3" json:"playerInfoList,omitempty"` //房间中的玩家
+}
+
+func (x *PrivateRoomInfo) Reset() {
+	*x = PrivateRoomInfo{}
+	mi := &file_game_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrivateRoomInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrivateRoomInfo) ProtoMessage() {}
+
+func (x *PrivateRoomInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrivateRoomInfo.ProtoReflect.Descriptor instead.
+func (*PrivateRoomInfo) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PrivateRoomInfo) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *PrivateRoomInfo) GetRoomType() uint32 {
+	if x != nil {
+		return x.RoomType
+	}
+	return 0
+}
+
+func (x *PrivateRoomInfo) GetOwnerId() uint64 {
+	if x != nil {
+		return x.OwnerId
+	}
+	return 0
+}
+
+func (x *PrivateRoomInfo) GetRoundLimit() uint32 {
+	if x != nil {
+		return x.RoundLimit
+	}
+	return 0
+}
+
+func (x *PrivateRoomInfo) GetTimeout() uint32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *PrivateRoomInfo) GetRobotFill() bool {
+	if x != nil {
+		return x.RobotFill
+	}
+	return false
+}
+
+func (x *PrivateRoomInfo) GetCapacity() uint32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *PrivateRoomInfo) GetPlayerInfoList() []*PlayerInfo {
+	if x != nil {
+		return x.PlayerInfoList
+	}
+	return nil
+}
+
+// 创建队伍 1016，队员在队伍中各自发送 startMatchReq 准备，全部准备后一起匹配，进入同一个房间
+type CreatePartyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CreatePartyReq) Reset() {
+	*x = CreatePartyReq{}
+	mi := &file_game_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePartyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePartyReq) ProtoMessage() {}
+
+func (x *CreatePartyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePartyReq.ProtoReflect.Descriptor instead.
+func (*CreatePartyReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{30}
+}
+
+type CreatePartyResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Party *PartyInfo `protobuf:"bytes,1,opt,name=party,proto3" json:"party,omitempty"`
+}
+
+func (x *CreatePartyResp) Reset() {
+	*x = CreatePartyResp{}
+	mi := &file_game_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePartyResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePartyResp) ProtoMessage() {}
+
+func (x *CreatePartyResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePartyResp.ProtoReflect.Descriptor instead.
+func (*CreatePartyResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CreatePartyResp) GetParty() *PartyInfo {
+	if x != nil {
+		return x.Party
+	}
+	return nil
+}
+
+// 队长邀请玩家 1017，被邀请的玩家收到 partyInvitePush
+type InvitePartyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId uint64 `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"` // 被邀请的玩家
+}
+
+func (x *InvitePartyReq) Reset() {
+	*x = InvitePartyReq{}
+	mi := &file_game_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvitePartyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvitePartyReq) ProtoMessage() {}
+
+func (x *InvitePartyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvitePartyReq.ProtoReflect.Descriptor instead.
+func (*InvitePartyReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *InvitePartyReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type InvitePartyResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *InvitePartyResp) Reset() {
+	*x = InvitePartyResp{}
+	mi := &file_game_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvitePartyResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvitePartyResp) ProtoMessage() {}
+
+func (x *InvitePartyResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvitePartyResp.ProtoReflect.Descriptor instead.
+func (*InvitePartyResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{33}
+}
+
+// 接受邀请加入队伍 1018
+type AcceptPartyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PartyId uint64 `protobuf:"varint,1,opt,name=partyId,proto3" json:"partyId,omitempty"` // 队伍id
+}
+
+func (x *AcceptPartyReq) Reset() {
+	*x = AcceptPartyReq{}
+	mi := &file_game_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptPartyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptPartyReq) ProtoMessage() {}
+
+func (x *AcceptPartyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptPartyReq.ProtoReflect.Descriptor instead.
+func (*AcceptPartyReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AcceptPartyReq) GetPartyId() uint64 {
+	if x != nil {
+		return x.PartyId
+	}
+	return 0
+}
+
+type AcceptPartyResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Party *PartyInfo `protobuf:"bytes,1,opt,name=party,proto3" json:"party,omitempty"`
+}
+
+func (x *AcceptPartyResp) Reset() {
+	*x = AcceptPartyResp{}
+	mi := &file_game_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptPartyResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptPartyResp) ProtoMessage() {}
+
+func (x *AcceptPartyResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptPartyResp.ProtoReflect.Descriptor instead.
+func (*AcceptPartyResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *AcceptPartyResp) GetParty() *PartyInfo {
+	if x != nil {
+		return x.Party
+	}
+	return nil
+}
+
+// 离开队伍 1019，匹配中时取消整个队伍的匹配，队长离开时由下一个队员成为队长
+type LeavePartyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LeavePartyReq) Reset() {
+	*x = LeavePartyReq{}
+	mi := &file_game_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeavePartyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeavePartyReq) ProtoMessage() {}
+
+func (x *LeavePartyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeavePartyReq.ProtoReflect.Descriptor instead.
+func (*LeavePartyReq) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{36}
+}
+
+type LeavePartyResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LeavePartyResp) Reset() {
+	*x = LeavePartyResp{}
+	mi := &file_game_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeavePartyResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeavePartyResp) ProtoMessage() {}
+
+func (x *LeavePartyResp) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeavePartyResp.ProtoReflect.Descriptor instead.
+func (*LeavePartyResp) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{37}
+}
+
+// 队伍变化推送 1020，成员、准备和匹配状态变化时推送给所有队员
+type PartyPush struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Party *PartyInfo `protobuf:"bytes,1,opt,name=party,proto3" json:"party,omitempty"`
+}
+
+func (x *PartyPush) Reset() {
+	*x = PartyPush{}
+	mi := &file_game_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartyPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartyPush) ProtoMessage() {}
+
+func (x *PartyPush) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartyPush.ProtoReflect.Descriptor instead.
+func (*PartyPush) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PartyPush) GetParty() *PartyInfo {
+	if x != nil {
+		return x.Party
+	}
+	return nil
+}
+
+// 组队邀请推送 1021
+type PartyInvitePush struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PartyId   uint64 `protobuf:"varint,1,opt,name=partyId,proto3" json:"partyId,omitempty"`     // 队伍id
+	InviterId uint64 `protobuf:"varint,2,opt,name=inviterId,proto3" json:"inviterId,omitempty"` // 邀请者
+}
+
+func (x *PartyInvitePush) Reset() {
+	*x = PartyInvitePush{}
+	mi := &file_game_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartyInvitePush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartyInvitePush) ProtoMessage() {}
+
+func (x *PartyInvitePush) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartyInvitePush.ProtoReflect.Descriptor instead.
+func (*PartyInvitePush) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PartyInvitePush) GetPartyId() uint64 {
+	if x != nil {
+		return x.PartyId
+	}
+	return 0
+}
+
+func (x *PartyInvitePush) GetInviterId() uint64 {
+	if x != nil {
+		return x.InviterId
+	}
+	return 0
+}
+
+// 队伍信息
+type PartyInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PartyId      uint64   `protobuf:"varint,1,opt,name=partyId,proto3" json:"partyId,omitempty"`
+	LeaderId     uint64   `protobuf:"varint,2,opt,name=leaderId,proto3" json:"leaderId,omitempty"`                // 队长
+	MemberIdList []uint64 `protobuf:"varint,3,rep,packed,name=memberIdList,proto3" json:"memberIdList,omitempty"` // 队员，按加入顺序
+	RoomType     uint32   `protobuf:"varint,4,opt,name=roomType,proto3" json:"roomType,omitempty"`                // 准备的房间类型，没有队员准备时为 0
+	ReadyIdList  []uint64 `protobuf:"varint,5,rep,packed,name=readyIdList,proto3" json:"readyIdList,omitempty"`   // 已准备的队员
+	Matching     bool     `protobuf:"varint,6,opt,name=matching,proto3" json:"matching,omitempty"`                // 全部准备后正在匹配
+}
+
+func (x *PartyInfo) Reset() {
+	*x = PartyInfo{}
+	mi := &file_game_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartyInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartyInfo) ProtoMessage() {}
+
+func (x *PartyInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartyInfo.ProtoReflect.Descriptor instead.
+func (*PartyInfo) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *PartyInfo) GetPartyId() uint64 {
+	if x != nil {
+		return x.PartyId
+	}
+	return 0
+}
+
+func (x *PartyInfo) GetLeaderId() uint64 {
+	if x != nil {
+		return x.LeaderId
+	}
+	return 0
+}
+
+func (x *PartyInfo) GetMemberIdList() []uint64 {
+	if x != nil {
+		return x.MemberIdList
+	}
+	return nil
+}
+
+func (x *PartyInfo) GetRoomType() uint32 {
+	if x != nil {
+		return x.RoomType
+	}
+	return 0
+}
+
+func (x *PartyInfo) GetReadyIdList() []uint64 {
+	if x != nil {
+		return x.ReadyIdList
+	}
+	return nil
+}
+
+func (x *PartyInfo) GetMatching() bool {
+	if x != nil {
+		return x.Matching
+	}
+	return false
+}
+
 // 提示信息
 type Hint struct {
 	state         protoimpl.MessageState
@@ -551,7 +2130,7 @@ type Hint struct {
 
 func (x *Hint) Reset() {
 	*x = Hint{}
-	mi := &file_game_proto_msgTypes[10]
+	mi := &file_game_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -563,7 +2142,7 @@ func (x *Hint) String() string {
 func (*Hint) ProtoMessage() {}
 
 func (x *Hint) ProtoReflect() protoreflect.Message {
-	mi := &file_game_proto_msgTypes[10]
+	mi := &file_game_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -576,7 +2155,7 @@ func (x *Hint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Hint.ProtoReflect.Descriptor instead.
 func (*Hint) Descriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{10}
+	return file_game_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *Hint) GetId() uint32 {
@@ -620,7 +2199,7 @@ type ScreenInfo struct {
 
 func (x *ScreenInfo) Reset() {
 	*x = ScreenInfo{}
-	mi := &file_game_proto_msgTypes[11]
+	mi := &file_game_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -632,7 +2211,7 @@ func (x *ScreenInfo) String() string {
 func (*ScreenInfo) ProtoMessage() {}
 
 func (x *ScreenInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_game_proto_msgTypes[11]
+	mi := &file_game_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -645,7 +2224,7 @@ func (x *ScreenInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScreenInfo.ProtoReflect.Descriptor instead.
 func (*ScreenInfo) Descriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{11}
+	return file_game_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *ScreenInfo) GetGridList() []*Grid {
@@ -683,7 +2262,7 @@ type Goods struct {
 
 func (x *Goods) Reset() {
 	*x = Goods{}
-	mi := &file_game_proto_msgTypes[12]
+	mi := &file_game_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -695,7 +2274,7 @@ func (x *Goods) String() string {
 func (*Goods) ProtoMessage() {}
 
 func (x *Goods) ProtoReflect() protoreflect.Message {
-	mi := &file_game_proto_msgTypes[12]
+	mi := &file_game_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -708,7 +2287,7 @@ func (x *Goods) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Goods.ProtoReflect.Descriptor instead.
 func (*Goods) Descriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{12}
+	return file_game_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *Goods) GetItemId() uint32 {
@@ -752,7 +2331,7 @@ type Grid struct {
 
 func (x *Grid) Reset() {
 	*x = Grid{}
-	mi := &file_game_proto_msgTypes[13]
+	mi := &file_game_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -764,7 +2343,7 @@ func (x *Grid) String() string {
 func (*Grid) ProtoMessage() {}
 
 func (x *Grid) ProtoReflect() protoreflect.Message {
-	mi := &file_game_proto_msgTypes[13]
+	mi := &file_game_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -777,7 +2356,7 @@ func (x *Grid) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Grid.ProtoReflect.Descriptor instead.
 func (*Grid) Descriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{13}
+	return file_game_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *Grid) GetIndexId() uint32 {
@@ -814,7 +2393,7 @@ type RoomSettlementInfo struct {
 
 func (x *RoomSettlementInfo) Reset() {
 	*x = RoomSettlementInfo{}
-	mi := &file_game_proto_msgTypes[14]
+	mi := &file_game_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -826,7 +2405,7 @@ func (x *RoomSettlementInfo) String() string {
 func (*RoomSettlementInfo) ProtoMessage() {}
 
 func (x *RoomSettlementInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_game_proto_msgTypes[14]
+	mi := &file_game_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -839,7 +2418,7 @@ func (x *RoomSettlementInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoomSettlementInfo.ProtoReflect.Descriptor instead.
 func (*RoomSettlementInfo) Descriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{14}
+	return file_game_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *RoomSettlementInfo) GetPlayerInfo() *PlayerInfo {
@@ -871,7 +2450,7 @@ type TestRoundInfoReq struct {
 
 func (x *TestRoundInfoReq) Reset() {
 	*x = TestRoundInfoReq{}
-	mi := &file_game_proto_msgTypes[15]
+	mi := &file_game_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -883,7 +2462,7 @@ func (x *TestRoundInfoReq) String() string {
 func (*TestRoundInfoReq) ProtoMessage() {}
 
 func (x *TestRoundInfoReq) ProtoReflect() protoreflect.Message {
-	mi := &file_game_proto_msgTypes[15]
+	mi := &file_game_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -896,7 +2475,7 @@ func (x *TestRoundInfoReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TestRoundInfoReq.ProtoReflect.Descriptor instead.
 func (*TestRoundInfoReq) Descriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{15}
+	return file_game_proto_rawDescGZIP(), []int{46}
 }
 
 type TestRoundInfoPush struct {
@@ -914,7 +2493,7 @@ type TestRoundInfoPush struct {
 
 func (x *TestRoundInfoPush) Reset() {
 	*x = TestRoundInfoPush{}
-	mi := &file_game_proto_msgTypes[16]
+	mi := &file_game_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -926,7 +2505,7 @@ func (x *TestRoundInfoPush) String() string {
 func (*TestRoundInfoPush) ProtoMessage() {}
 
 func (x *TestRoundInfoPush) ProtoReflect() protoreflect.Message {
-	mi := &file_game_proto_msgTypes[16]
+	mi := &file_game_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -939,7 +2518,7 @@ func (x *TestRoundInfoPush) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TestRoundInfoPush.ProtoReflect.Descriptor instead.
 func (*TestRoundInfoPush) Descriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{16}
+	return file_game_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *TestRoundInfoPush) GetEndTimeOut() int64 {
@@ -1047,68 +2626,222 @@ var file_game_proto_rawDesc = []byte{
 	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f,
 	0x12, 0x26, 0x0a, 0x08, 0x68, 0x69, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x03,
 	0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x68, 0x69, 0x6e, 0x74, 0x52, 0x08,
-	0x68, 0x69, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x66, 0x0a, 0x04, 0x68, 0x69, 0x6e, 0x74,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64,
-	0x12, 0x1a, 0x0a, 0x08, 0x68, 0x69, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x08, 0x68, 0x69, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79, 0x70, 0x65,
-	0x22, 0x99, 0x01, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12,
-	0x26, 0x0a, 0x08, 0x47, 0x72, 0x69, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x0a, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x47, 0x72, 0x69, 0x64, 0x52, 0x08, 0x47,
-	0x72, 0x69, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x08, 0x61, 0x6c, 0x6c, 0x47, 0x6f,
-	0x6f, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x68, 0x65, 0x72, 0x6f,
-	0x2e, 0x67, 0x6f, 0x6f, 0x64, 0x73, 0x52, 0x08, 0x61, 0x6c, 0x6c, 0x47, 0x6f, 0x6f, 0x64, 0x73,
-	0x12, 0x3a, 0x0a, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69,
-	0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65,
-	0x72, 0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0e, 0x70, 0x6c,
-	0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x71, 0x0a, 0x05,
-	0x67, 0x6f, 0x6f, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x12, 0x14, 0x0a,
-	0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e,
-	0x64, 0x65, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x68, 0x6f, 0x77, 0x41, 0x6c, 0x6c, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x73, 0x68, 0x6f, 0x77, 0x41, 0x6c, 0x6c, 0x12, 0x20, 0x0a,
-	0x0b, 0x73, 0x68, 0x6f, 0x77, 0x43, 0x6f, 0x6e, 0x74, 0x6f, 0x75, 0x72, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x0b, 0x73, 0x68, 0x6f, 0x77, 0x43, 0x6f, 0x6e, 0x74, 0x6f, 0x75, 0x72, 0x22,
-	0x64, 0x0a, 0x04, 0x47, 0x72, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e, 0x64, 0x65, 0x78,
-	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x49,
-	0x64, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x68, 0x6f, 0x77, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x73, 0x68, 0x6f, 0x77, 0x51, 0x75, 0x61, 0x6c,
-	0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x54, 0x79,
-	0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74,
-	0x79, 0x54, 0x79, 0x70, 0x65, 0x22, 0x9c, 0x01, 0x0a, 0x12, 0x52, 0x6f, 0x6f, 0x6d, 0x53, 0x65,
-	0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x32, 0x0a, 0x0a,
-	0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x12, 0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f,
-	0x12, 0x2a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x6e,
-	0x66, 0x6f, 0x52, 0x08, 0x65, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x06,
-	0x70, 0x72, 0x6f, 0x66, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x69,
-	0x74, 0x65, 0x6d, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x70, 0x72,
-	0x6f, 0x66, 0x69, 0x74, 0x22, 0x12, 0x0a, 0x10, 0x74, 0x65, 0x73, 0x74, 0x52, 0x6f, 0x75, 0x6e,
-	0x64, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x22, 0x97, 0x02, 0x0a, 0x11, 0x74, 0x65, 0x73,
-	0x74, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x50, 0x75, 0x73, 0x68, 0x12, 0x1e,
-	0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x4f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x4f, 0x75, 0x74, 0x12, 0x1e,
-	0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1a,
-	0x0a, 0x08, 0x69, 0x73, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x08, 0x69, 0x73, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x12, 0x3c, 0x0a, 0x10, 0x63, 0x68,
-	0x61, 0x6e, 0x67, 0x65, 0x53, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x73, 0x63, 0x72, 0x65,
-	0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x10, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x63,
-	0x72, 0x65, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x26, 0x0a, 0x08, 0x68, 0x69, 0x6e, 0x74,
-	0x4c, 0x69, 0x73, 0x74, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x68, 0x65, 0x72,
-	0x6f, 0x2e, 0x68, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x68, 0x69, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74,
-	0x12, 0x40, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e,
-	0x66, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e,
-	0x52, 0x6f, 0x6f, 0x6d, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e,
-	0x66, 0x6f, 0x52, 0x0e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e,
-	0x66, 0x6f, 0x42, 0x1a, 0x5a, 0x18, 0x67, 0x61, 0x6d, 0x65, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x62, 0x47, 0x6f, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x68, 0x69, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x14, 0x0a, 0x12, 0x67, 0x65, 0x74, 0x52,
+	0x6f, 0x6f, 0x6d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x22, 0xb9,
+	0x02, 0x0a, 0x13, 0x67, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x4f, 0x75, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x4f,
+	0x75, 0x74, 0x12, 0x30, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x73, 0x63,
+	0x72, 0x65, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0a, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x3a, 0x0a, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e,
+	0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74,
+	0x12, 0x32, 0x0a, 0x0a, 0x62, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x06,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x72, 0x6f, 0x75, 0x6e,
+	0x64, 0x42, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0a, 0x62, 0x65, 0x74, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x62, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x07, 0x20, 0x03, 0x28, 0x04, 0x52, 0x0d, 0x62, 0x65, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x6a, 0x0a, 0x0c, 0x72, 0x6f,
+	0x75, 0x6e, 0x64, 0x42, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x6f,
+	0x75, 0x6e, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a,
+	0x72, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x3a, 0x0a, 0x0e, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x2e, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e,
+	0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x31, 0x0a, 0x13, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a,
+	0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x22, 0x46, 0x0a, 0x14, 0x73, 0x70, 0x65,
+	0x63, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x12, 0x2e, 0x0a, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x4c, 0x69, 0x73,
+	0x74, 0x22, 0xc2, 0x01, 0x0a, 0x0c, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f,
+	0x6f, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x6f,
+	0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x6f,
+	0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x3a, 0x0a, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69,
+	0x73, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x73, 0x70, 0x65, 0x63,
+	0x74, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x49, 0x64, 0x22, 0x5d, 0x0a, 0x0f, 0x6a, 0x6f, 0x69, 0x6e, 0x53, 0x70,
+	0x65, 0x63, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x6f,
+	0x6d, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x49, 0x64, 0x22, 0x49, 0x0a, 0x10, 0x6a, 0x6f, 0x69, 0x6e, 0x53, 0x70, 0x65,
+	0x63, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x12, 0x35, 0x0a, 0x08, 0x73, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x65,
+	0x72, 0x6f, 0x2e, 0x67, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x22, 0x12, 0x0a, 0x10, 0x6c, 0x65, 0x61, 0x76, 0x65, 0x53, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x22, 0x13, 0x0a, 0x11, 0x6c, 0x65, 0x61, 0x76, 0x65, 0x53, 0x70, 0x65,
+	0x63, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x22, 0xd6, 0x01, 0x0a, 0x14, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52,
+	0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x65, 0x72, 0x6f, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06,
+	0x68, 0x65, 0x72, 0x6f, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x0c, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x6e,
+	0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x69,
+	0x74, 0x65, 0x6d, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x69, 0x74,
+	0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x6f,
+	0x75, 0x6e, 0x64, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a,
+	0x72, 0x6f, 0x75, 0x6e, 0x64, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x46, 0x69, 0x6c,
+	0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x46, 0x69,
+	0x6c, 0x6c, 0x22, 0x42, 0x0a, 0x15, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x69, 0x76,
+	0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x12, 0x29, 0x0a, 0x04, 0x72,
+	0x6f, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x68, 0x65, 0x72, 0x6f,
+	0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x04, 0x72, 0x6f, 0x6f, 0x6d, 0x22, 0x74, 0x0a, 0x12, 0x6a, 0x6f, 0x69, 0x6e, 0x50, 0x72,
+	0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x72, 0x6f, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x06, 0x68, 0x65, 0x72, 0x6f, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x0c, 0x69, 0x74, 0x65, 0x6d,
+	0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c,
+	0x69, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x40, 0x0a, 0x13,
+	0x6a, 0x6f, 0x69, 0x6e, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52,
+	0x65, 0x73, 0x70, 0x12, 0x29, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65,
+	0x52, 0x6f, 0x6f, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x72, 0x6f, 0x6f, 0x6d, 0x22, 0x15,
+	0x0a, 0x13, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f,
+	0x6f, 0x6d, 0x52, 0x65, 0x71, 0x22, 0x16, 0x0a, 0x14, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x72,
+	0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x22, 0x15, 0x0a,
+	0x13, 0x6c, 0x65, 0x61, 0x76, 0x65, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f,
+	0x6d, 0x52, 0x65, 0x71, 0x22, 0x16, 0x0a, 0x14, 0x6c, 0x65, 0x61, 0x76, 0x65, 0x50, 0x72, 0x69,
+	0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x22, 0x3c, 0x0a, 0x0f,
+	0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x50, 0x75, 0x73, 0x68, 0x12,
+	0x29, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x68, 0x65, 0x72, 0x6f, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x72, 0x6f, 0x6f, 0x6d, 0x22, 0x8b, 0x02, 0x0a, 0x0f, 0x70,
+	0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12,
+	0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e,
+	0x64, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x72, 0x6f,
+	0x75, 0x6e, 0x64, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f,
+	0x75, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x46, 0x69, 0x6c, 0x6c, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x46, 0x69, 0x6c, 0x6c,
+	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x3a, 0x0a, 0x0e,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x2e, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x10, 0x0a, 0x0e, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x50, 0x61, 0x72, 0x74, 0x79, 0x52, 0x65, 0x71, 0x22, 0x38, 0x0a, 0x0f, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x50, 0x61, 0x72, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x12, 0x25, 0x0a,
+	0x05, 0x70, 0x61, 0x72, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x68,
+	0x65, 0x72, 0x6f, 0x2e, 0x70, 0x61, 0x72, 0x74, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x70,
+	0x61, 0x72, 0x74, 0x79, 0x22, 0x28, 0x0a, 0x0e, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x50, 0x61,
+	0x72, 0x74, 0x79, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x11,
+	0x0a, 0x0f, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x50, 0x61, 0x72, 0x74, 0x79, 0x52, 0x65, 0x73,
+	0x70, 0x22, 0x2a, 0x0a, 0x0e, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x50, 0x61, 0x72, 0x74, 0x79,
+	0x52, 0x65, 0x71, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x72, 0x74, 0x79, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x70, 0x61, 0x72, 0x74, 0x79, 0x49, 0x64, 0x22, 0x38, 0x0a,
+	0x0f, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x50, 0x61, 0x72, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70,
+	0x12, 0x25, 0x0a, 0x05, 0x70, 0x61, 0x72, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x70, 0x61, 0x72, 0x74, 0x79, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x05, 0x70, 0x61, 0x72, 0x74, 0x79, 0x22, 0x0f, 0x0a, 0x0d, 0x6c, 0x65, 0x61, 0x76, 0x65,
+	0x50, 0x61, 0x72, 0x74, 0x79, 0x52, 0x65, 0x71, 0x22, 0x10, 0x0a, 0x0e, 0x6c, 0x65, 0x61, 0x76,
+	0x65, 0x50, 0x61, 0x72, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x22, 0x32, 0x0a, 0x09, 0x70, 0x61,
+	0x72, 0x74, 0x79, 0x50, 0x75, 0x73, 0x68, 0x12, 0x25, 0x0a, 0x05, 0x70, 0x61, 0x72, 0x74, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x70, 0x61,
+	0x72, 0x74, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x70, 0x61, 0x72, 0x74, 0x79, 0x22, 0x49,
+	0x0a, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x79, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x50, 0x75, 0x73,
+	0x68, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x72, 0x74, 0x79, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x07, 0x70, 0x61, 0x72, 0x74, 0x79, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x69,
+	0x6e, 0x76, 0x69, 0x74, 0x65, 0x72, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x72, 0x49, 0x64, 0x22, 0xbf, 0x01, 0x0a, 0x09, 0x70, 0x61,
+	0x72, 0x74, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x72, 0x74, 0x79,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x70, 0x61, 0x72, 0x74, 0x79, 0x49,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x49, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x08, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x22, 0x0a,
+	0x0c, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x49, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x04, 0x52, 0x0c, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x49, 0x64, 0x4c, 0x69, 0x73,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a,
+	0x0b, 0x72, 0x65, 0x61, 0x64, 0x79, 0x49, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x04, 0x52, 0x0b, 0x72, 0x65, 0x61, 0x64, 0x79, 0x49, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x22, 0x66, 0x0a, 0x04, 0x68,
+	0x69, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x69, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x68, 0x69, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79,
+	0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54,
+	0x79, 0x70, 0x65, 0x22, 0x99, 0x01, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x26, 0x0a, 0x08, 0x47, 0x72, 0x69, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x47, 0x72, 0x69, 0x64,
+	0x52, 0x08, 0x47, 0x72, 0x69, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x08, 0x61, 0x6c,
+	0x6c, 0x47, 0x6f, 0x6f, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x68,
+	0x65, 0x72, 0x6f, 0x2e, 0x67, 0x6f, 0x6f, 0x64, 0x73, 0x52, 0x08, 0x61, 0x6c, 0x6c, 0x47, 0x6f,
+	0x6f, 0x64, 0x73, 0x12, 0x3a, 0x0a, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66,
+	0x6f, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x0e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x22,
+	0x71, 0x0a, 0x05, 0x67, 0x6f, 0x6f, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x74, 0x65, 0x6d,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64,
+	0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x68, 0x6f, 0x77, 0x41, 0x6c,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x73, 0x68, 0x6f, 0x77, 0x41, 0x6c, 0x6c,
+	0x12, 0x20, 0x0a, 0x0b, 0x73, 0x68, 0x6f, 0x77, 0x43, 0x6f, 0x6e, 0x74, 0x6f, 0x75, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x73, 0x68, 0x6f, 0x77, 0x43, 0x6f, 0x6e, 0x74, 0x6f,
+	0x75, 0x72, 0x22, 0x64, 0x0a, 0x04, 0x47, 0x72, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x68, 0x6f, 0x77, 0x51, 0x75, 0x61, 0x6c,
+	0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x73, 0x68, 0x6f, 0x77, 0x51,
+	0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74,
+	0x79, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x71, 0x75, 0x61,
+	0x6c, 0x69, 0x74, 0x79, 0x54, 0x79, 0x70, 0x65, 0x22, 0x9c, 0x01, 0x0a, 0x12, 0x52, 0x6f, 0x6f,
+	0x6d, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x32, 0x0a, 0x0a, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x2e, 0x70, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x2a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x69, 0x74, 0x65,
+	0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x65, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x12,
+	0x26, 0x0a, 0x06, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x06, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x74, 0x22, 0x12, 0x0a, 0x10, 0x74, 0x65, 0x73, 0x74, 0x52,
+	0x6f, 0x75, 0x6e, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x22, 0x97, 0x02, 0x0a, 0x11,
+	0x74, 0x65, 0x73, 0x74, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x50, 0x75, 0x73,
+	0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x4f, 0x75, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x4f, 0x75,
+	0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x12, 0x3c, 0x0a,
+	0x10, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x49, 0x6e, 0x66,
+	0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x68, 0x65, 0x72, 0x6f, 0x2e, 0x73,
+	0x63, 0x72, 0x65, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x10, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x53, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x26, 0x0a, 0x08, 0x68,
+	0x69, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x68, 0x65, 0x72, 0x6f, 0x2e, 0x68, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x68, 0x69, 0x6e, 0x74, 0x4c,
+	0x69, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x68, 0x65,
+	0x72, 0x6f, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x49, 0x6e, 0x66, 0x6f, 0x42, 0x1a, 0x5a, 0x18, 0x67, 0x61, 0x6d, 0x65, 0x53, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x62, 0x47,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1123,54 +2856,101 @@ func file_game_proto_rawDescGZIP() []byte {
 	return file_game_proto_rawDescData
 }
 
-var file_game_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_game_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
 var file_game_proto_goTypes = []any{
-	(*StartMatchReq)(nil),      // 0: hero.startMatchReq
-	(*StartMatchResp)(nil),     // 1: hero.startMatchResp
-	(*MatchInfoPush)(nil),      // 2: hero.MatchInfoPush
-	(*CancelMatchReq)(nil),     // 3: hero.cancelMatchReq
-	(*CancelMatchResp)(nil),    // 4: hero.cancelMatchResp
-	(*BetReq)(nil),             // 5: hero.betReq
-	(*BetResp)(nil),            // 6: hero.betResp
-	(*RoundInfoPush)(nil),      // 7: hero.roundInfoPush
-	(*UseItemReq)(nil),         // 8: hero.useItemReq
-	(*UseItemResp)(nil),        // 9: hero.useItemResp
-	(*Hint)(nil),               // 10: hero.hint
-	(*ScreenInfo)(nil),         // 11: hero.screenInfo
-	(*Goods)(nil),              // 12: hero.goods
-	(*Grid)(nil),               // 13: hero.Grid
-	(*RoomSettlementInfo)(nil), // 14: hero.RoomSettlementInfo
-	(*TestRoundInfoReq)(nil),   // 15: hero.testRoundInfoReq
-	(*TestRoundInfoPush)(nil),  // 16: hero.testRoundInfoPush
-	(*ItemInfo)(nil),           // 17: item.itemInfo
-	(*PlayerInfo)(nil),         // 18: player.playerInfo
+	(*StartMatchReq)(nil),         // 0: hero.startMatchReq
+	(*StartMatchResp)(nil),        // 1: hero.startMatchResp
+	(*MatchInfoPush)(nil),         // 2: hero.MatchInfoPush
+	(*CancelMatchReq)(nil),        // 3: hero.cancelMatchReq
+	(*CancelMatchResp)(nil),       // 4: hero.cancelMatchResp
+	(*BetReq)(nil),                // 5: hero.betReq
+	(*BetResp)(nil),               // 6: hero.betResp
+	(*RoundInfoPush)(nil),         // 7: hero.roundInfoPush
+	(*UseItemReq)(nil),            // 8: hero.useItemReq
+	(*UseItemResp)(nil),           // 9: hero.useItemResp
+	(*GetRoomSnapshotReq)(nil),    // 10: hero.getRoomSnapshotReq
+	(*GetRoomSnapshotResp)(nil),   // 11: hero.getRoomSnapshotResp
+	(*RoundBetInfo)(nil),          // 12: hero.roundBetInfo
+	(*SpectateRoomListReq)(nil),   // 13: hero.spectateRoomListReq
+	(*SpectateRoomListResp)(nil),  // 14: hero.spectateRoomListResp
+	(*SpectateRoom)(nil),          // 15: hero.spectateRoom
+	(*JoinSpectateReq)(nil),       // 16: hero.joinSpectateReq
+	(*JoinSpectateResp)(nil),      // 17: hero.joinSpectateResp
+	(*LeaveSpectateReq)(nil),      // 18: hero.leaveSpectateReq
+	(*LeaveSpectateResp)(nil),     // 19: hero.leaveSpectateResp
+	(*CreatePrivateRoomReq)(nil),  // 20: hero.createPrivateRoomReq
+	(*CreatePrivateRoomResp)(nil), // 21: hero.createPrivateRoomResp
+	(*JoinPrivateRoomReq)(nil),    // 22: hero.joinPrivateRoomReq
+	(*JoinPrivateRoomResp)(nil),   // 23: hero.joinPrivateRoomResp
+	(*StartPrivateRoomReq)(nil),   // 24: hero.startPrivateRoomReq
+	(*StartPrivateRoomResp)(nil),  // 25: hero.startPrivateRoomResp
+	(*LeavePrivateRoomReq)(nil),   // 26: hero.leavePrivateRoomReq
+	(*LeavePrivateRoomResp)(nil),  // 27: hero.leavePrivateRoomResp
+	(*PrivateRoomPush)(nil),       // 28: hero.privateRoomPush
+	(*PrivateRoomInfo)(nil),       // 29: hero.privateRoomInfo
+	(*CreatePartyReq)(nil),        // 30: hero.createPartyReq
+	(*CreatePartyResp)(nil),       // 31: hero.createPartyResp
+	(*InvitePartyReq)(nil),        // 32: hero.invitePartyReq
+	(*InvitePartyResp)(nil),       // 33: hero.invitePartyResp
+	(*AcceptPartyReq)(nil),        // 34: hero.acceptPartyReq
+	(*AcceptPartyResp)(nil),       // 35: hero.acceptPartyResp
+	(*LeavePartyReq)(nil),         // 36: hero.leavePartyReq
+	(*LeavePartyResp)(nil),        // 37: hero.leavePartyResp
+	(*PartyPush)(nil),             // 38: hero.partyPush
+	(*PartyInvitePush)(nil),       // 39: hero.partyInvitePush
+	(*PartyInfo)(nil),             // 40: hero.partyInfo
+	(*Hint)(nil),                  // 41: hero.hint
+	(*ScreenInfo)(nil),            // 42: hero.screenInfo
+	(*Goods)(nil),                 // 43: hero.goods
+	(*Grid)(nil),                  // 44: hero.Grid
+	(*RoomSettlementInfo)(nil),    // 45: hero.RoomSettlementInfo
+	(*TestRoundInfoReq)(nil),      // 46: hero.testRoundInfoReq
+	(*TestRoundInfoPush)(nil),     // 47: hero.testRoundInfoPush
+	(*ItemInfo)(nil),              // 48: item.itemInfo
+	(*PlayerInfo)(nil),            // 49: player.playerInfo
 }
 var file_game_proto_depIdxs = []int32{
-	17, // 0: hero.startMatchReq.itemInfoList:type_name -> item.itemInfo
-	18, // 1: hero.MatchInfoPush.playerInfoList:type_name -> player.playerInfo
-	17, // 2: hero.betReq.betInfo:type_name -> item.itemInfo
-	18, // 3: hero.betResp.playerInfo:type_name -> player.playerInfo
-	11, // 4: hero.roundInfoPush.changeScreenInfo:type_name -> hero.screenInfo
-	10, // 5: hero.roundInfoPush.hintList:type_name -> hero.hint
-	14, // 6: hero.roundInfoPush.SettlementInfo:type_name -> hero.RoomSettlementInfo
-	17, // 7: hero.useItemReq.item:type_name -> item.itemInfo
-	17, // 8: hero.useItemResp.itemInfoList:type_name -> item.itemInfo
-	11, // 9: hero.useItemResp.changeScreenInfo:type_name -> hero.screenInfo
-	10, // 10: hero.useItemResp.hintList:type_name -> hero.hint
-	13, // 11: hero.screenInfo.GridList:type_name -> hero.Grid
-	12, // 12: hero.screenInfo.allGoods:type_name -> hero.goods
-	18, // 13: hero.screenInfo.playerInfoList:type_name -> player.playerInfo
-	18, // 14: hero.RoomSettlementInfo.playerInfo:type_name -> player.playerInfo
-	17, // 15: hero.RoomSettlementInfo.expenses:type_name -> item.itemInfo
-	17, // 16: hero.RoomSettlementInfo.profit:type_name -> item.itemInfo
-	11, // 17: hero.testRoundInfoPush.changeScreenInfo:type_name -> hero.screenInfo
-	10, // 18: hero.testRoundInfoPush.hintList:type_name -> hero.hint
-	14, // 19: hero.testRoundInfoPush.SettlementInfo:type_name -> hero.RoomSettlementInfo
-	20, // [20:20] is the sub-list for method output_type
-	20, // [20:20] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	48, // 0: hero.startMatchReq.itemInfoList:type_name -> item.itemInfo
+	49, // 1: hero.MatchInfoPush.playerInfoList:type_name -> player.playerInfo
+	48, // 2: hero.betReq.betInfo:type_name -> item.itemInfo
+	49, // 3: hero.betResp.playerInfo:type_name -> player.playerInfo
+	42, // 4: hero.roundInfoPush.changeScreenInfo:type_name -> hero.screenInfo
+	41, // 5: hero.roundInfoPush.hintList:type_name -> hero.hint
+	45, // 6: hero.roundInfoPush.SettlementInfo:type_name -> hero.RoomSettlementInfo
+	48, // 7: hero.useItemReq.item:type_name -> item.itemInfo
+	48, // 8: hero.useItemResp.itemInfoList:type_name -> item.itemInfo
+	42, // 9: hero.useItemResp.changeScreenInfo:type_name -> hero.screenInfo
+	41, // 10: hero.useItemResp.hintList:type_name -> hero.hint
+	42, // 11: hero.getRoomSnapshotResp.screenInfo:type_name -> hero.screenInfo
+	49, // 12: hero.getRoomSnapshotResp.playerInfoList:type_name -> player.playerInfo
+	12, // 13: hero.getRoomSnapshotResp.betHistory:type_name -> hero.roundBetInfo
+	49, // 14: hero.roundBetInfo.playerInfoList:type_name -> player.playerInfo
+	15, // 15: hero.spectateRoomListResp.roomList:type_name -> hero.spectateRoom
+	49, // 16: hero.spectateRoom.playerInfoList:type_name -> player.playerInfo
+	11, // 17: hero.joinSpectateResp.snapshot:type_name -> hero.getRoomSnapshotResp
+	48, // 18: hero.createPrivateRoomReq.itemInfoList:type_name -> item.itemInfo
+	29, // 19: hero.createPrivateRoomResp.room:type_name -> hero.privateRoomInfo
+	48, // 20: hero.joinPrivateRoomReq.itemInfoList:type_name -> item.itemInfo
+	29, // 21: hero.joinPrivateRoomResp.room:type_name -> hero.privateRoomInfo
+	29, // 22: hero.privateRoomPush.room:type_name -> hero.privateRoomInfo
+	49, // 23: hero.privateRoomInfo.playerInfoList:type_name -> player.playerInfo
+	40, // 24: hero.createPartyResp.party:type_name -> hero.partyInfo
+	40, // 25: hero.acceptPartyResp.party:type_name -> hero.partyInfo
+	40, // 26: hero.partyPush.party:type_name -> hero.partyInfo
+	44, // 27: hero.screenInfo.GridList:type_name -> hero.Grid
+	43, // 28: hero.screenInfo.allGoods:type_name -> hero.goods
+	49, // 29: hero.screenInfo.playerInfoList:type_name -> player.playerInfo
+	49, // 30: hero.RoomSettlementInfo.playerInfo:type_name -> player.playerInfo
+	48, // 31: hero.RoomSettlementInfo.expenses:type_name -> item.itemInfo
+	48, // 32: hero.RoomSettlementInfo.profit:type_name -> item.itemInfo
+	42, // 33: hero.testRoundInfoPush.changeScreenInfo:type_name -> hero.screenInfo
+	41, // 34: hero.testRoundInfoPush.hintList:type_name -> hero.hint
+	45, // 35: hero.testRoundInfoPush.SettlementInfo:type_name -> hero.RoomSettlementInfo
+	36, // [36:36] is the sub-list for method output_type
+	36, // [36:36] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
 }
 
 func init() { file_game_proto_init() }
@@ -1186,7 +2966,7 @@ func file_game_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_game_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   48,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
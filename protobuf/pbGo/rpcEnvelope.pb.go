@@ -0,0 +1,461 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v6.33.4
+// source: rpcEnvelope.proto
+
+package pbGo
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RpcHead 对应 common.MessageHead
+type RpcHead struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Len      uint32 `protobuf:"varint,1,opt,name=len,proto3" json:"len,omitempty"`
+	Flag     uint32 `protobuf:"varint,2,opt,name=flag,proto3" json:"flag,omitempty"`
+	Sn       uint32 `protobuf:"varint,3,opt,name=sn,proto3" json:"sn,omitempty"`
+	Code     uint32 `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"`
+	Protocol uint32 `protobuf:"varint,5,opt,name=protocol,proto3" json:"protocol,omitempty"`
+}
+
+func (x *RpcHead) Reset() {
+	*x = RpcHead{}
+	mi := &file_rpcEnvelope_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RpcHead) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RpcHead) ProtoMessage() {}
+
+func (x *RpcHead) ProtoReflect() protoreflect.Message {
+	mi := &file_rpcEnvelope_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RpcHead.ProtoReflect.Descriptor instead.
+func (*RpcHead) Descriptor() ([]byte, []int) {
+	return file_rpcEnvelope_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RpcHead) GetLen() uint32 {
+	if x != nil {
+		return x.Len
+	}
+	return 0
+}
+
+func (x *RpcHead) GetFlag() uint32 {
+	if x != nil {
+		return x.Flag
+	}
+	return 0
+}
+
+func (x *RpcHead) GetSn() uint32 {
+	if x != nil {
+		return x.Sn
+	}
+	return 0
+}
+
+func (x *RpcHead) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *RpcHead) GetProtocol() uint32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+// RpcPlayer 对应 common.Player
+type RpcPlayer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ServerIds     []uint32 `protobuf:"varint,1,rep,packed,name=serverIds,proto3" json:"serverIds,omitempty"` // 玩家所链接的服务器
+	UserId        uint64   `protobuf:"varint,2,opt,name=userId,proto3" json:"userId,omitempty"`              // 角色 id
+	AccountId     string   `protobuf:"bytes,3,opt,name=accountId,proto3" json:"accountId,omitempty"`         // 第三方账号 id
+	ServerId      uint32   `protobuf:"varint,4,opt,name=serverId,proto3" json:"serverId,omitempty"`          // 角色归属区服 id
+	RealServerId  uint32   `protobuf:"varint,5,opt,name=realServerId,proto3" json:"realServerId,omitempty"`  // 角色当前所在的区服 id
+	ClientVersion string   `protobuf:"bytes,6,opt,name=clientVersion,proto3" json:"clientVersion,omitempty"`
+	Platform      uint32   `protobuf:"varint,7,opt,name=platform,proto3" json:"platform,omitempty"` // 登录平台，0 游客 1 抖音
+	DeviceId      string   `protobuf:"bytes,8,opt,name=deviceId,proto3" json:"deviceId,omitempty"`
+	Locale        string   `protobuf:"bytes,9,opt,name=locale,proto3" json:"locale,omitempty"`
+	GateId        uint32   `protobuf:"varint,10,opt,name=gateId,proto3" json:"gateId,omitempty"`       // 登录的网关 id
+	SessionId     uint64   `protobuf:"varint,11,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // 网关会话 id
+}
+
+func (x *RpcPlayer) Reset() {
+	*x = RpcPlayer{}
+	mi := &file_rpcEnvelope_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RpcPlayer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RpcPlayer) ProtoMessage() {}
+
+func (x *RpcPlayer) ProtoReflect() protoreflect.Message {
+	mi := &file_rpcEnvelope_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RpcPlayer.ProtoReflect.Descriptor instead.
+func (*RpcPlayer) Descriptor() ([]byte, []int) {
+	return file_rpcEnvelope_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RpcPlayer) GetServerIds() []uint32 {
+	if x != nil {
+		return x.ServerIds
+	}
+	return nil
+}
+
+func (x *RpcPlayer) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RpcPlayer) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *RpcPlayer) GetServerId() uint32 {
+	if x != nil {
+		return x.ServerId
+	}
+	return 0
+}
+
+func (x *RpcPlayer) GetRealServerId() uint32 {
+	if x != nil {
+		return x.RealServerId
+	}
+	return 0
+}
+
+func (x *RpcPlayer) GetClientVersion() string {
+	if x != nil {
+		return x.ClientVersion
+	}
+	return ""
+}
+
+func (x *RpcPlayer) GetPlatform() uint32 {
+	if x != nil {
+		return x.Platform
+	}
+	return 0
+}
+
+func (x *RpcPlayer) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *RpcPlayer) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *RpcPlayer) GetGateId() uint32 {
+	if x != nil {
+		return x.GateId
+	}
+	return 0
+}
+
+func (x *RpcPlayer) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+// RpcMessage 对应 common.RpcMessage
+type RpcMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Head    *RpcHead   `protobuf:"bytes,1,opt,name=head,proto3" json:"head,omitempty"`
+	Body    []byte     `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	Player  *RpcPlayer `protobuf:"bytes,3,opt,name=player,proto3" json:"player,omitempty"`
+	Retries uint32     `protobuf:"varint,4,opt,name=retries,proto3" json:"retries,omitempty"` // 服务端请求重发次数
+}
+
+func (x *RpcMessage) Reset() {
+	*x = RpcMessage{}
+	mi := &file_rpcEnvelope_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RpcMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RpcMessage) ProtoMessage() {}
+
+func (x *RpcMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_rpcEnvelope_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RpcMessage.ProtoReflect.Descriptor instead.
+func (*RpcMessage) Descriptor() ([]byte, []int) {
+	return file_rpcEnvelope_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RpcMessage) GetHead() *RpcHead {
+	if x != nil {
+		return x.Head
+	}
+	return nil
+}
+
+func (x *RpcMessage) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *RpcMessage) GetPlayer() *RpcPlayer {
+	if x != nil {
+		return x.Player
+	}
+	return nil
+}
+
+func (x *RpcMessage) GetRetries() uint32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+// RpcResp 对应 common.Resp
+type RpcResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code uint32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Flag uint32 `protobuf:"varint,2,opt,name=flag,proto3" json:"flag,omitempty"`
+	Body []byte `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (x *RpcResp) Reset() {
+	*x = RpcResp{}
+	mi := &file_rpcEnvelope_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RpcResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RpcResp) ProtoMessage() {}
+
+func (x *RpcResp) ProtoReflect() protoreflect.Message {
+	mi := &file_rpcEnvelope_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RpcResp.ProtoReflect.Descriptor instead.
+func (*RpcResp) Descriptor() ([]byte, []int) {
+	return file_rpcEnvelope_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RpcResp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *RpcResp) GetFlag() uint32 {
+	if x != nil {
+		return x.Flag
+	}
+	return 0
+}
+
+func (x *RpcResp) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+var File_rpcEnvelope_proto protoreflect.FileDescriptor
+
+var file_rpcEnvelope_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x72, 0x70, 0x63, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x03, 0x72, 0x70, 0x63, 0x22, 0x6f, 0x0a, 0x07, 0x52, 0x70, 0x63, 0x48,
+	0x65, 0x61, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x03, 0x6c, 0x65, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x12, 0x0e, 0x0a, 0x02, 0x73, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x73, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x22, 0xcb, 0x02, 0x0a, 0x09, 0x52, 0x70,
+	0x63, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x49, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x09, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x49, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1c, 0x0a,
+	0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x61, 0x6c, 0x53,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x72,
+	0x65, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x49, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x1a, 0x0a,
+	0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x67, 0x61, 0x74, 0x65, 0x49, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x06, 0x67, 0x61, 0x74, 0x65, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x84, 0x01, 0x0a, 0x0a, 0x52, 0x70, 0x63, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x68, 0x65, 0x61, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x70, 0x63, 0x48, 0x65,
+	0x61, 0x64, 0x52, 0x04, 0x68, 0x65, 0x61, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x26, 0x0a, 0x06,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x52, 0x70, 0x63, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x06, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x45,
+	0x0a, 0x07, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x66, 0x6c, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x66, 0x6c, 0x61,
+	0x67, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x62, 0x6f, 0x64, 0x79, 0x42, 0x1a, 0x5a, 0x18, 0x67, 0x61, 0x6d, 0x65, 0x53, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x62, 0x47,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpcEnvelope_proto_rawDescOnce sync.Once
+	file_rpcEnvelope_proto_rawDescData = file_rpcEnvelope_proto_rawDesc
+)
+
+func file_rpcEnvelope_proto_rawDescGZIP() []byte {
+	file_rpcEnvelope_proto_rawDescOnce.Do(func() {
+		file_rpcEnvelope_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpcEnvelope_proto_rawDescData)
+	})
+	return file_rpcEnvelope_proto_rawDescData
+}
+
+var file_rpcEnvelope_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_rpcEnvelope_proto_goTypes = []any{
+	(*RpcHead)(nil),    // 0: rpc.RpcHead
+	(*RpcPlayer)(nil),  // 1: rpc.RpcPlayer
+	(*RpcMessage)(nil), // 2: rpc.RpcMessage
+	(*RpcResp)(nil),    // 3: rpc.RpcResp
+}
+var file_rpcEnvelope_proto_depIdxs = []int32{
+	0, // 0: rpc.RpcMessage.head:type_name -> rpc.RpcHead
+	1, // 1: rpc.RpcMessage.player:type_name -> rpc.RpcPlayer
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpcEnvelope_proto_init() }
+func file_rpcEnvelope_proto_init() {
+	if File_rpcEnvelope_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpcEnvelope_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpcEnvelope_proto_goTypes,
+		DependencyIndexes: file_rpcEnvelope_proto_depIdxs,
+		MessageInfos:      file_rpcEnvelope_proto_msgTypes,
+	}.Build()
+	File_rpcEnvelope_proto = out.File
+	file_rpcEnvelope_proto_rawDesc = nil
+	file_rpcEnvelope_proto_goTypes = nil
+	file_rpcEnvelope_proto_depIdxs = nil
+}
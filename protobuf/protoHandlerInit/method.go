@@ -9,7 +9,19 @@ var ProtoIdToMethodMap = map[uint16]string{
 	1003: "CancelMatchHandler", //取消匹配
 	1004: "BetHandler",         //竞拍
 	// 1005: roundInfoPush //推送竞拍
-	1006: "UseItemHandler", //道具使用竞拍
+	1006: "UseItemHandler",           //道具使用竞拍
+	1007: "GetRoomSnapshotHandler",   //房间快照，断线重连
+	1008: "SpectateRoomListHandler",  //可观战房间
+	1009: "JoinSpectateHandler",      //观战
+	1010: "LeaveSpectateHandler",     //退出观战
+	1011: "CreatePrivateRoomHandler", //创建私人房间
+	1012: "JoinPrivateRoomHandler",   //邀请码加入私人房间
+	1013: "StartPrivateRoomHandler",  //房主开始
+	1014: "LeavePrivateRoomHandler",  //离开私人房间
+	1016: "CreatePartyHandler",       //创建队伍
+	1017: "InvitePartyHandler",       //邀请组队
+	1018: "AcceptPartyHandler",       //接受组队邀请
+	1019: "LeavePartyHandler",        //离开队伍
 
 	2001: "GetItemInfoHandler",
 	2002: "BuyItemHandler",
@@ -25,4 +37,8 @@ const (
 	MatchInfoPush uint16 = 1002
 	BetPush       uint16 = 1004
 	RoundInfoPush uint16 = 1005
+
+	PrivateRoomPush uint16 = 1015
+	PartyPush       uint16 = 1020
+	PartyInvitePush uint16 = 1021
 )
@@ -2,6 +2,15 @@ package common
 
 import "gameServer/pkg/bytes"
 
+// 服务端发起请求相关的 Flag 标志，与 datapack 中的压缩、加密标志共用 Flag 字段
+const (
+	// MessageFlagServerRequest 服务端发起的请求，SN 使用服务端独立的编号空间，客户端需回复同 SN 的应答
+	MessageFlagServerRequest = uint16(0x0002)
+
+	// MessageFlagServerReply 客户端对服务端请求的应答，SN 为对应请求的 SN
+	MessageFlagServerReply = uint16(0x0004)
+)
+
 type MessageHead struct {
 	// Len 消息体Body的长度
 	Len uint16 //2
@@ -25,6 +34,41 @@ type Message struct {
 type RpcMessage struct {
 	Data   *Message
 	Player *Player
+	// Retries 服务端请求未收到应答时的重发次数，仅 MessageFlagServerRequest 消息使用
+	Retries uint8
+}
+
+// RpcBatchMessage 同一网关上多个玩家的批量推送，消息体只序列化一次
+type RpcBatchMessage struct {
+	Data    *Message
+	UserIds []uint64
+}
+
+// RpcMultiMessage 同一网关上的多条推送，按顺序下发
+type RpcMultiMessage struct {
+	Items []*RpcBatchMessage
+}
+
+// BatchResp 批量推送结果
+type BatchResp struct {
+	// Failed 推送失败的玩家，userId-失败原因
+	Failed map[uint64]string
+}
+
+// CanaryRule 灰度规则，由运维调用网关 SetCanary 下发
+type CanaryRule struct {
+	Group     string   // 服务组名
+	Version   uint32   // 灰度版本
+	Percent   uint32   // 按 userId 命中的百分比，0~100
+	Whitelist []uint64 // 必定进入灰度版本的玩家
+	Remove    bool     // 删除该组规则
+}
+
+// ServerBind 把同一网关上的玩家的某组协议绑定到指定节点，如跨节点匹配成桌后绑定到房间所在节点
+type ServerBind struct {
+	Group    string   // 服务组名
+	ServerId uint32   // 节点 id
+	UserIds  []uint64 // 本网关上的玩家
 }
 
 type Resp struct {
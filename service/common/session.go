@@ -5,7 +5,9 @@ import (
 	"gameServer/pkg/config"
 	"gameServer/pkg/logger/log2"
 	"net"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/panjf2000/gnet/v2"
@@ -17,15 +19,47 @@ import (
 const (
 	// PingCheckInterval 心跳检查间隔
 	PingCheckInterval = 3 * time.Second
+
+	// GateGroup 网关服务组名，节点推送时按此组查找玩家所在网关
+	GateGroup = "gate"
+)
+
+// Platform 登录平台，与登录协议的 loginType 一致
+type Platform uint8
+
+const (
+	PlatformGuest  Platform = 0 // 游客
+	PlatformDouyin Platform = 1 // 抖音
 )
 
+// String 平台名称，用于日志和统计
+func (p Platform) String() string {
+	switch p {
+	case PlatformGuest:
+		return "guest"
+	case PlatformDouyin:
+		return "douyin"
+	}
+	return "unknown"
+}
+
+// Player 玩家上下文，登录时由网关填充，随每个请求传给节点的处理函数
 type Player struct {
-	ServerIds []uint32 //玩家所链接的服务器,网关编号在1~999
+	ServerIds []uint32 //玩家所链接的服务器，所属服务组由节点元数据决定
 
-	accountID    uint64
-	realServerID uint32 // 角色当前所在的区服 id
-	serverID     uint32 // 角色归属区服 id
 	UserId       uint64 // 角色 id
+	AccountId    string // 第三方账号 id(openid)，游客为客户端登录码
+	ServerId     uint32 // 角色归属区服 id
+	RealServerId uint32 // 角色当前所在的区服 id
+
+	// 客户端信息，登录时上报
+	ClientVersion string
+	Platform      Platform
+	DeviceId      string
+	Locale        string // 如 zh-CN
+
+	GateId    uint32 // 登录的网关 id
+	SessionId uint64 // 网关会话 id，重新登录后变化
 }
 
 var sessionPool = bytes.NewPool(func() *Session {
@@ -53,6 +87,15 @@ type Session struct {
 
 	closeOnce sync.Once
 	lock      sync.RWMutex
+
+	// serverSN 服务端发起请求的自增编号，与客户端 SN 相互独立
+	serverSN atomic.Uint32
+	// pending 等待客户端应答的服务端请求，sn-应答通道
+	pending     map[uint32]chan *Message
+	pendingLock sync.Mutex
+
+	// versions 会话内固定的版本范围，组名-[versionMin, versionMax]
+	versions map[string][2]uint32
 }
 
 // -------------------------------------- 外部 --------------------------------------
@@ -135,6 +178,142 @@ func (s *Session) Reset() {
 	//s.version = nil
 }
 
+// PinnedVersion 会话在该组固定的版本范围
+func (s *Session) PinnedVersion(group string) (uint32, uint32, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	v, ok := s.versions[group]
+	return v[0], v[1], ok
+}
+
+// PinVersion 固定会话在该组的版本范围，重新登录后重新计算
+func (s *Session) PinVersion(group string, versionMin, versionMax uint32) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.versions == nil {
+		s.versions = make(map[string][2]uint32)
+	}
+	s.versions[group] = [2]uint32{versionMin, versionMax}
+}
+
+// ServerIds 玩家绑定的节点 id，写入时整体替换，返回的切片不会再被修改
+func (s *Session) ServerIds() []uint32 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.Player == nil {
+		return nil
+	}
+	return s.Player.ServerIds
+}
+
+// UpdateServerIds 修改玩家绑定的节点，fn 返回新的切片，不能修改传入的切片
+//
+// 网关的读协程和 rpcx 协程都会修改绑定，统一在 lock 内整体替换
+func (s *Session) UpdateServerIds(fn func(serverIds []uint32) []uint32) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.Player == nil {
+		return
+	}
+	s.Player.ServerIds = fn(s.Player.ServerIds)
+}
+
+// AddServerId 绑定节点，已绑定时忽略
+func (s *Session) AddServerId(id uint32) {
+	s.UpdateServerIds(func(serverIds []uint32) []uint32 {
+		if slices.Contains(serverIds, id) {
+			return serverIds
+		}
+		return append(slices.Clip(serverIds), id)
+	})
+}
+
+// RemoveServerId 解除与节点的绑定，返回是否曾绑定
+func (s *Session) RemoveServerId(id uint32) bool {
+	removed := false
+	s.UpdateServerIds(func(serverIds []uint32) []uint32 {
+		if !slices.Contains(serverIds, id) {
+			return serverIds
+		}
+		removed = true
+		return slices.DeleteFunc(slices.Clone(serverIds), func(serverId uint32) bool { return serverId == id })
+	})
+	return removed
+}
+
+// PlayerCopy 玩家信息的副本，随请求传给节点，不受之后修改绑定的影响
+func (s *Session) PlayerCopy() *Player {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.Player == nil {
+		return nil
+	}
+	p := *s.Player
+	return &p
+}
+
+// NextServerSN 分配一个服务端请求编号，从 1 开始
+func (s *Session) NextServerSN() uint32 {
+	sn := s.serverSN.Add(1)
+	if sn == 0 { // 回绕
+		sn = s.serverSN.Add(1)
+	}
+	return sn
+}
+
+// AddPending 登记等待应答的服务端请求，返回接收应答的通道，会话关闭时通道被关闭
+func (s *Session) AddPending(sn uint32) chan *Message {
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+
+	if s.pending == nil {
+		s.pending = make(map[uint32]chan *Message)
+	}
+	ch := make(chan *Message, 1)
+	s.pending[sn] = ch
+	return ch
+}
+
+// RemovePending 移除等待应答的服务端请求
+func (s *Session) RemovePending(sn uint32) {
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+
+	delete(s.pending, sn)
+}
+
+// DeliverReply 将客户端应答交给对应的服务端请求，重复或过期的应答返回 false
+//
+// 应答内容会被复制，调用方可以立即释放 message
+func (s *Session) DeliverReply(message *Message) bool {
+	s.pendingLock.Lock()
+	ch, ok := s.pending[message.Head.SN]
+	if ok {
+		delete(s.pending, message.Head.SN) // 重发导致的重复应答只处理一次
+	}
+	s.pendingLock.Unlock()
+	if !ok {
+		return false
+	}
+
+	ch <- &Message{
+		Head: &MessageHead{
+			Len:      message.Head.Len,
+			Flag:     message.Head.Flag,
+			SN:       message.Head.SN,
+			Code:     message.Head.Code,
+			Protocol: message.Head.Protocol,
+		},
+		Body: append([]byte(nil), message.Body...),
+	}
+	return true
+}
+
 // -------------------------------------- 内部 --------------------------------------
 
 func NewSession(c gnet.Conn) *Session {
@@ -190,6 +369,7 @@ func (s *Session) incrementAndCheckRetries(n *int, maxN int) bool {
 func (s *Session) Close() {
 	s.closeOnce.Do(func() {
 		s.shutdown()
+		s.closePending()
 
 		// 关闭连接
 		s.lock.Lock()
@@ -210,6 +390,24 @@ func (s *Session) reset() {
 	//s.closeChan = nil
 	s.shareKey = ""
 	s.Player = nil
+	s.serverSN.Store(0)
+	s.versions = nil
+
+	// 迟到的 DeliverReply 可能与重置同时执行
+	s.pendingLock.Lock()
+	s.pending = nil
+	s.pendingLock.Unlock()
+}
+
+// closePending 关闭所有等待应答的通道，等待方立即返回
+func (s *Session) closePending() {
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+
+	for sn, ch := range s.pending {
+		close(ch)
+		delete(s.pending, sn)
+	}
 }
 
 // shutdown 关闭账号，但不关闭连接
@@ -222,7 +420,7 @@ func (s *Session) shutdown() {
 	log2.Get().Debug(
 		"[gate.session.shutdown] exit",
 		zap.String("address", s.RemoteAddrString()),
-		zap.Uint64("account", s.accountID()),
+		zap.String("account", s.accountID()),
 		zap.Uint32("server", s.serverID()),
 		zap.Uint64("role", uint64(s.UserID())),
 	)
@@ -248,11 +446,11 @@ func (s *Session) checkHeart() (isTimeout bool) {
 	return
 }
 
-func (s *Session) accountID() uint64 {
+func (s *Session) accountID() string {
 	if s.Player == nil {
-		return 0
+		return ""
 	}
-	return s.Player.accountID
+	return s.Player.AccountId
 }
 
 // RealServerID 玩家当前所处区服
@@ -260,14 +458,14 @@ func (s *Session) RealServerID() uint32 {
 	if s.Player == nil {
 		return 0
 	}
-	return s.Player.realServerID
+	return s.Player.RealServerId
 }
 
 func (s *Session) serverID() uint32 {
 	if s.Player == nil {
 		return 0
 	}
-	return s.Player.serverID
+	return s.Player.ServerId
 }
 
 func (s *Session) UserID() uint64 {
@@ -283,14 +481,30 @@ var PlayerPool = bytes.NewPool(func() *Player {
 
 // Reset 重置
 func (p *Player) Reset() {
-	p.accountID = 0
-	p.realServerID = 0
-	p.serverID = 0
+	p.ServerIds = nil
 	p.UserId = 0
+	p.AccountId = ""
+	p.ServerId = 0
+	p.RealServerId = 0
+	p.ClientVersion = ""
+	p.Platform = PlatformGuest
+	p.DeviceId = ""
+	p.Locale = ""
+	p.GateId = 0
+	p.SessionId = 0
 }
 
-func (p *Player) set(accountID uint64, realServerID uint32) {
-	p.accountID = accountID
-	p.realServerID = realServerID
-	p.serverID = realServerID
+// LogFields 玩家上下文日志字段，用于分析统计
+func (p *Player) LogFields() []zap.Field {
+	return []zap.Field{
+		zap.Uint64("userId", p.UserId),
+		zap.String("account", p.AccountId),
+		zap.Uint32("server", p.RealServerId),
+		zap.String("platform", p.Platform.String()),
+		zap.String("clientVersion", p.ClientVersion),
+		zap.String("deviceId", p.DeviceId),
+		zap.String("locale", p.Locale),
+		zap.Uint32("gate", p.GateId),
+		zap.Uint64("session", p.SessionId),
+	}
 }
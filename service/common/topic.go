@@ -0,0 +1,34 @@
+package common
+
+import "strconv"
+
+// TopicGlobal 全服主题，玩家登录后自动订阅
+const TopicGlobal = "global"
+
+// RegionTopic 区服主题
+func RegionTopic(serverId uint32) string {
+	return "region:" + strconv.Itoa(int(serverId))
+}
+
+// RoomTypeTopic 房间类型主题，开局后订阅，结束时取消
+func RoomTypeTopic(roomType uint32) string {
+	return "roomType:" + strconv.Itoa(int(roomType))
+}
+
+// UserTopic 玩家个人主题，登录后自动订阅，用于向不知道所在网关的玩家推送，如组队邀请
+func UserTopic(userId uint64) string {
+	return "user:" + strconv.FormatUint(userId, 10)
+}
+
+// TopicMessage 按主题推送的消息，rpcx 和 redis 共用
+type TopicMessage struct {
+	Topic    string
+	Protocol uint16
+	Body     []byte
+}
+
+// TopicSubscribe 订阅、取消订阅请求，UserIds 为同一网关上的玩家
+type TopicSubscribe struct {
+	Topic   string
+	UserIds []uint64
+}
@@ -0,0 +1,391 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"gameServer/pkg/config"
+	"sync"
+	"time"
+
+	xclient "github.com/smallnest/rpcx/client"
+)
+
+// BreakerState 熔断器状态
+type BreakerState uint8
+
+const (
+	// BreakerClosed 正常
+	BreakerClosed BreakerState = iota
+	// BreakerOpen 熔断，节点不参与选择
+	BreakerOpen
+	// BreakerHalfOpen 试探，只放行少量请求
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerConfig 熔断配置，对应 [xxx.breaker]
+type BreakerConfig struct {
+	Window         int           // 统计最近的请求数
+	MinRequests    int           // 窗口内请求数达到后才判断
+	ErrorRate      float64       // 失败率阈值
+	SlowThreshold  time.Duration // 超过该耗时记为慢请求
+	SlowRate       float64       // 慢请求率阈值
+	OpenTimeout    time.Duration // 熔断多久后进入试探
+	HalfOpenProbes int           // 试探期放行的请求数，全部成功后恢复
+}
+
+// BuildBreakerConfig 从服务配置读取，未配置的项使用默认值
+func BuildBreakerConfig() BreakerConfig {
+	return BuildBreakerConfigFrom(config.Get())
+}
+
+// BuildBreakerConfigFrom 从指定的服务配置读取
+func BuildBreakerConfigFrom(c *config.Config) BreakerConfig {
+	b := defaultBreakerConfig()
+	service := c.Service()
+	if service == nil {
+		return b
+	}
+	if v := service.GetInt("breaker.window"); v > 0 {
+		b.Window = v
+	}
+	if v := service.GetInt("breaker.minrequests"); v > 0 {
+		b.MinRequests = v
+	}
+	if v := service.GetFloat64("breaker.errorrate"); v > 0 {
+		b.ErrorRate = v
+	}
+	if v := service.GetInt64("breaker.slowthreshold"); v > 0 {
+		b.SlowThreshold = time.Duration(v) * time.Millisecond
+	}
+	if v := service.GetFloat64("breaker.slowrate"); v > 0 {
+		b.SlowRate = v
+	}
+	if v := service.GetInt64("breaker.opentimeout"); v > 0 {
+		b.OpenTimeout = time.Duration(v) * time.Millisecond
+	}
+	if v := service.GetInt("breaker.halfopenprobes"); v > 0 {
+		b.HalfOpenProbes = v
+	}
+	return b
+}
+
+// BreakerStat 熔断器状态快照，用于监控
+type BreakerStat struct {
+	State     string  `json:"state"`
+	Requests  int     `json:"requests"`
+	ErrorRate float64 `json:"errorRate"`
+	SlowRate  float64 `json:"slowRate"`
+	OpenedAt  int64   `json:"openedAt,omitempty"` // 毫秒
+}
+
+// outcome 一次请求的结果
+type outcome struct {
+	failed bool
+	slow   bool
+}
+
+// breaker 单个节点的熔断器
+type breaker struct {
+	state    BreakerState
+	window   []outcome // 环形
+	next     int
+	count    int
+	openedAt time.Time
+	probedAt time.Time // 本轮试探开始时间
+	probes   int       // 试探期已放行
+	passed   int       // 试探期已成功
+}
+
+// Breakers 按节点地址管理熔断器
+type Breakers struct {
+	config BreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// defaultBreakerConfig 默认熔断配置
+func defaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		Window:         20,
+		MinRequests:    10,
+		ErrorRate:      0.5,
+		SlowThreshold:  time.Second,
+		SlowRate:       0.8,
+		OpenTimeout:    5 * time.Second,
+		HalfOpenProbes: 3,
+	}
+}
+
+// NewBreakers 创建
+//
+//   - config: 未设置统计窗口时使用默认配置
+func NewBreakers(config BreakerConfig) *Breakers {
+	if config.Window <= 0 {
+		config = defaultBreakerConfig()
+	}
+	return &Breakers{
+		config:   config,
+		breakers: make(map[string]*breaker),
+	}
+}
+
+// Available 节点是否可参与选择，熔断到期时转为试探
+func (b *Breakers) Available(address string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.breakers[address]
+	if br == nil {
+		return true
+	}
+	b.refresh(br)
+	return br.state == BreakerClosed || (br.state == BreakerHalfOpen && br.probes < b.config.HalfOpenProbes)
+}
+
+// Acquire 选中节点时调用，试探期占用一个名额
+func (b *Breakers) Acquire(address string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.breakers[address]
+	if br == nil {
+		return true
+	}
+	b.refresh(br)
+	switch br.state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if br.probes >= b.config.HalfOpenProbes {
+			return false
+		}
+		br.probes++
+	}
+	return true
+}
+
+// Release 归还试探名额，选中节点后请求结果不计入统计时调用
+func (b *Breakers) Release(address string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.breakers[address]
+	if br == nil || br.state != BreakerHalfOpen {
+		return
+	}
+	if br.probes > br.passed {
+		br.probes--
+	}
+}
+
+// Record 记录请求结果，不计入统计的结果归还试探名额
+func (b *Breakers) Record(address string, err error, latency time.Duration) {
+	if address == "" {
+		return
+	}
+	if !countable(err) {
+		b.Release(address)
+		return
+	}
+	result := outcome{
+		failed: err != nil,
+		slow:   latency >= b.config.SlowThreshold,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.breakers[address]
+	if br == nil {
+		br = &breaker{window: make([]outcome, b.config.Window)}
+		b.breakers[address] = br
+	}
+
+	switch br.state {
+	case BreakerHalfOpen:
+		if result.failed || result.slow {
+			b.open(br)
+			return
+		}
+		br.passed++
+		if br.passed >= b.config.HalfOpenProbes {
+			b.close(br)
+		}
+	case BreakerClosed:
+		br.window[br.next] = result
+		br.next = (br.next + 1) % len(br.window)
+		if br.count < len(br.window) {
+			br.count++
+		}
+		if br.count >= b.config.MinRequests {
+			errorRate, slowRate := br.rates()
+			if errorRate >= b.config.ErrorRate || slowRate >= b.config.SlowRate {
+				b.open(br)
+			}
+		}
+	}
+}
+
+// Remove 节点下线时清除
+func (b *Breakers) Remove(address string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.breakers, address)
+}
+
+// Stats 所有节点的状态
+func (b *Breakers) Stats() map[string]BreakerStat {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make(map[string]BreakerStat, len(b.breakers))
+	for address, br := range b.breakers {
+		b.refresh(br)
+		errorRate, slowRate := br.rates()
+		stat := BreakerStat{
+			State:     br.state.String(),
+			Requests:  br.count,
+			ErrorRate: errorRate,
+			SlowRate:  slowRate,
+		}
+		if br.state != BreakerClosed {
+			stat.OpenedAt = br.openedAt.UnixMilli()
+		}
+		out[address] = stat
+	}
+	return out
+}
+
+// -------------------------------------- 内部 --------------------------------------
+
+// refresh 熔断到期转为试探；试探超过 OpenTimeout 仍没有结果时重新开始一轮，避免名额丢失后节点永久剔除
+func (b *Breakers) refresh(br *breaker) {
+	switch br.state {
+	case BreakerOpen:
+		if time.Since(br.openedAt) < b.config.OpenTimeout {
+			return
+		}
+		br.state = BreakerHalfOpen
+	case BreakerHalfOpen:
+		if br.probes == 0 || time.Since(br.probedAt) < b.config.OpenTimeout {
+			return
+		}
+	default:
+		return
+	}
+	br.probedAt = time.Now()
+	br.probes = 0
+	br.passed = 0
+}
+
+func (b *Breakers) open(br *breaker) {
+	br.state = BreakerOpen
+	br.openedAt = time.Now()
+}
+
+func (b *Breakers) close(br *breaker) {
+	br.state = BreakerClosed
+	br.next = 0
+	br.count = 0
+	clear(br.window)
+}
+
+func (br *breaker) rates() (float64, float64) {
+	if br.count == 0 {
+		return 0, 0
+	}
+	failed, slow := 0, 0
+	for i := 0; i < br.count; i++ {
+		if br.window[i].failed {
+			failed++
+		}
+		if br.window[i].slow {
+			slow++
+		}
+	}
+	return float64(failed) / float64(br.count), float64(slow) / float64(br.count)
+}
+
+// countable 业务错误和调用方主动取消不计入节点健康
+func countable(err error) bool {
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, xclient.ErrXClientNoServer) {
+		return false
+	}
+	var serviceError xclient.ServiceError
+	return !errors.As(err, &serviceError)
+}
+
+// selectedKey 记录本次调用选中的节点地址
+type selectedKey struct{}
+
+// selected 由选择器写入
+type selected struct {
+	address string
+	// failed 故障转移前选中的节点，这些节点的请求已失败
+	failed []string
+}
+
+// errFailedOver 故障转移时之前选中节点的结果
+var errFailedOver = errors.New("rpc: failed over to another node")
+
+// record 记录本次调用各选中节点的结果
+func (s *selected) record(b *Breakers, err error, latency time.Duration) {
+	for _, address := range s.failed {
+		b.Record(address, errFailedOver, latency)
+	}
+	b.Record(s.address, err, latency)
+}
+
+// withSelected 调用前放入 ctx，选择器据此回填地址
+func withSelected(ctx context.Context) (context.Context, *selected) {
+	s := &selected{}
+	return context.WithValue(ctx, selectedKey{}, s), s
+}
+
+// SetSelected 选择器选中节点后调用，ctx 中没有记录时忽略
+func SetSelected(ctx context.Context, address string) {
+	if s, ok := ctx.Value(selectedKey{}).(*selected); ok {
+		if s.address != "" {
+			s.failed = append(s.failed, s.address)
+		}
+		s.address = address
+	}
+}
+
+// clients 已创建的客户端，服务对象名称-客户端(*Client 或 *LocalClient)，用于监控
+var clients sync.Map
+
+// BreakerStats 所有客户端的熔断状态，服务对象名称-节点地址-状态
+func BreakerStats() map[string]map[string]BreakerStat {
+	out := make(map[string]map[string]BreakerStat)
+	clients.Range(func(key, value any) bool {
+		out[key.(string)] = value.(interface {
+			BreakerStats() map[string]BreakerStat
+		}).BreakerStats()
+		return true
+	})
+	return out
+}
+
+func init() {
+	// 通过 pprof 地址的 /debug/vars 查看
+	expvar.Publish("rpc_breakers", expvar.Func(func() any {
+		return BreakerStats()
+	}))
+}
@@ -1,21 +1,21 @@
 package client
 
 import (
-	"fmt"
 	"gameServer/pkg/config"
+	"gameServer/service/rpc/codec"
+	"gameServer/service/rpc/discovery"
 	"time"
 
 	xclient "github.com/smallnest/rpcx/client"
+	"github.com/smallnest/rpcx/protocol"
 )
 
 // 客户端rpcx 配置
 type ClientConfig struct {
 	// etcd 心跳间隔
 	HeartbeatInterval time.Duration
-	// etcd 服务器地址列表
-	EtcdEndpoints []string
-	// etcd 服务注册基础路径，相当于缓存前缀，避免不同项目使用同一个 etcd 造成混乱，例如 MODOU_LDL
-	BasePath string
+	// 服务发现配置，etcd、固定节点列表或局域网多播
+	Discovery discovery.Config
 	// 服务名称，例如 gate, game, battle
 	ServiceName string
 	// 服务对象名称，例如 Forward，Gate
@@ -29,6 +29,12 @@ type ClientConfig struct {
 	SelectMode xclient.SelectMode
 	// 当路由方式为自定义时，此处传入自定义的路由选择器
 	Selector xclient.Selector
+	// 节点熔断配置
+	Breaker BreakerConfig
+	// Local 使用进程内传输，见 local.Registry
+	Local bool
+	// SerializeType 序列化类型，为 0 时使用 codec.SerializeEnvelope
+	SerializeType protocol.SerializeType
 }
 
 // Client rpcx 客户端接口实现
@@ -37,10 +43,18 @@ type Client struct {
 	config *ClientConfig
 	// rpcx 客户端
 	pool *xclient.XClientPool
-	// etcd 服务发现
+	// 服务发现
 	discovery xclient.ServiceDiscovery
 
 	selector xclient.Selector
+
+	// breakers 按节点地址的熔断器
+	breakers *Breakers
+}
+
+// breakerAware 支持熔断剔除的选择器
+type breakerAware interface {
+	SetBreaker(b *Breakers)
 }
 
 // 从服务配置中创建
@@ -52,19 +66,23 @@ type Client struct {
 //   - selectMode: 选择模式，例如 xclient.RandomSelect, xclient.RoundRobin
 //   - selector: 选择器，例如 &pkgrpcclient.ServerIDSelector{}
 func BuildClientConfig(name, methodName string, poolSize int, failMode xclient.FailMode, selectMode xclient.SelectMode, selector xclient.Selector) *ClientConfig {
-	c := config.Get()
+	return BuildClientConfigFrom(config.Get(), name, methodName, poolSize, failMode, selectMode, selector)
+}
 
-	basePath := fmt.Sprintf("%s", c.EtcdPrefix())
+// BuildClientConfigFrom 从指定的服务配置创建，同一进程运行多个服务时使用
+func BuildClientConfigFrom(c *config.Config, name, methodName string, poolSize int, failMode xclient.FailMode, selectMode xclient.SelectMode, selector xclient.Selector) *ClientConfig {
 
 	return &ClientConfig{
 		HeartbeatInterval: time.Duration(c.RPCHeart()) * time.Second,
-		EtcdEndpoints:     c.EtcdAddress(), //":12379;:22329;:32329"
-		BasePath:          basePath,
+		Discovery:         discovery.BuildConfig(),
 		ServiceName:       name, //没用上
 		ServicePath:       methodName,
 		PoolSize:          poolSize,
 		FailMode:          failMode,
 		SelectMode:        selectMode,
 		Selector:          selector,
+		Breaker:           BuildBreakerConfigFrom(c),
+		Local:             c.RPCLocal(),
+		SerializeType:     codec.Parse(c.RPCCodec()),
 	}
 }
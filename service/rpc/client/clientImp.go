@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"gameServer/pkg/bytes"
 	"gameServer/service/rpc"
+	"gameServer/service/rpc/codec"
+	"gameServer/service/rpc/discovery"
 	"strconv"
 	"time"
 
-	etcdClient "github.com/rpcxio/rpcx-etcd/client"
 	xclient "github.com/smallnest/rpcx/client"
 	"github.com/smallnest/rpcx/protocol"
 	"github.com/smallnest/rpcx/share"
@@ -17,30 +18,28 @@ import (
 // New 创建客户端
 func NewClient(config *ClientConfig) (*Client, error) {
 	c := &Client{
-		config: config,
+		config:   config,
+		breakers: NewBreakers(config.Breaker),
 	}
 
 	// 创建服务发现
 	//gate: node/Forward
 	//node: node/Gate
-	discovery, err := etcdClient.NewEtcdV3Discovery(
-		config.BasePath,    //基本路径 node
-		config.ServicePath, //具体分支路径的服务对象
-		config.EtcdEndpoints,
-		true, //监听节点上下线变化
-		nil,
-	)
+	d, err := discovery.NewDiscovery(config.Discovery, config.ServicePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create etcd service discovery: %v", err)
+		return nil, fmt.Errorf("failed to create %s service discovery: %v", config.Discovery.Kind, err)
 	}
-	c.discovery = discovery
+	c.discovery = d
 
 	// 创建客户端选项
 	option := xclient.DefaultOption
 	//option.Heartbeat = true
 	//option.HeartbeatInterval = config.HeartbeatInterval
 	option.ConnectTimeout = time.Second * 3
-	option.SerializeType = protocol.MsgPack
+	option.SerializeType = codec.SerializeEnvelope
+	if config.SerializeType > 0 {
+		option.SerializeType = config.SerializeType
+	}
 	option.CompressType = protocol.None
 	option.BackupLatency = time.Second // 设置故障转移延迟
 	option.Retries = 1                 // 设置重试次数
@@ -67,17 +66,22 @@ func NewClient(config *ClientConfig) (*Client, error) {
 		config.ServicePath, //必须和保持和etcd一致
 		failMode,
 		selectMode,
-		discovery,
+		d,
 		option,
 	)
 
 	if config.Selector != nil {
+		// 熔断的节点不参与选择
+		if s, ok := config.Selector.(breakerAware); ok {
+			s.SetBreaker(c.breakers)
+		}
 		for range poolSize {
 			pool.Get().SetSelector(config.Selector)
 		}
 	}
 
 	c.pool = pool
+	clients.Store(config.ServicePath, c)
 
 	return c, nil
 }
@@ -89,14 +93,50 @@ func (c *Client) Wrap(id, versionMin, versionMax uint32) rpc.WrapClient {
 	return wc
 }
 
-// Call 同步调用
+// Call 同步调用，结果计入选中节点的熔断统计
 func (c *Client) Call(ctx context.Context, serviceMethod string, args any, reply any) error {
-	return c.pool.Get().Call(ctx, serviceMethod, args, reply)
+	ctx, sel := withSelected(ctx)
+	start := time.Now()
+	err := c.pool.Get().Call(ctx, serviceMethod, args, reply)
+	sel.record(c.breakers, err, time.Since(start))
+	return err
 }
 
-// Go 异步调用
+// Go 异步调用，通过 Call 完成，结果同样计入熔断统计
 func (c *Client) Go(ctx context.Context, serviceMethod string, args any, reply any, done chan *xclient.Call) (*xclient.Call, error) {
-	return c.pool.Get().Go(ctx, serviceMethod, args, reply, done)
+	if done == nil {
+		done = make(chan *xclient.Call, 1)
+	}
+	call := &xclient.Call{
+		ServicePath:   c.config.ServicePath,
+		ServiceMethod: serviceMethod,
+		Args:          args,
+		Reply:         reply,
+		Done:          done,
+	}
+	go func() {
+		call.Error = c.Call(ctx, serviceMethod, args, reply)
+		select {
+		case call.Done <- call:
+		default: // 与 rpcx 一致，通道已满时丢弃
+		}
+	}()
+	return call, nil
+}
+
+// Broadcast 调用所有服务节点
+func (c *Client) Broadcast(ctx context.Context, serviceMethod string, args any, reply any) error {
+	return c.pool.Get().Broadcast(ctx, serviceMethod, args, reply)
+}
+
+// BreakerStats 各节点熔断状态
+func (c *Client) BreakerStats() map[string]BreakerStat {
+	return c.breakers.Stats()
+}
+
+// Breakers 按节点地址的熔断器，流链接调用共用
+func (c *Client) Breakers() *Breakers {
+	return c.breakers
 }
 
 // Close 关闭客户端
@@ -125,8 +165,14 @@ func (c *Client) GetSelector() xclient.Selector {
 //	Go(ctx context.Context, serviceMethod string, args any, reply any, done chan *xclient.Call) (*xclient.Call, error)
 //}
 
+// caller 被包装的客户端，rpcx 客户端和进程内客户端
+type caller interface {
+	Call(ctx context.Context, serviceMethod string, args any, reply any) error
+	Go(ctx context.Context, serviceMethod string, args any, reply any, done chan *xclient.Call) (*xclient.Call, error)
+}
+
 type wrapClient struct {
-	c          *Client
+	c          caller
 	id         uint32
 	versionMin uint32
 	versionMax uint32
@@ -156,7 +202,7 @@ func (w *wrapClient) Go(ctx context.Context, serviceMethod string, args any, rep
 	return w.c.Go(w.buildCtx(ctx), serviceMethod, args, reply, done)
 }
 
-func (w *wrapClient) reset(c *Client, id, versionMin, versionMax uint32) {
+func (w *wrapClient) reset(c caller, id, versionMin, versionMax uint32) {
 	w.c = c
 	w.id = id
 	w.versionMin = versionMin
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"gameServer/service/rpc"
+	"gameServer/service/rpc/local"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	xclient "github.com/smallnest/rpcx/client"
+)
+
+// LocalClient 进程内客户端，通过注册表直接调用同进程的服务对象，语义与 rpcx 客户端一致
+type LocalClient struct {
+	config    *ClientConfig
+	registry  *local.Registry
+	discovery xclient.ServiceDiscovery
+	watch     chan []*xclient.KVPair
+
+	// 未设置选择器时轮询
+	mu        sync.RWMutex
+	addresses []string
+	next      atomic.Uint64
+
+	breakers  *Breakers
+	closeOnce sync.Once
+}
+
+// New 按配置创建客户端，开启 rpclocal 时创建进程内客户端
+func New(config *ClientConfig) (rpc.ClientInterface, error) {
+	if config.Local {
+		return NewLocalClient(config, local.Default())
+	}
+	return NewClient(config)
+}
+
+// NewLocalClient 创建进程内客户端
+//
+//   - registry: 注册表，为 nil 时使用进程默认注册表
+func NewLocalClient(config *ClientConfig, registry *local.Registry) (*LocalClient, error) {
+	if registry == nil {
+		registry = local.Default()
+	}
+	c := &LocalClient{
+		config:    config,
+		registry:  registry,
+		discovery: registry.Discovery(config.ServicePath),
+		breakers:  NewBreakers(config.Breaker),
+	}
+	if s, ok := config.Selector.(breakerAware); ok {
+		s.SetBreaker(c.breakers)
+	}
+
+	c.watch = c.discovery.WatchService()
+	c.update(c.discovery.GetServices())
+	go func() {
+		for pairs := range c.watch {
+			c.update(pairs)
+		}
+	}()
+
+	clients.Store(config.ServicePath, c)
+	return c, nil
+}
+
+// Wrap 指定 id 调用
+func (c *LocalClient) Wrap(id, versionMin, versionMax uint32) rpc.WrapClient {
+	wc := wrapClientPool.Get()
+	wc.reset(c, id, versionMin, versionMax)
+	return wc
+}
+
+// Call 同步调用，结果计入选中节点的熔断统计
+func (c *LocalClient) Call(ctx context.Context, serviceMethod string, args any, reply any) error {
+	ctx, sel := withSelected(ctx)
+	address := c.selectServer(ctx, serviceMethod, args)
+	if address == "" {
+		return xclient.ErrXClientNoServer
+	}
+	sel.address = address
+
+	start := time.Now()
+	err := c.invoke(ctx, address, serviceMethod, args, reply)
+	c.breakers.Record(address, err, time.Since(start))
+	return err
+}
+
+// Go 异步调用
+func (c *LocalClient) Go(ctx context.Context, serviceMethod string, args any, reply any, done chan *xclient.Call) (*xclient.Call, error) {
+	if done == nil {
+		done = make(chan *xclient.Call, 1)
+	}
+	call := &xclient.Call{
+		ServicePath:   c.config.ServicePath,
+		ServiceMethod: serviceMethod,
+		Args:          args,
+		Reply:         reply,
+		Done:          done,
+	}
+	go func() {
+		call.Error = c.Call(ctx, serviceMethod, args, reply)
+		select {
+		case call.Done <- call:
+		default: // 与 rpcx 一致，通道已满时丢弃
+		}
+	}()
+	return call, nil
+}
+
+// Broadcast 调用所有服务节点，任一节点失败时返回错误
+func (c *LocalClient) Broadcast(ctx context.Context, serviceMethod string, args any, reply any) error {
+	servers := c.registry.Servers(c.config.ServicePath)
+	if len(servers) == 0 {
+		return xclient.ErrXClientNoServer
+	}
+	var errs []error
+	for address := range servers {
+		if err := c.invoke(ctx, address, serviceMethod, args, reply); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// BreakerStats 各节点熔断状态
+func (c *LocalClient) BreakerStats() map[string]BreakerStat {
+	return c.breakers.Stats()
+}
+
+// Breakers 按节点地址的熔断器，流链接调用共用
+func (c *LocalClient) Breakers() *Breakers {
+	return c.breakers
+}
+
+// Close 关闭客户端，停止监听注册表
+func (c *LocalClient) Close() error {
+	c.closeOnce.Do(func() {
+		c.discovery.RemoveWatcher(c.watch)
+		close(c.watch)
+	})
+	return nil
+}
+
+// Name rpc 服务提供者名称
+func (c *LocalClient) Name() string {
+	return c.config.ServiceName
+}
+
+func (c *LocalClient) GetSelector() xclient.Selector {
+	return c.config.Selector
+}
+
+// selectServer 有选择器时由选择器决定，否则轮询
+func (c *LocalClient) selectServer(ctx context.Context, serviceMethod string, args any) string {
+	if c.config.Selector != nil {
+		return c.config.Selector.Select(ctx, c.config.ServicePath, serviceMethod, args)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.addresses) == 0 {
+		return ""
+	}
+	return c.addresses[int(c.next.Add(1)%uint64(len(c.addresses)))]
+}
+
+func (c *LocalClient) invoke(ctx context.Context, address, serviceMethod string, args any, reply any) error {
+	rcvr, ok := c.registry.Lookup(c.config.ServicePath, address)
+	if !ok { // 选择后节点已下线
+		return xclient.ErrXClientNoServer
+	}
+	return local.Invoke(ctx, rcvr, serviceMethod, args, reply)
+}
+
+// update 注册表变化时更新选择器和轮询列表
+func (c *LocalClient) update(pairs []*xclient.KVPair) {
+	servers := make(map[string]string, len(pairs))
+	addresses := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		servers[p.Key] = p.Value
+		addresses = append(addresses, p.Key)
+	}
+	sort.Strings(addresses)
+
+	c.mu.Lock()
+	c.addresses = addresses
+	c.mu.Unlock()
+
+	if c.config.Selector != nil {
+		c.config.Selector.UpdateServer(servers)
+	}
+}
@@ -3,10 +3,13 @@ package selector
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+
+	"gameServer/service/common"
+	"gameServer/service/rpc/client"
 
 	"github.com/smallnest/rpcx/share"
 )
@@ -15,28 +18,137 @@ import (
 type DefaultSelector struct {
 	mu      sync.RWMutex //可能并发
 	Servers []*serverInfo
+
+	// Strategies 各组的选择策略，组名-策略，未配置的组随机
+	Strategies map[string]Strategy
+	// rings 使用一致性哈希的组，UpdateServer 时重建
+	rings map[string]*hashRing
+	// breakers 节点熔断，由客户端注入，熔断的节点不参与选择
+	breakers *client.Breakers
 }
 type serverInfo struct {
 	Id      uint32
-	groupId uint32
+	group   string // 服务组名，节点配置中声明，如 gate、room、home
 	Address string
+	stream  string // 流链接地址，未开启时为空
+	userId  uint64 // 仅请求元数据使用，一致性哈希的键
+
+	// 请求指定的版本范围，versionMax 为 0 时不限制
+	versionMin uint32
+	versionMax uint32
 
 	maxVersion uint32
 	curVersion uint32
 	roomStatus uint8
 	open       bool
+
+	// 节点上报的负载
+	rooms    int64
+	sessions int64
+	cpu      int64 // 百分比
+	weight   int   // 容量权重
+	// picked 本地自上次更新以来的选择次数
+	picked atomic.Int64
+}
+
+// NewDefaultSelector 创建选择器
+//
+//   - strategies: 组名-策略名，见 ParseStrategy
+func NewDefaultSelector(strategies map[string]string) *DefaultSelector {
+	s := &DefaultSelector{
+		Servers:    make([]*serverInfo, 0),
+		Strategies: make(map[string]Strategy, len(strategies)),
+	}
+	for group, name := range strategies {
+		s.Strategies[group] = ParseStrategy(name)
+	}
+	return s
+}
+
+// BoundServer 玩家已绑定的节点中属于该组的节点 id，没有时返回 0
+func (s *DefaultSelector) BoundServer(group string, serverIds []uint32) uint32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, id := range serverIds {
+		for _, info := range s.Servers {
+			if info.Id == id && info.group == group {
+				return id
+			}
+		}
+	}
+	return 0
 }
 
-// NewRandomSelector 创建随机选择器
-//func NewDefaultSelector() *DefaultSelector {
-//	return &DefaultSelector{
-//		Servers: make([]*serverInfo, 0),
-//	}
-//}
+// SetBreaker 设置熔断器，创建客户端时调用
+func (s *DefaultSelector) SetBreaker(b *client.Breakers) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.breakers = b
+}
 
-// Select 随机选择一个服务器，由 rpcx 调用
+// ServerID 按地址查找节点 id
+func (s *DefaultSelector) ServerID(address string) uint32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, info := range s.Servers {
+		if info.Address == address {
+			return info.Id
+		}
+	}
+	return 0
+}
+
+// Address 按节点 id 查找 rpc 地址，没有时返回空
+func (s *DefaultSelector) Address(id uint32) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, info := range s.Servers {
+		if info.Id == id {
+			return info.Address
+		}
+	}
+	return ""
+}
+
+// StreamAddress 节点的流链接地址，没有时返回空
+func (s *DefaultSelector) StreamAddress(id uint32) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, info := range s.Servers {
+		if info.Id == id {
+			return info.stream
+		}
+	}
+	return ""
+}
+
+// Select 选择一个服务器，已绑定节点直接返回，否则按版本范围和策略选择，由 rpcx 调用
 func (s *DefaultSelector) Select(ctx context.Context, servicePath, serviceMethod string, _ /** args */ any) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	address := s.lookup(ctx)
+	if address == "" {
+		return ""
+	}
+	return s.acquire(ctx, address)
+}
+
+// Pick 与 Select 的选择相同，但不占用熔断试探名额，用于调用前预先确定节点，
+// 实际调用时 rpcx 再通过 Select 占用
+func (s *DefaultSelector) Pick(ctx context.Context) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lookup(ctx)
+}
 
+// lookup 按 ctx 元数据选择节点地址
+func (s *DefaultSelector) lookup(ctx context.Context) string {
 	if len(s.Servers) == 0 {
 		return ""
 	}
@@ -46,47 +158,88 @@ func (s *DefaultSelector) Select(ctx context.Context, servicePath, serviceMethod
 	}
 	// 选择一台目标机器
 	oneServer := getServerInfo(m)
-	if oneServer.groupId == 1 && oneServer.Id == 0 { //远程到来的，空网关直接返回
+	if oneServer.group == common.GateGroup && oneServer.Id == 0 { //远程到来的，空网关直接返回
 		return ""
 	}
-	address := ""
-	for _, server := range s.Servers {
-		// 假设 game-1 存在两个版本的进程
-		// v1: 线上版本
-		// v2: 新开发功能，发布前测试版本
-		//
-		// 当前登录的账号设置了白名单，必须进入 v2
-		//
-		// 此时，versionMin = 2, versionMax = 2
-		// 就会匹配到 v2 版本
-		if oneServer.Id == 0 { //没有数据,则按平均分配服务器
-			if server.groupId == oneServer.groupId && server.curVersion == server.maxVersion { //没有则返回最大版本
-				serverList := make([]*serverInfo, 0) //相同组的所有版本
-				for _, tempServer := range s.Servers {
-					if server.groupId == tempServer.groupId && tempServer.curVersion == tempServer.maxVersion {
-						serverList = append(serverList, tempServer)
-					}
-				}
-				// 从 serverList 选择一个
-				targetServer := serverList[rand.Intn(len(serverList))]
-				// 写入返回数据
-				//ctx = context.WithValue(ctx, share.ResMetaDataKey, map[string]string{
-				//	"Id": strconv.Itoa(int(targetServer.Id)),
-				//})
-				return targetServer.Address
-			}
-		} else {
+	if oneServer.Id > 0 { // 已绑定节点，熔断时直接失败，由调用方重新选择
+		for _, server := range s.Servers {
 			if server.Id == oneServer.Id {
-				//log2.Get().Info("version======", zap.Uint32("curVersion:", server.curVersion))
 				return server.Address
 			}
 		}
+		return ""
+	}
 
+	// 没有数据,则按策略分配服务器
+	serverList, ring := s.candidates(oneServer)
+	targetServer := s.pick(oneServer.group, oneServer.userId, serverList, ring)
+	if targetServer == nil {
+		return ""
 	}
+	return targetServer.Address
+}
 
+// acquire 占用熔断试探名额并记录选中地址
+func (s *DefaultSelector) acquire(ctx context.Context, address string) string {
+	if s.breakers != nil && !s.breakers.Acquire(address) {
+		return ""
+	}
+	client.SetSelected(ctx, address)
 	return address
 }
 
+// available 节点未熔断
+func (s *DefaultSelector) available(address string) bool {
+	return s.breakers == nil || s.breakers.Available(address)
+}
+
+// candidates 同组的候选节点
+//
+// 假设 game-1 存在两个版本的进程
+// v1: 线上版本
+// v2: 新开发功能，发布前测试版本
+//
+// 当前登录的账号设置了白名单，必须进入 v2，此时 versionMin = 2, versionMax = 2，就会匹配到 v2 版本；
+// 未指定版本范围时选择最大版本。版本范围内没有可用节点时（刚部署还没有旧版本、旧版本已下线）
+// 退回到不限版本，避免该组请求全部失败
+func (s *DefaultSelector) candidates(req *serverInfo) ([]*serverInfo, *hashRing) {
+	if req.versionMax > 0 {
+		if serverList := s.inRange(req); len(serverList) > 0 {
+			return serverList, nil
+		}
+	}
+
+	serverList := make([]*serverInfo, 0) //相同组的最大版本
+	for _, server := range s.Servers {
+		if server.group == req.group && server.curVersion == server.maxVersion && s.available(server.Address) {
+			serverList = append(serverList, server)
+		}
+	}
+	if len(serverList) < s.rings[req.group].size() { // 有节点熔断，按剩余节点建环
+		return serverList, nil
+	}
+	return serverList, s.rings[req.group]
+}
+
+// inRange 版本范围内最高版本的可用节点
+func (s *DefaultSelector) inRange(req *serverInfo) []*serverInfo {
+	serverList := make([]*serverInfo, 0)
+	top := uint32(0)
+	for _, server := range s.Servers {
+		if server.group != req.group || server.curVersion < req.versionMin || server.curVersion > req.versionMax || !s.available(server.Address) {
+			continue
+		}
+		if server.curVersion > top {
+			top = server.curVersion
+			serverList = serverList[:0]
+		}
+		if server.curVersion == top {
+			serverList = append(serverList, server)
+		}
+	}
+	return serverList
+}
+
 // UpdateServer 更新服务器列表，由 rpcx 调用
 func (s *DefaultSelector) UpdateServer(servers map[string]string) {
 	s.mu.Lock()
@@ -98,11 +251,11 @@ func (s *DefaultSelector) UpdateServer(servers map[string]string) {
 
 	// 更新版本
 	s.Servers = make([]*serverInfo, 0)
-	groupIdMaxVersionMap := make(map[uint32]uint32) //各个服务的最大版本
+	groupMaxVersionMap := make(map[string]uint32) //各个服务的最大版本
 	for address, metadata := range servers {
 		serverMetadata := parseServerMetadata(metadata, address)
-		if groupIdMaxVersionMap[serverMetadata.groupId] <= serverMetadata.curVersion {
-			groupIdMaxVersionMap[serverMetadata.groupId] = serverMetadata.curVersion
+		if groupMaxVersionMap[serverMetadata.group] <= serverMetadata.curVersion {
+			groupMaxVersionMap[serverMetadata.group] = serverMetadata.curVersion
 		}
 		// 排重
 		flag := false
@@ -117,7 +270,22 @@ func (s *DefaultSelector) UpdateServer(servers map[string]string) {
 		}
 	}
 	for _, info := range s.Servers { //todo？？？
-		info.maxVersion = groupIdMaxVersionMap[info.groupId]
+		info.maxVersion = groupMaxVersionMap[info.group]
+	}
+
+	// 重建哈希环，只包含最大版本
+	s.rings = make(map[string]*hashRing)
+	for group, strategy := range s.Strategies {
+		if strategy != StrategyHash {
+			continue
+		}
+		serverList := make([]*serverInfo, 0)
+		for _, info := range s.Servers {
+			if info.group == group && info.curVersion == info.maxVersion {
+				serverList = append(serverList, info)
+			}
+		}
+		s.rings[group] = newHashRing(serverList)
 	}
 }
 
@@ -125,10 +293,12 @@ func getServerInfo(metadata map[string]string) *serverInfo {
 	var (
 		id, _         = strconv.Atoi(metadata["id"])
 		curVersion, _ = strconv.Atoi(metadata["curVersion"])
-		groupId, _    = strconv.Atoi(metadata["groupId"])
 
 		//versionMax, _ = strconv.Atoi(metadata["versionMax"])
 		roomStatus, _ = strconv.Atoi(metadata["roomStatus"])
+		userId, _     = strconv.ParseUint(metadata["userId"], 10, 64)
+		versionMin, _ = strconv.Atoi(metadata["versionMin"])
+		versionMax, _ = strconv.Atoi(metadata["versionMax"])
 	)
 
 	// todo 对象池优化
@@ -136,9 +306,12 @@ func getServerInfo(metadata map[string]string) *serverInfo {
 	return &serverInfo{
 		Id:         uint32(id),
 		curVersion: uint32(curVersion),
-		groupId:    uint32(groupId), //？？？
+		group:      metadata["group"],
 		//maxVersion: uint32(versionMax),
 		roomStatus: uint8(roomStatus),
+		userId:     userId,
+		versionMin: uint32(versionMin),
+		versionMax: uint32(versionMax),
 	}
 }
 
@@ -154,9 +327,8 @@ func parseServerMetadata(metadata, address string) *serverInfo {
 		case "id":
 			t, _ := strconv.Atoi(value)
 			out.Id = uint32(t)
-		case "groupId":
-			t, _ := strconv.Atoi(value)
-			out.groupId = uint32(t)
+		case "group":
+			out.group = value
 		//case "maxVersion":
 		//	t, _ := strconv.Atoi(value)
 		//	out.curVersion = uint32(t)
@@ -166,8 +338,31 @@ func parseServerMetadata(metadata, address string) *serverInfo {
 		case "roomStatus":
 			t, _ := strconv.Atoi(value)
 			out.roomStatus = uint8(t)
+		case "rooms":
+			out.rooms, _ = strconv.ParseInt(value, 10, 64)
+		case "sessions":
+			out.sessions, _ = strconv.ParseInt(value, 10, 64)
+		case "cpu":
+			out.cpu, _ = strconv.ParseInt(value, 10, 64)
+		case "weight":
+			out.weight, _ = strconv.Atoi(value)
+		case "stream":
+			out.stream = value
 		}
 	}
 
 	return out
 }
+
+// load 节点负载
+func (info *serverInfo) load() int64 {
+	return info.rooms + info.sessions
+}
+
+// weightOrDefault 未上报权重时为 100
+func (info *serverInfo) weightOrDefault() int {
+	if info.weight <= 0 {
+		return 100
+	}
+	return info.weight
+}
@@ -0,0 +1,138 @@
+package selector
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// Strategy 玩家没有固定节点时的选择策略
+type Strategy uint8
+
+const (
+	// StrategyRandom 随机
+	StrategyRandom Strategy = iota
+	// StrategyHash 按 userId 一致性哈希，同一玩家总是落到同一节点，节点缓存保持热
+	StrategyHash
+	// StrategyLeastConn 选择负载（房间数+会话数）最小的节点
+	StrategyLeastConn
+	// StrategyWeighted 按节点容量权重随机
+	StrategyWeighted
+)
+
+// virtualNodes 一致性哈希每个节点的虚拟节点数
+const virtualNodes = 100
+
+// ParseStrategy 解析配置中的策略名，未知的按随机处理
+func ParseStrategy(name string) Strategy {
+	switch name {
+	case "hash":
+		return StrategyHash
+	case "leastconn":
+		return StrategyLeastConn
+	case "weighted":
+		return StrategyWeighted
+	default:
+		return StrategyRandom
+	}
+}
+
+// pick 从候选节点中选择一个，ring 为 nil 时按候选节点临时建环
+func (s *DefaultSelector) pick(group string, userId uint64, candidates []*serverInfo, ring *hashRing) *serverInfo {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	switch s.Strategies[group] {
+	case StrategyHash:
+		if userId > 0 {
+			if ring == nil {
+				ring = newHashRing(candidates)
+			}
+			return ring.get(userId)
+		}
+	case StrategyLeastConn:
+		return pickLeastConn(candidates)
+	case StrategyWeighted:
+		return pickWeighted(candidates)
+	}
+	return candidates[rand.Intn(len(candidates))]
+}
+
+// pickLeastConn 元数据定时上报，期间用本地选择次数补偿，避免瞬间全部打到同一节点
+func pickLeastConn(candidates []*serverInfo) *serverInfo {
+	var target *serverInfo
+	minLoad := int64(-1)
+	for _, server := range candidates {
+		load := server.load() + server.picked.Load()
+		if minLoad < 0 || load < minLoad {
+			minLoad = load
+			target = server
+		}
+	}
+	target.picked.Add(1)
+	return target
+}
+
+func pickWeighted(candidates []*serverInfo) *serverInfo {
+	total := 0
+	for _, server := range candidates {
+		total += server.weightOrDefault()
+	}
+	n := rand.Intn(total)
+	for _, server := range candidates {
+		n -= server.weightOrDefault()
+		if n < 0 {
+			return server
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// hashRing 一致性哈希环
+type hashRing struct {
+	hashes  []uint32
+	servers map[uint32]*serverInfo
+	nodes   int
+}
+
+func newHashRing(servers []*serverInfo) *hashRing {
+	r := &hashRing{
+		hashes:  make([]uint32, 0, len(servers)*virtualNodes),
+		servers: make(map[uint32]*serverInfo, len(servers)*virtualNodes),
+		nodes:   len(servers),
+	}
+	for _, server := range servers {
+		for i := 0; i < virtualNodes; i++ {
+			h := crc32.ChecksumIEEE([]byte(strconv.Itoa(int(server.Id)) + "#" + strconv.Itoa(i)))
+			if _, ok := r.servers[h]; ok { // 冲突跳过
+				continue
+			}
+			r.servers[h] = server
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+}
+
+// size 环上的节点数
+func (r *hashRing) size() int {
+	if r == nil {
+		return 0
+	}
+	return r.nodes
+}
+
+func (r *hashRing) get(userId uint64) *serverInfo {
+	if len(r.hashes) == 0 {
+		return nil
+	}
+	h := crc32.ChecksumIEEE([]byte(strconv.FormatUint(userId, 10)))
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.servers[r.hashes[i]]
+}
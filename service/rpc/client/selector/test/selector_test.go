@@ -0,0 +1,114 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"gameServer/service/rpc/client"
+	"gameServer/service/rpc/client/selector"
+
+	"github.com/smallnest/rpcx/share"
+)
+
+func newSelector(strategy string) *selector.DefaultSelector {
+	s := selector.NewDefaultSelector(map[string]string{"room": strategy})
+	s.UpdateServer(map[string]string{
+		"tcp@127.0.0.1:9001": "id=1&group=room&version=1&rooms=5&weight=100",
+		"tcp@127.0.0.1:9002": "id=2&group=room&version=2&rooms=3&weight=100",
+		"tcp@127.0.0.1:9003": "id=3&group=room&version=2&rooms=1&weight=100",
+		"tcp@127.0.0.1:9100": "id=100&group=home&version=1",
+	})
+	return s
+}
+
+func request(m map[string]string) context.Context {
+	return context.WithValue(context.Background(), share.ResMetaDataKey, m)
+}
+
+func pick(s *selector.DefaultSelector, m map[string]string) uint32 {
+	return s.ServerID(s.Pick(request(m)))
+}
+
+// 已绑定节点直接返回
+func TestSelectBound(t *testing.T) {
+	s := newSelector("random")
+	if id := pick(s, map[string]string{"id": "1", "group": "room"}); id != 1 {
+		t.Fatalf("bound id = %d, want 1", id)
+	}
+	if id := pick(s, map[string]string{"id": "9", "group": "room"}); id != 0 {
+		t.Fatalf("unknown id = %d, want 0", id)
+	}
+}
+
+// 未指定版本时只选最大版本
+func TestSelectMaxVersion(t *testing.T) {
+	s := newSelector("random")
+	for range 50 {
+		if id := pick(s, map[string]string{"id": "0", "group": "room"}); id != 2 && id != 3 {
+			t.Fatalf("picked %d, want a version 2 node", id)
+		}
+	}
+}
+
+// 版本范围内没有节点时退回最大版本
+func TestSelectVersionRange(t *testing.T) {
+	s := newSelector("random")
+	m := map[string]string{"id": "0", "group": "room", "versionMin": "1", "versionMax": "1"}
+	if id := pick(s, m); id != 1 {
+		t.Fatalf("pinned id = %d, want 1", id)
+	}
+	m["versionMin"], m["versionMax"] = "5", "6"
+	if id := pick(s, m); id != 2 && id != 3 {
+		t.Fatalf("fallback id = %d, want a version 2 node", id)
+	}
+}
+
+// 一致性哈希同一玩家总是落到同一节点
+func TestSelectHash(t *testing.T) {
+	s := newSelector("hash")
+	for userId := 1; userId <= 20; userId++ {
+		m := map[string]string{"id": "0", "group": "room", "userId": strconv.Itoa(userId)}
+		first := pick(s, m)
+		for range 5 {
+			if id := pick(s, m); id != first {
+				t.Fatalf("user %d moved from %d to %d", userId, first, id)
+			}
+		}
+	}
+}
+
+// 最小负载优先，本地选择次数计入负载
+func TestSelectLeastConn(t *testing.T) {
+	s := newSelector("leastconn")
+	m := map[string]string{"id": "0", "group": "room"}
+	if id := pick(s, m); id != 3 {
+		t.Fatalf("picked %d, want the least loaded node 3", id)
+	}
+	counts := map[uint32]int{}
+	for range 4 {
+		counts[pick(s, m)]++
+	}
+	if counts[2] == 0 {
+		t.Fatalf("local picks should spread load: %v", counts)
+	}
+}
+
+// 熔断的节点不参与选择
+func TestSelectBreaker(t *testing.T) {
+	s := newSelector("leastconn")
+	b := client.NewBreakers(client.BreakerConfig{Window: 2, MinRequests: 2, ErrorRate: 0.5, SlowThreshold: time.Second, SlowRate: 1, OpenTimeout: time.Minute, HalfOpenProbes: 1})
+	s.SetBreaker(b)
+	b.Record("tcp@127.0.0.1:9003", errors.New("io"), 0)
+	b.Record("tcp@127.0.0.1:9003", errors.New("io"), 0)
+	for range 5 {
+		if id := pick(s, map[string]string{"id": "0", "group": "room"}); id != 2 {
+			t.Fatalf("picked %d, want 2 while 3 is open", id)
+		}
+	}
+	if addr := s.Select(request(map[string]string{"id": "3", "group": "room"}), "", "", nil); addr != "" {
+		t.Fatalf("bound node is open, got %s", addr)
+	}
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gameServer/service/rpc/client"
+
+	xclient "github.com/smallnest/rpcx/client"
+)
+
+const address = "tcp@127.0.0.1:9000"
+
+func newBreakers() *client.Breakers {
+	return client.NewBreakers(client.BreakerConfig{
+		Window:         4,
+		MinRequests:    2,
+		ErrorRate:      0.5,
+		SlowThreshold:  time.Second,
+		SlowRate:       1,
+		OpenTimeout:    20 * time.Millisecond,
+		HalfOpenProbes: 1,
+	})
+}
+
+// tripped 连续失败后熔断并等待进入试探
+func tripped(t *testing.T) *client.Breakers {
+	b := newBreakers()
+	b.Record(address, errors.New("io"), 0)
+	b.Record(address, errors.New("io"), 0)
+	if b.Available(address) {
+		t.Fatal("breaker should be open after failures")
+	}
+	time.Sleep(25 * time.Millisecond)
+	if !b.Acquire(address) {
+		t.Fatal("breaker should let a probe through after OpenTimeout")
+	}
+	return b
+}
+
+// 试探成功后恢复
+func TestBreakerRecover(t *testing.T) {
+	b := tripped(t)
+	if b.Acquire(address) {
+		t.Fatal("only one probe is allowed")
+	}
+	b.Record(address, nil, time.Millisecond)
+	if state := b.Stats()[address].State; state != "closed" {
+		t.Fatalf("state = %s, want closed", state)
+	}
+}
+
+// 试探失败重新熔断
+func TestBreakerProbeFailed(t *testing.T) {
+	b := tripped(t)
+	b.Record(address, errors.New("io"), 0)
+	if b.Available(address) {
+		t.Fatal("breaker should be open after a failed probe")
+	}
+}
+
+// 不计入统计的结果归还试探名额
+func TestBreakerReleaseUncounted(t *testing.T) {
+	b := tripped(t)
+	b.Record(address, context.Canceled, 0)
+	if !b.Acquire(address) {
+		t.Fatal("canceled probe should be released")
+	}
+}
+
+// 试探名额没有结果时，超过 OpenTimeout 重新试探
+func TestBreakerStaleProbe(t *testing.T) {
+	b := tripped(t)
+	if b.Available(address) {
+		t.Fatal("probe is in flight")
+	}
+	time.Sleep(25 * time.Millisecond)
+	if !b.Acquire(address) {
+		t.Fatal("stale probe should be given back")
+	}
+}
+
+// 失败率达到阈值熔断，业务错误和主动取消不计入
+func TestBreakerErrorRate(t *testing.T) {
+	b := newBreakers()
+	for range 4 {
+		b.Record(address, errors.New("io"), 0)
+		b.Record(address, nil, 0)
+	}
+	if b.Available(address) {
+		t.Fatal("50% failures should open the breaker")
+	}
+
+	b = newBreakers()
+	for range 4 {
+		b.Record(address, context.Canceled, 0)
+		b.Record(address, xclient.NewServiceError("biz"), 0)
+	}
+	if !b.Available(address) {
+		t.Fatal("canceled calls and service errors should not open the breaker")
+	}
+}
@@ -0,0 +1,140 @@
+package codec
+
+import (
+	"gameServer/protobuf/pbGo"
+	"gameServer/service/common"
+
+	rpcxCodec "github.com/smallnest/rpcx/codec"
+	"github.com/smallnest/rpcx/protocol"
+	"github.com/smallnest/rpcx/share"
+	"google.golang.org/protobuf/proto"
+)
+
+// SerializeEnvelope 服务间 rpc 的序列化类型，rpcx 头部只有 4 位，自定义类型取 8
+//
+// RpcMessage、Resp 使用 protobuf 信封(protobuf/proto/rpcEnvelope.proto)，消息体直接作为 bytes 字段，
+// 其它类型（批量推送、灰度规则等）仍使用 msgpack
+const SerializeEnvelope protocol.SerializeType = 8
+
+func init() {
+	share.RegisterCodec(SerializeEnvelope, EnvelopeCodec{})
+}
+
+// Parse 按名称选择序列化类型，msgpack 或 protobuf，默认 protobuf 信封
+func Parse(name string) protocol.SerializeType {
+	if name == "msgpack" {
+		return protocol.MsgPack
+	}
+	return SerializeEnvelope
+}
+
+// EnvelopeCodec protobuf 信封编解码
+type EnvelopeCodec struct {
+	fallback rpcxCodec.MsgpackCodec
+}
+
+// Encode 编码
+func (c EnvelopeCodec) Encode(i any) ([]byte, error) {
+	switch v := i.(type) {
+	case *common.RpcMessage:
+		return proto.Marshal(toRpcMessage(v))
+	case common.RpcMessage:
+		return proto.Marshal(toRpcMessage(&v))
+	case *common.Resp:
+		return proto.Marshal(toRpcResp(v))
+	case common.Resp:
+		return proto.Marshal(toRpcResp(&v))
+	}
+	return c.fallback.Encode(i)
+}
+
+// Decode 解码，目标类型必须与编码时一致
+func (c EnvelopeCodec) Decode(data []byte, i any) error {
+	switch v := i.(type) {
+	case *common.RpcMessage:
+		m := &pbGo.RpcMessage{}
+		if err := proto.Unmarshal(data, m); err != nil {
+			return err
+		}
+		fromRpcMessage(m, v)
+		return nil
+	case *common.Resp:
+		m := &pbGo.RpcResp{}
+		if err := proto.Unmarshal(data, m); err != nil {
+			return err
+		}
+		v.Code = uint16(m.Code)
+		v.Flag = uint16(m.Flag)
+		v.Body = m.Body
+		return nil
+	}
+	return c.fallback.Decode(data, i)
+}
+
+func toRpcMessage(v *common.RpcMessage) *pbGo.RpcMessage {
+	out := &pbGo.RpcMessage{Retries: uint32(v.Retries)}
+	if v.Data != nil {
+		out.Body = v.Data.Body
+		if h := v.Data.Head; h != nil {
+			out.Head = &pbGo.RpcHead{
+				Len:      uint32(h.Len),
+				Flag:     uint32(h.Flag),
+				Sn:       h.SN,
+				Code:     uint32(h.Code),
+				Protocol: uint32(h.Protocol),
+			}
+		}
+	}
+	if v.Player != nil {
+		p := v.Player
+		out.Player = &pbGo.RpcPlayer{
+			ServerIds:     p.ServerIds,
+			UserId:        p.UserId,
+			AccountId:     p.AccountId,
+			ServerId:      p.ServerId,
+			RealServerId:  p.RealServerId,
+			ClientVersion: p.ClientVersion,
+			Platform:      uint32(p.Platform),
+			DeviceId:      p.DeviceId,
+			Locale:        p.Locale,
+			GateId:        p.GateId,
+			SessionId:     p.SessionId,
+		}
+	}
+	return out
+}
+
+func fromRpcMessage(m *pbGo.RpcMessage, v *common.RpcMessage) {
+	v.Retries = uint8(m.Retries)
+	v.Data = &common.Message{Head: &common.MessageHead{}, Body: m.Body}
+	if h := m.Head; h != nil {
+		v.Data.Head.Len = uint16(h.Len)
+		v.Data.Head.Flag = uint16(h.Flag)
+		v.Data.Head.SN = h.Sn
+		v.Data.Head.Code = uint16(h.Code)
+		v.Data.Head.Protocol = uint16(h.Protocol)
+	}
+	if p := m.Player; p != nil {
+		v.Player = &common.Player{
+			ServerIds:     p.ServerIds,
+			UserId:        p.UserId,
+			AccountId:     p.AccountId,
+			ServerId:      p.ServerId,
+			RealServerId:  p.RealServerId,
+			ClientVersion: p.ClientVersion,
+			Platform:      common.Platform(p.Platform),
+			DeviceId:      p.DeviceId,
+			Locale:        p.Locale,
+			GateId:        p.GateId,
+			SessionId:     p.SessionId,
+		}
+	}
+}
+
+func toRpcResp(v *common.Resp) *pbGo.RpcResp {
+	return &pbGo.RpcResp{
+		Code: uint32(v.Code),
+		Flag: uint32(v.Flag),
+		Body: v.Body,
+	}
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"gameServer/service/common"
+	"gameServer/service/rpc/codec"
+
+	rpcxCodec "github.com/smallnest/rpcx/codec"
+)
+
+func newRpcMessage() common.RpcMessage {
+	return common.RpcMessage{
+		Data: &common.Message{
+			Head: &common.MessageHead{Len: 64, Flag: 1, SN: 12345, Code: 0, Protocol: 1003},
+			Body: bytes.Repeat([]byte{0x0a}, 64),
+		},
+		Player: &common.Player{
+			ServerIds: []uint32{1, 1000, 2000}, UserId: 10086, AccountId: "openid",
+			ServerId: 1, RealServerId: 1, ClientVersion: "1.2.0", Platform: common.PlatformDouyin,
+			DeviceId: "device", Locale: "zh-CN", GateId: 1000, SessionId: 1000<<32 | 1,
+		},
+		Retries: 2,
+	}
+}
+
+func newResp() *common.Resp {
+	return &common.Resp{Code: 0, Flag: 1, Body: bytes.Repeat([]byte{0x0b}, 256)}
+}
+
+// 信封编解码后与原数据一致
+func TestEnvelopeRoundTrip(t *testing.T) {
+	c := codec.EnvelopeCodec{}
+
+	req := newRpcMessage()
+	data, err := c.Encode(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &common.RpcMessage{}
+	if err = c.Decode(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if *got.Data.Head != *req.Data.Head || !bytes.Equal(got.Data.Body, req.Data.Body) ||
+		!reflect.DeepEqual(got.Player, req.Player) || got.Retries != req.Retries {
+		t.Fatalf("RpcMessage mismatch: %+v", got)
+	}
+
+	resp := newResp()
+	if data, err = c.Encode(resp); err != nil {
+		t.Fatal(err)
+	}
+	gotResp := &common.Resp{}
+	if err = c.Decode(data, gotResp); err != nil {
+		t.Fatal(err)
+	}
+	if gotResp.Code != resp.Code || gotResp.Flag != resp.Flag || !bytes.Equal(gotResp.Body, resp.Body) {
+		t.Fatalf("Resp mismatch: %+v", gotResp)
+	}
+
+	// 其它类型使用 msgpack
+	batch := &common.BatchResp{Failed: map[uint64]string{1: "session not found"}}
+	if data, err = c.Encode(batch); err != nil {
+		t.Fatal(err)
+	}
+	gotBatch := &common.BatchResp{}
+	if err = c.Decode(data, gotBatch); err != nil || gotBatch.Failed[1] != "session not found" {
+		t.Fatalf("BatchResp mismatch: %+v, %v", gotBatch, err)
+	}
+}
+
+func benchmarkRoundTrip(b *testing.B, c interface {
+	Encode(i any) ([]byte, error)
+	Decode(data []byte, i any) error
+}) {
+	req := newRpcMessage()
+	resp := newResp()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, _ := c.Encode(req)
+		if err := c.Decode(data, &common.RpcMessage{}); err != nil {
+			b.Fatal(err)
+		}
+		data, _ = c.Encode(resp)
+		if err := c.Decode(data, &common.Resp{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// 网关到节点一次往返的请求和响应编解码
+func BenchmarkMsgpack(b *testing.B) {
+	benchmarkRoundTrip(b, rpcxCodec.MsgpackCodec{})
+}
+
+func BenchmarkEnvelope(b *testing.B) {
+	benchmarkRoundTrip(b, codec.EnvelopeCodec{})
+}
+
+func BenchmarkEnvelopeSize(b *testing.B) {
+	req := newRpcMessage()
+	m, _ := rpcxCodec.MsgpackCodec{}.Encode(req)
+	e, _ := codec.EnvelopeCodec{}.Encode(req)
+	b.ReportMetric(float64(len(m)), "msgpack-bytes")
+	b.ReportMetric(float64(len(e)), "envelope-bytes")
+}
@@ -0,0 +1,121 @@
+package discovery
+
+import (
+	"fmt"
+	"gameServer/pkg/config"
+	"time"
+
+	etcdClient "github.com/rpcxio/rpcx-etcd/client"
+	"github.com/rpcxio/rpcx-etcd/serverplugin"
+	xclient "github.com/smallnest/rpcx/client"
+)
+
+// 服务发现方式，在 [common.discovery] 的 type 中配置
+const (
+	// KindEtcd 注册到 etcd，默认方式
+	KindEtcd = "etcd"
+	// KindStatic 配置文件中的固定节点列表，节点需要配置固定的 rpcaddr
+	KindStatic = "static"
+	// KindMulticast 局域网多播，节点定时广播自身信息
+	KindMulticast = "multicast"
+)
+
+// DefaultMulticastAddress 默认多播地址
+const DefaultMulticastAddress = "239.255.42.99:9999"
+
+// Config 服务发现配置
+type Config struct {
+	// Kind 发现方式，见 KindEtcd
+	Kind string
+	// BasePath 服务注册基础路径，相当于缓存前缀，避免不同项目混乱，例如 node
+	BasePath string
+	// EtcdEndpoints etcd 服务器地址列表
+	EtcdEndpoints []string
+	// MulticastAddress 多播地址，例如 239.255.42.99:9999
+	MulticastAddress string
+	// Peers 固定节点列表
+	Peers []Peer
+}
+
+// Peer 固定节点
+type Peer struct {
+	// Service 服务对象名称，例如 Forward, Gate
+	Service string `mapstructure:"service"`
+	// Address 服务地址，例如 tcp@127.0.0.1:9101
+	Address string `mapstructure:"address"`
+	// Metadata 节点元数据，例如 id=3&version=1&group=room
+	Metadata string `mapstructure:"metadata"`
+}
+
+// Registrar 服务端注册，同时作为 rpcx 插件在注册服务时调用
+type Registrar interface {
+	Start() error
+	Stop() error
+	Register(name string, rcvr any, metadata string) error
+	Unregister(name string) error
+}
+
+// BuildConfig 从通用配置中创建
+func BuildConfig() Config {
+	c := config.Get()
+
+	out := Config{
+		Kind:             KindEtcd,
+		BasePath:         c.EtcdPrefix(),
+		EtcdEndpoints:    c.EtcdAddress(),
+		MulticastAddress: DefaultMulticastAddress,
+	}
+	common := c.Common()
+	if v := common.GetString("discovery.type"); v != "" {
+		out.Kind = v
+	}
+	if v := common.GetString("discovery.multicastaddr"); v != "" {
+		out.MulticastAddress = v
+	}
+	_ = common.UnmarshalKey("discovery.peer", &out.Peers)
+	return out
+}
+
+// NewRegistrar 创建服务端注册
+//
+//   - address: 本节点服务地址，例如 tcp@127.0.0.1:9101
+//   - updateInterval: 元数据更新间隔
+func NewRegistrar(c Config, address string, updateInterval time.Duration) (Registrar, error) {
+	switch c.Kind {
+	case KindEtcd, "":
+		return &serverplugin.EtcdV3RegisterPlugin{
+			BasePath:       c.BasePath, //根目录， eg：node/Forward,xxx/Forward
+			ServiceAddress: address,
+			EtcdServers:    c.EtcdEndpoints, //往etcd注册地址
+			UpdateInterval: updateInterval,  //信息更新间隔
+		}, nil
+	case KindStatic:
+		return staticRegistrar{}, nil
+	case KindMulticast:
+		return newMulticastRegistrar(c, address, updateInterval), nil
+	}
+	return nil, fmt.Errorf("discovery: unknown type %q", c.Kind)
+}
+
+// NewDiscovery 创建客户端服务发现
+//
+//   - servicePath: 服务对象名称，例如 Forward, Gate
+func NewDiscovery(c Config, servicePath string) (xclient.ServiceDiscovery, error) {
+	switch c.Kind {
+	case KindEtcd, "":
+		//gate: node/Forward
+		//node: node/Gate
+		return etcdClient.NewEtcdV3Discovery(
+			c.BasePath,  //基本路径 node
+			servicePath, //具体分支路径的服务对象
+			c.EtcdEndpoints,
+			true, //监听节点上下线变化
+			nil,
+		)
+	case KindStatic:
+		return newStaticDiscovery(c, servicePath)
+	case KindMulticast:
+		return newMulticastDiscovery(c, servicePath)
+	}
+	return nil, fmt.Errorf("discovery: unknown type %q", c.Kind)
+}
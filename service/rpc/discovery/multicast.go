@@ -0,0 +1,355 @@
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"gameServer/pkg/logger/log2"
+	"net"
+	"sync"
+	"time"
+
+	xclient "github.com/smallnest/rpcx/client"
+	"go.uber.org/zap"
+)
+
+const (
+	// MulticastInterval 节点广播间隔
+	MulticastInterval = time.Second
+	// MulticastTTL 超过该时间未收到广播的节点视为下线
+	MulticastTTL = 5 * MulticastInterval
+)
+
+// announcement 节点广播内容
+type announcement struct {
+	BasePath string `json:"basePath"`
+	Service  string `json:"service"`
+	Address  string `json:"address"`
+	Metadata string `json:"metadata"`
+	Leave    bool   `json:"leave,omitempty"` // 主动下线
+}
+
+// ----------------------------------------- 服务端 -----------------------------------------
+
+// multicastRegistrar 定时向多播地址广播已注册的服务
+type multicastRegistrar struct {
+	config  Config
+	address string
+
+	conn     *net.UDPConn
+	mu       sync.Mutex
+	services map[string]string // 服务对象名称-元数据
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newMulticastRegistrar(c Config, address string, _ time.Duration) *multicastRegistrar {
+	return &multicastRegistrar{
+		config:   c,
+		address:  address,
+		services: make(map[string]string),
+		stop:     make(chan struct{}),
+	}
+}
+
+func (r *multicastRegistrar) Start() error {
+	addr, err := net.ResolveUDPAddr("udp4", r.config.MulticastAddress)
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialUDP("udp4", nil, addr)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.conn = conn
+	r.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(MulticastInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.announceAll(false)
+			}
+		}
+	}()
+	return nil
+}
+
+func (r *multicastRegistrar) Stop() error {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		r.announceAll(true)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.conn != nil {
+			_ = r.conn.Close()
+		}
+	})
+	return nil
+}
+
+// Register 注册或更新元数据，立即广播
+func (r *multicastRegistrar) Register(name string, _ any, metadata string) error {
+	r.mu.Lock()
+	r.services[name] = metadata
+	r.mu.Unlock()
+
+	return r.announce(name, metadata, false)
+}
+
+// Unregister 注销并广播下线
+func (r *multicastRegistrar) Unregister(name string) error {
+	r.mu.Lock()
+	metadata := r.services[name]
+	delete(r.services, name)
+	r.mu.Unlock()
+
+	return r.announce(name, metadata, true)
+}
+
+func (r *multicastRegistrar) announceAll(leave bool) {
+	r.mu.Lock()
+	services := make(map[string]string, len(r.services))
+	for name, metadata := range r.services {
+		services[name] = metadata
+	}
+	r.mu.Unlock()
+
+	for name, metadata := range services {
+		if err := r.announce(name, metadata, leave); err != nil {
+			log2.Get().Warn("[discovery] multicast announce failed", zap.String("service", name), zap.Error(err))
+		}
+	}
+}
+
+func (r *multicastRegistrar) announce(name, metadata string, leave bool) error {
+	data, err := json.Marshal(&announcement{
+		BasePath: r.config.BasePath,
+		Service:  name,
+		Address:  r.address,
+		Metadata: metadata,
+		Leave:    leave,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn == nil { // 未启动
+		return nil
+	}
+	_, err = r.conn.Write(data)
+	return err
+}
+
+// ----------------------------------------- 客户端 -----------------------------------------
+
+// multicastListener 监听多播地址，同一进程内按地址共用
+type multicastListener struct {
+	basePath string
+
+	mu       sync.Mutex
+	services map[string]map[string]*peerState // 服务对象名称-地址-状态
+	watchers map[string][]chan []*xclient.KVPair
+}
+
+type peerState struct {
+	metadata string
+	expire   time.Time
+}
+
+var (
+	listenersMu sync.Mutex
+	listeners   = make(map[string]*multicastListener)
+)
+
+// getListener 获取或创建监听
+func getListener(c Config) (*multicastListener, error) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+
+	key := c.BasePath + "|" + c.MulticastAddress
+	if l, ok := listeners[key]; ok {
+		return l, nil
+	}
+
+	addr, err := net.ResolveUDPAddr("udp4", c.MulticastAddress)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenMulticastUDP("udp4", nil, addr)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: listen multicast %s failed: %v", c.MulticastAddress, err)
+	}
+
+	l := &multicastListener{
+		basePath: c.BasePath,
+		services: make(map[string]map[string]*peerState),
+		watchers: make(map[string][]chan []*xclient.KVPair),
+	}
+	go l.read(conn)
+	go l.sweep()
+
+	listeners[key] = l
+	return l, nil
+}
+
+func (l *multicastListener) read(conn *net.UDPConn) {
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			log2.Get().Error("[discovery] multicast read failed", zap.Error(err))
+			return
+		}
+		var a announcement
+		if err = json.Unmarshal(buf[:n], &a); err != nil || a.BasePath != l.basePath {
+			continue // 其它项目或无效数据
+		}
+		if l.apply(&a) {
+			l.notify(a.Service)
+		}
+	}
+}
+
+// apply 更新节点状态，节点上下线或元数据变化时返回 true
+func (l *multicastListener) apply(a *announcement) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	peers, ok := l.services[a.Service]
+	if !ok {
+		peers = make(map[string]*peerState)
+		l.services[a.Service] = peers
+	}
+	old, exist := peers[a.Address]
+	if a.Leave {
+		delete(peers, a.Address)
+		return exist
+	}
+	peers[a.Address] = &peerState{metadata: a.Metadata, expire: time.Now().Add(MulticastTTL)}
+	return !exist || old.metadata != a.Metadata
+}
+
+// sweep 移除超时的节点
+func (l *multicastListener) sweep() {
+	ticker := time.NewTicker(MulticastInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		changed := make([]string, 0)
+
+		l.mu.Lock()
+		for service, peers := range l.services {
+			for address, state := range peers {
+				if now.After(state.expire) {
+					delete(peers, address)
+					changed = append(changed, service)
+				}
+			}
+		}
+		l.mu.Unlock()
+
+		for _, service := range changed {
+			l.notify(service)
+		}
+	}
+}
+
+func (l *multicastListener) pairs(servicePath string) []*xclient.KVPair {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]*xclient.KVPair, 0, len(l.services[servicePath]))
+	for address, state := range l.services[servicePath] {
+		out = append(out, &xclient.KVPair{Key: address, Value: state.metadata})
+	}
+	return out
+}
+
+// notify 通知监听者，监听者未及时读取时丢弃旧的通知
+func (l *multicastListener) notify(servicePath string) {
+	pairs := l.pairs(servicePath)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, ch := range l.watchers[servicePath] {
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- pairs:
+		default:
+		}
+	}
+}
+
+// multicastDiscovery 单个服务对象的多播服务发现
+type multicastDiscovery struct {
+	config      Config
+	listener    *multicastListener
+	servicePath string
+	filter      xclient.ServiceDiscoveryFilter
+}
+
+func newMulticastDiscovery(c Config, servicePath string) (xclient.ServiceDiscovery, error) {
+	l, err := getListener(c)
+	if err != nil {
+		return nil, err
+	}
+	return &multicastDiscovery{config: c, listener: l, servicePath: servicePath}, nil
+}
+
+func (d *multicastDiscovery) GetServices() []*xclient.KVPair {
+	pairs := d.listener.pairs(d.servicePath)
+	if d.filter == nil {
+		return pairs
+	}
+	out := pairs[:0]
+	for _, p := range pairs {
+		if d.filter(p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (d *multicastDiscovery) WatchService() chan []*xclient.KVPair {
+	ch := make(chan []*xclient.KVPair, 1)
+	d.listener.mu.Lock()
+	d.listener.watchers[d.servicePath] = append(d.listener.watchers[d.servicePath], ch)
+	d.listener.mu.Unlock()
+	return ch
+}
+
+func (d *multicastDiscovery) RemoveWatcher(ch chan []*xclient.KVPair) {
+	d.listener.mu.Lock()
+	defer d.listener.mu.Unlock()
+
+	l := d.listener.watchers[d.servicePath]
+	for i := range l {
+		if l[i] == ch {
+			d.listener.watchers[d.servicePath] = append(l[:i], l[i+1:]...)
+			return
+		}
+	}
+}
+
+func (d *multicastDiscovery) Clone(servicePath string) (xclient.ServiceDiscovery, error) {
+	return newMulticastDiscovery(d.config, servicePath)
+}
+
+func (d *multicastDiscovery) SetFilter(filter xclient.ServiceDiscoveryFilter) {
+	d.filter = filter
+}
+
+// Close 监听在进程内共用，不关闭
+func (d *multicastDiscovery) Close() {}
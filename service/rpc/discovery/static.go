@@ -0,0 +1,29 @@
+package discovery
+
+import (
+	"fmt"
+
+	xclient "github.com/smallnest/rpcx/client"
+)
+
+// staticRegistrar 固定节点列表不需要注册
+type staticRegistrar struct{}
+
+func (staticRegistrar) Start() error                       { return nil }
+func (staticRegistrar) Stop() error                        { return nil }
+func (staticRegistrar) Register(string, any, string) error { return nil }
+func (staticRegistrar) Unregister(string) error            { return nil }
+
+// newStaticDiscovery 配置文件中该服务对象的节点
+func newStaticDiscovery(c Config, servicePath string) (xclient.ServiceDiscovery, error) {
+	pairs := make([]*xclient.KVPair, 0)
+	for _, peer := range c.Peers {
+		if peer.Service == servicePath {
+			pairs = append(pairs, &xclient.KVPair{Key: peer.Address, Value: peer.Metadata})
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("discovery: no static peer for %s", servicePath)
+	}
+	return xclient.NewMultipleServersDiscovery(pairs)
+}
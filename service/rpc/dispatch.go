@@ -5,11 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"gameServer/common/errorCode"
+	"gameServer/pkg/logger/log2"
 	"gameServer/service/common"
+	"time"
+
+	"go.uber.org/zap"
 )
 
 var sum = 0
 
+// DefaultDispatchTimeout 请求未携带期限时，节点处理单个请求的最长时间
+const DefaultDispatchTimeout = 10 * time.Second
+
 // Dispatch 网关发生的消息派遣
 func (f *Forward) Dispatch(ctx context.Context, req *common.RpcMessage, resp *common.Resp) error {
 	sum++
@@ -40,11 +47,43 @@ func (f *Forward) Dispatch(ctx context.Context, req *common.RpcMessage, resp *co
 		return fmt.Errorf("protocol not found: %d", req.Data.Head.Protocol)
 	}
 
-	err := protocolMethod.Call(ctx, req.Player, req, resp)
+	// 请求期限由网关写入 rpcx 元数据(share.ServerTimeout)，rpcx 服务端已据此设置 ctx
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultDispatchTimeout)
+		defer cancel()
+	}
+	if ctx.Err() != nil { // 到达时已经过期，网关已不再等待
+		resp.Code = errorCode.ErrorCode_Timeout
+		return nil
+	}
+
+	// 处理函数在当前协程中执行完，不在超时后放弃：被放弃的处理函数仍会修改数据，
+	// 返回超时会让客户端把已经完成的写入当作失败。处理函数通过 ctx 在开始写入前检查期限
+	err := f.call(ctx, protocolMethod, req, resp)
+	if err == nil && resp.Code != errorCode.ErrorCode_Success && ctx.Err() != nil {
+		resp.Code = errorCode.ErrorCode_Timeout // 失败由超时引起
+	}
 	// 计算耗时
 	//end := time.Now().UnixMilli()
 	//fmt.Printf("rpc Dispatch:函数运行时间: %v\n", end-start)
 	//fmt.Printf("rpc Dispatch:函数运行时间: %d\n", sum)
-
+	if ctx.Err() != nil {
+		log2.Get().Warn("[rpc.Dispatch] finished after deadline",
+			zap.String("method", protocolMethod.Name()),
+			zap.Uint64("userId", req.Player.UserId),
+			zap.Uint16("code", uint16(resp.Code)),
+		)
+	}
 	return err
 }
+
+// call 执行处理函数，panic 转为错误
+func (f *Forward) call(ctx context.Context, protocolMethod *ProtocolMethod, req *common.RpcMessage, resp *common.Resp) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("protocol: %s panic: %v", protocolMethod.Name(), r)
+		}
+	}()
+	return protocolMethod.Call(ctx, req.Player, req, resp)
+}
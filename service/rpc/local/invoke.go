@@ -0,0 +1,76 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	rpcCodec "gameServer/service/rpc/codec"
+
+	xclient "github.com/smallnest/rpcx/client"
+	"github.com/smallnest/rpcx/codec"
+	"github.com/smallnest/rpcx/share"
+)
+
+var (
+	typeOfContext = reflect.TypeOf((*context.Context)(nil)).Elem()
+	typeOfError   = reflect.TypeOf((*error)(nil)).Elem()
+
+	// ErrMethodNotFound 服务对象没有该方法，或签名不是 func(ctx, *Args, *Reply) error
+	ErrMethodNotFound = errors.New("local rpc: method not found")
+)
+
+// Invoke 直接调用服务对象的方法，语义与 rpcx 一致
+//
+// 参数和结果经过与远程调用相同的编解码复制，调用双方不共享内存；
+// 服务端只继承调用方的期限，调用方返回后不会取消服务端的处理；
+// 方法返回的错误包装为 ServiceError，与远程调用一样不计入熔断
+func Invoke(ctx context.Context, rcvr any, serviceMethod string, args any, reply any) error {
+	method := reflect.ValueOf(rcvr).MethodByName(serviceMethod)
+	if !method.IsValid() || !validMethod(method.Type()) {
+		return fmt.Errorf("%w: %T.%s", ErrMethodNotFound, rcvr, serviceMethod)
+	}
+	c := share.Codecs[rpcCodec.SerializeEnvelope]
+
+	argv := reflect.New(method.Type().In(1).Elem())
+	if err := copyValue(c, args, argv.Interface()); err != nil {
+		return fmt.Errorf("local rpc: encode args failed: %v", err)
+	}
+	replyv := reflect.New(method.Type().In(2).Elem())
+
+	serverCtx := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		serverCtx, cancel = context.WithDeadline(serverCtx, deadline)
+		defer cancel()
+	}
+
+	out := method.Call([]reflect.Value{reflect.ValueOf(serverCtx), argv, replyv})
+	if err, _ := out[0].Interface().(error); err != nil {
+		return xclient.NewServiceError(err.Error())
+	}
+	if reply == nil {
+		return nil
+	}
+	if err := copyValue(c, replyv.Interface(), reply); err != nil {
+		return fmt.Errorf("local rpc: decode reply failed: %v", err)
+	}
+	return nil
+}
+
+// validMethod func(context.Context, *Args, *Reply) error
+func validMethod(t reflect.Type) bool {
+	return t.NumIn() == 3 && t.NumOut() == 1 &&
+		t.In(0) == typeOfContext &&
+		t.In(1).Kind() == reflect.Ptr && t.In(2).Kind() == reflect.Ptr &&
+		t.Out(0) == typeOfError
+}
+
+func copyValue(c codec.Codec, src, dst any) error {
+	data, err := c.Encode(src)
+	if err != nil {
+		return err
+	}
+	return c.Decode(data, dst)
+}
@@ -0,0 +1,185 @@
+package local
+
+import (
+	"strings"
+	"sync"
+
+	xclient "github.com/smallnest/rpcx/client"
+)
+
+// AddressPrefix 进程内服务地址前缀，例如 local@gate-1
+const AddressPrefix = "local@"
+
+// Registry 进程内服务注册表，代替 etcd，单进程开发模式和集成测试使用
+type Registry struct {
+	mu       sync.RWMutex
+	services map[string]map[string]*entry // 服务对象名称-地址-服务
+	watchers map[string][]chan []*xclient.KVPair
+}
+
+type entry struct {
+	rcvr     any
+	metadata string
+}
+
+// NewRegistry 创建注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		services: make(map[string]map[string]*entry),
+		watchers: make(map[string][]chan []*xclient.KVPair),
+	}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default 进程默认注册表，同一进程内的网关和节点共用
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register 注册或更新服务，地址相同时覆盖元数据
+//
+//   - servicePath: 服务对象名称，例如 Forward, Gate
+//   - address: 服务地址，例如 local@room-1
+func (r *Registry) Register(servicePath, address string, rcvr any, metadata string) {
+	r.mu.Lock()
+	m, ok := r.services[servicePath]
+	if !ok {
+		m = make(map[string]*entry)
+		r.services[servicePath] = m
+	}
+	m[address] = &entry{rcvr: rcvr, metadata: metadata}
+	r.mu.Unlock()
+
+	r.notify(servicePath)
+}
+
+// Unregister 注销服务
+func (r *Registry) Unregister(servicePath, address string) {
+	r.mu.Lock()
+	delete(r.services[servicePath], address)
+	r.mu.Unlock()
+
+	r.notify(servicePath)
+}
+
+// Lookup 按地址查找服务对象
+func (r *Registry) Lookup(servicePath, address string) (any, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.services[servicePath][address]
+	if !ok {
+		return nil, false
+	}
+	return e.rcvr, true
+}
+
+// Servers 服务的所有地址，地址-元数据，格式与 selector 的 UpdateServer 参数一致
+func (r *Registry) Servers(servicePath string) map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]string, len(r.services[servicePath]))
+	for address, e := range r.services[servicePath] {
+		out[address] = e.metadata
+	}
+	return out
+}
+
+// Discovery 服务发现，实现 rpcx 的 ServiceDiscovery
+func (r *Registry) Discovery(servicePath string) xclient.ServiceDiscovery {
+	return &discovery{registry: r, servicePath: servicePath}
+}
+
+// IsLocal 是否为进程内地址
+func IsLocal(address string) bool {
+	return strings.HasPrefix(address, AddressPrefix)
+}
+
+func (r *Registry) pairs(servicePath string) []*xclient.KVPair {
+	servers := r.Servers(servicePath)
+	out := make([]*xclient.KVPair, 0, len(servers))
+	for address, metadata := range servers {
+		out = append(out, &xclient.KVPair{Key: address, Value: metadata})
+	}
+	return out
+}
+
+// notify 通知监听者，监听者未及时读取时丢弃旧的通知
+func (r *Registry) notify(servicePath string) {
+	pairs := r.pairs(servicePath)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, ch := range r.watchers[servicePath] {
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- pairs:
+		default:
+		}
+	}
+}
+
+func (r *Registry) watch(servicePath string) chan []*xclient.KVPair {
+	ch := make(chan []*xclient.KVPair, 1)
+	r.mu.Lock()
+	r.watchers[servicePath] = append(r.watchers[servicePath], ch)
+	r.mu.Unlock()
+	return ch
+}
+
+func (r *Registry) removeWatcher(servicePath string, ch chan []*xclient.KVPair) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l := r.watchers[servicePath]
+	for i := range l {
+		if l[i] == ch {
+			r.watchers[servicePath] = append(l[:i], l[i+1:]...)
+			return
+		}
+	}
+}
+
+// discovery 单个服务对象的服务发现
+type discovery struct {
+	registry    *Registry
+	servicePath string
+	filter      xclient.ServiceDiscoveryFilter
+}
+
+func (d *discovery) GetServices() []*xclient.KVPair {
+	pairs := d.registry.pairs(d.servicePath)
+	if d.filter == nil {
+		return pairs
+	}
+	out := pairs[:0]
+	for _, p := range pairs {
+		if d.filter(p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (d *discovery) WatchService() chan []*xclient.KVPair {
+	return d.registry.watch(d.servicePath)
+}
+
+func (d *discovery) RemoveWatcher(ch chan []*xclient.KVPair) {
+	d.registry.removeWatcher(d.servicePath, ch)
+}
+
+func (d *discovery) Clone(servicePath string) (xclient.ServiceDiscovery, error) {
+	return d.registry.Discovery(servicePath), nil
+}
+
+func (d *discovery) SetFilter(filter xclient.ServiceDiscoveryFilter) {
+	d.filter = filter
+}
+
+func (d *discovery) Close() {}
@@ -0,0 +1,173 @@
+package test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"gameServer/common/errorCode"
+	"gameServer/pkg/logger/log2"
+	"gameServer/protobuf/pbGo"
+	"gameServer/service/common"
+	"gameServer/service/rpc"
+	"gameServer/service/rpc/client"
+	"gameServer/service/rpc/client/selector"
+	"gameServer/service/rpc/local"
+	"gameServer/service/rpc/server"
+
+	"github.com/smallnest/rpcx/share"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	gateId = 1
+	roomId = 1000
+)
+
+func TestMain(m *testing.M) {
+	log2.Init(log2.Config{Level: zapcore.ErrorLevel, IsDocker: true})
+	os.Exit(m.Run())
+}
+
+// Gate 模拟网关，记录节点推送
+type Gate struct {
+	received chan *common.RpcMessage
+}
+
+func (g *Gate) Receive(_ context.Context, req *common.RpcMessage, _ *common.Resp) error {
+	g.received <- req
+	return nil
+}
+
+// Room 节点处理器，回包的同时推送给玩家所在网关
+type Room struct {
+	gateClient rpc.ClientInterface
+}
+
+func (r *Room) TestHandler(ctx context.Context, player *common.Player, req *pbGo.TestRpcRep, resp *pbGo.TestRpcResp) *common.ErrorInfo {
+	resp.Id = req.Id + 1
+	resp.Name = req.Name
+
+	body, err := proto.Marshal(resp)
+	if err != nil {
+		return common.Error(errorCode.ErrorCode_PushFailed)
+	}
+	push := common.RpcMessage{
+		Data:   common.NewMessage(0, 0, 0, 1000, body),
+		Player: player,
+	}
+	ctx = context.WithValue(ctx, share.ResMetaDataKey, map[string]string{
+		"id":    strconv.Itoa(gateId),
+		"group": common.GateGroup,
+	})
+	if err = r.gateClient.Call(ctx, "Receive", push, nil); err != nil {
+		return common.Error(errorCode.ErrorCode_PushFailed)
+	}
+	return nil
+}
+
+func startServer(t *testing.T, registry *local.Registry, config *server.ServerConfig, rcvr any) {
+	s, err := server.NewLocalServer(config, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Register(rcvr); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Stop() })
+}
+
+func newClient(t *testing.T, registry *local.Registry, servicePath string) rpc.ClientInterface {
+	c, err := client.NewLocalClient(&client.ClientConfig{
+		ServicePath: servicePath,
+		Selector:    selector.NewDefaultSelector(nil),
+	}, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+// 网关 Dispatch 到节点，节点处理时通过 Receive 推送回网关
+func TestDispatchReceive(t *testing.T) {
+	registry := local.NewRegistry()
+
+	gate := &Gate{received: make(chan *common.RpcMessage, 1)}
+	startServer(t, registry, &server.ServerConfig{ID: gateId, Name: "gate-1", Group: common.GateGroup}, gate)
+
+	room := &Room{gateClient: newClient(t, registry, "Gate")}
+	f := rpc.NewForward()
+	if err := f.AddModules([]interface{}{room}); err != nil {
+		t.Fatal(err)
+	}
+	startServer(t, registry, &server.ServerConfig{ID: roomId, Name: "room-1", Group: "room"}, f)
+
+	body, err := proto.Marshal(&pbGo.TestRpcRep{Id: 41, Name: "local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := common.RpcMessage{
+		Data:   common.NewMessage(0, 1, 0, 1000, body),
+		Player: &common.Player{UserId: 10086, ServerIds: []uint32{gateId}, GateId: gateId},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ctx = context.WithValue(ctx, share.ResMetaDataKey, map[string]string{
+		"id":     "0",
+		"group":  "room",
+		"userId": "10086",
+	})
+
+	resp := &common.Resp{}
+	if err = newClient(t, registry, "Forward").Call(ctx, "Dispatch", req, resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Code != errorCode.ErrorCode_Success {
+		t.Fatalf("code = %d", resp.Code)
+	}
+	got := &pbGo.TestRpcResp{}
+	if err = proto.Unmarshal(resp.Body, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Id != 42 || got.Name != "local" {
+		t.Fatalf("resp = %v", got)
+	}
+
+	select {
+	case push := <-gate.received:
+		if push.Player.UserId != 10086 || push.Data.Head.Protocol != 1000 {
+			t.Fatalf("push = %+v", push)
+		}
+	default:
+		t.Fatal("gate did not receive the push")
+	}
+}
+
+// 节点注销后网关不再选择
+func TestUnregister(t *testing.T) {
+	registry := local.NewRegistry()
+	s, err := server.NewLocalServer(&server.ServerConfig{ID: roomId, Name: "room-1", Group: "room"}, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := rpc.NewForward()
+	if err = f.AddModules([]interface{}{&Room{}}); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Register(f)
+	_ = s.Start()
+	if len(registry.Servers("Forward")) != 1 {
+		t.Fatal("node should be registered after Start")
+	}
+	_ = s.Stop()
+	if len(registry.Servers("Forward")) != 0 {
+		t.Fatal("node should be removed after Stop")
+	}
+}
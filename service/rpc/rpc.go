@@ -34,6 +34,9 @@ type ClientInterface interface {
 	// Go 异步调用
 	Go(ctx context.Context, serviceMethod string, args any, reply any, done chan *xclient.Call) (*xclient.Call, error)
 
+	// Broadcast 调用所有服务节点，不经过选择器
+	Broadcast(ctx context.Context, serviceMethod string, args any, reply any) error
+
 	// Close 关闭
 	Close() error
 
@@ -0,0 +1,40 @@
+//go:build !windows
+
+package server
+
+import (
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	cpuMu       sync.Mutex
+	lastCPUTime time.Duration
+	lastCPUAt   time.Time
+)
+
+// cpuPercent 距上次调用期间进程的 cpu 占用，按核数归一到 0~100
+func cpuPercent() int64 {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0
+	}
+	used := time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+	now := time.Now()
+
+	cpuMu.Lock()
+	defer cpuMu.Unlock()
+
+	var percent int64
+	if !lastCPUAt.IsZero() {
+		elapsed := now.Sub(lastCPUAt) * time.Duration(runtime.NumCPU())
+		if elapsed > 0 {
+			percent = int64((used - lastCPUTime) * 100 / elapsed)
+		}
+	}
+	lastCPUTime = used
+	lastCPUAt = now
+	return percent
+}
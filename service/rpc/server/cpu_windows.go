@@ -0,0 +1,6 @@
+package server
+
+// cpuPercent windows 下不统计
+func cpuPercent() int64 {
+	return 0
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// loads 节点负载上报项，名称-取值函数，写入 etcd 元数据供选择器使用
+var loads sync.Map
+
+// extras 节点固定元数据，名称-值，例如 stream 地址
+var extras sync.Map
+
+// SetMetadata 设置固定元数据，在注册服务前调用
+func SetMetadata(name, value string) {
+	extras.Store(name, value)
+}
+
+// SetLoad 设置负载上报项，如 rooms、sessions
+func SetLoad(name string, fn func() int64) {
+	loads.Store(name, fn)
+}
+
+// loadMetadata 固定元数据和当前负载，按名称排序保证元数据稳定
+func loadMetadata() []string {
+	out := make([]string, 0, 4)
+	extras.Range(func(key, value any) bool {
+		out = append(out, fmt.Sprintf("%s=%s", key.(string), value.(string)))
+		return true
+	})
+	loads.Range(func(key, value any) bool {
+		out = append(out, fmt.Sprintf("%s=%d", key.(string), value.(func() int64)()))
+		return true
+	})
+	sort.Strings(out)
+	return append(out, fmt.Sprintf("cpu=%d", cpuPercent()))
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"fmt"
+	"gameServer/service/rpc"
+	"gameServer/service/rpc/local"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LocalServer 进程内服务端，注册到进程内注册表，不监听端口也不依赖 etcd
+type LocalServer struct {
+	config   *ServerConfig
+	registry *local.Registry
+	address  string
+
+	started atomic.Bool
+	closed  atomic.Bool
+	stop    chan struct{}
+
+	// services 已注册的服务，服务名-服务对象
+	services   map[string]any
+	servicesMu sync.Mutex
+}
+
+// New 按配置创建服务端，开启 rpclocal 时创建进程内服务端
+func New(config *ServerConfig) (rpc.ServerInterface, error) {
+	if config.Local {
+		return NewLocalServer(config, local.Default())
+	}
+	return NewServer(config)
+}
+
+// NewLocalServer 创建进程内服务端
+//
+//   - registry: 注册表，为 nil 时使用进程默认注册表
+func NewLocalServer(config *ServerConfig, registry *local.Registry) (rpc.ServerInterface, error) {
+	if registry == nil {
+		registry = local.Default()
+	}
+	return &LocalServer{
+		config:   config,
+		registry: registry,
+		address:  local.AddressPrefix + config.Name,
+		stop:     make(chan struct{}),
+		services: make(map[string]any),
+	}, nil
+}
+
+// Output 输出当前信息
+func (s *LocalServer) Output() string {
+	return fmt.Sprintf("%s(id: %d)[version: %d][listen: %s]", s.config.Name, s.config.ID, s.config.Version, s.address)
+}
+
+// Start 启动服务，已注册的服务开始对外可见
+func (s *LocalServer) Start() error {
+	if s.closed.Load() {
+		return fmt.Errorf("server: %s already closed", s.Output())
+	}
+	if !s.started.CompareAndSwap(false, true) {
+		return nil
+	}
+	s.publish()
+
+	go s.refreshMetadata()
+	return nil
+}
+
+// Stop 停止服务，从注册表注销
+func (s *LocalServer) Stop() error {
+	if !s.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	close(s.stop)
+
+	s.servicesMu.Lock()
+	defer s.servicesMu.Unlock()
+	for name := range s.services {
+		s.registry.Unregister(name, s.address)
+	}
+	return nil
+}
+
+// Register 注册服务，启动后立即可见
+//
+// - rcvr 函数相关，例如 new(Forward)
+func (s *LocalServer) Register(rcvr any) error {
+	servicePath := reflect.Indirect(reflect.ValueOf(rcvr)).Type().Name()
+
+	s.servicesMu.Lock()
+	s.services[servicePath] = rcvr
+	s.servicesMu.Unlock()
+
+	if s.started.Load() {
+		s.registry.Register(servicePath, s.address, rcvr, buildMetadata(s.config))
+	}
+	return nil
+}
+
+// publish 把所有服务和最新元数据写入注册表
+func (s *LocalServer) publish() {
+	metadata := buildMetadata(s.config)
+
+	s.servicesMu.Lock()
+	defer s.servicesMu.Unlock()
+	for name, rcvr := range s.services {
+		s.registry.Register(name, s.address, rcvr, metadata)
+	}
+}
+
+// refreshMetadata 定时刷新负载
+func (s *LocalServer) refreshMetadata() {
+	if s.config.UpdateInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.config.UpdateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.publish()
+		}
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"gameServer/pkg/config"
+	"gameServer/service/rpc/discovery"
 	"time"
 )
 
@@ -13,29 +14,40 @@ type ServerConfig struct {
 	Name string
 	// 服务器版本
 	Version uint32
+	// 容量权重，选择器按权重分配，默认 100
+	Weight int
 
-	// 向 etcd 更新信息间隔
+	// 向注册中心更新信息间隔
 	UpdateInterval time.Duration
-	// etcd 服务器地址列表
-	EtcdEndpoints []string
-
-	// etcd 服务注册基础路径，相当于缓存前缀，避免不同项目使用同一个 etcd 造成混乱，例如 MODOU_LDL
-	BasePath string
+	// 服务发现配置，etcd、固定节点列表或局域网多播
+	Discovery discovery.Config
+	// 监听地址，为空时使用本机 ip 和随机端口，固定节点列表时必须配置
+	Address string
 	// 服务名称，例如 gate, game, battle。例如组成 MODOU_LDL/gate
 	ServiceName string
+	// 服务组名，网关按组路由，例如 gate, room, home
+	Group string
+	// Local 使用进程内传输，见 local.Registry
+	Local bool
 }
 
 // BuildServerConfig 从服务配置表中创建
 func BuildServerConfig() *ServerConfig {
-	c := config.Get()
+	return BuildServerConfigFrom(config.Get())
+}
 
+// BuildServerConfigFrom 从指定的服务配置创建，同一进程运行多个服务时使用
+func BuildServerConfigFrom(c *config.Config) *ServerConfig {
 	return &ServerConfig{
 		ID:             c.NodeID(),
 		Name:           c.NodeName(),
 		Version:        c.NodeVersion(),
+		Weight:         c.NodeWeight(),
 		UpdateInterval: 10 * time.Second,
-		EtcdEndpoints:  c.EtcdAddress(),
-		BasePath:       c.EtcdPrefix(),
+		Discovery:      discovery.BuildConfig(),
+		Address:        c.NodeRPCAddress(),
 		ServiceName:    c.ServiceName(),
+		Group:          c.ServiceGroup(),
+		Local:          c.RPCLocal(),
 	}
 }
@@ -5,15 +5,17 @@ import (
 	"gameServer/pkg/logger/log2"
 	utils2 "gameServer/pkg/utils"
 	"gameServer/service/rpc"
+	_ "gameServer/service/rpc/codec" // 注册服务间 rpc 的序列化类型
+	"gameServer/service/rpc/discovery"
 	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	"go.uber.org/zap"
 
 	"time"
 
-	"github.com/rpcxio/rpcx-etcd/serverplugin"
 	rpcxServer "github.com/smallnest/rpcx/server"
 )
 
@@ -23,17 +25,22 @@ type Server struct {
 	config *ServerConfig
 	// rpcx 服务器
 	server *rpcxServer.Server
-	// etcd 服务注册插件
-	registry *serverplugin.EtcdV3RegisterPlugin
+	// 服务注册插件
+	registry discovery.Registrar
 	// 添加关闭标志
 	closed atomic.Bool
+
+	// services 已注册的服务，服务名-服务对象，定时刷新元数据时使用
+	services   map[string]any
+	servicesMu sync.Mutex
 }
 
 // 创建服务端
 func NewServer(config *ServerConfig) (rpc.ServerInterface, error) {
 	s := &Server{
-		config: config,
-		server: rpcxServer.NewServer(),
+		config:   config,
+		server:   rpcxServer.NewServer(),
+		services: make(map[string]any),
 	}
 	return s, nil
 }
@@ -74,24 +81,23 @@ func (s *Server) Start() error {
 		return fmt.Errorf("server: %s get address failed", s.Output())
 	}
 
-	// 向etcd注册
+	// 向注册中心注册
 	address := fmt.Sprintf("%s@%s", addr.Network(), addr.String())
-	log2.Get().Info("etcd Register==", zap.String("address=", address), zap.Strings("etcd =", config.EtcdEndpoints))
+	log2.Get().Info("discovery Register==", zap.String("address=", address), zap.String("type", config.Discovery.Kind), zap.Strings("etcd =", config.Discovery.EtcdEndpoints))
 
-	r := &serverplugin.EtcdV3RegisterPlugin{
-		BasePath:       s.publicServicePath(), //根目录， eg：node/Forward,xxx/Forward
-		ServiceAddress: address,
-		EtcdServers:    config.EtcdEndpoints,  //往etcd注册地址
-		UpdateInterval: config.UpdateInterval, //信息更新间隔
+	r, err := discovery.NewRegistrar(config.Discovery, address, config.UpdateInterval)
+	if err != nil {
+		return fmt.Errorf("server: %s registry failed: %v", s.Output(), err)
 	}
-
-	if err := r.Start(); err != nil {
-		return fmt.Errorf("server: %s etcd registry failed: %v", s.Output(), err)
+	if err = r.Start(); err != nil {
+		return fmt.Errorf("server: %s registry failed: %v", s.Output(), err)
 	}
 
 	s.server.Plugins.Add(r)
 	s.registry = r
 
+	go s.refreshMetadata()
+
 	return nil
 }
 
@@ -110,7 +116,7 @@ func (s *Server) Stop() error {
 	// 注销服务
 	if s.registry != nil {
 		if err := s.registry.Stop(); err != nil {
-			errs = append(errs, fmt.Errorf("etcd registry stop failed: %v", err))
+			errs = append(errs, fmt.Errorf("registry stop failed: %v", err))
 		}
 	}
 
@@ -137,6 +143,10 @@ func (s *Server) Register(rcvr any) error {
 	// node: node/Forward
 	servicePath := reflect.Indirect(reflect.ValueOf(rcvr)).Type().Name()
 
+	s.servicesMu.Lock()
+	s.services[servicePath] = rcvr
+	s.servicesMu.Unlock()
+
 	return s.server.RegisterName(servicePath, rcvr, metadata)
 }
 
@@ -144,9 +154,10 @@ func (s *Server) Register(rcvr any) error {
 //
 // 使用系统分配的端口
 func (s *Server) serve() error {
-	ip := utils2.LocalIP()
-
-	address := fmt.Sprintf("%s:%d", ip, 0)
+	address := s.config.Address
+	if address == "" {
+		address = fmt.Sprintf("%s:%d", utils2.LocalIP(), 0)
+	}
 	// 随机获取可用的ip和端口
 	//address := ":0" // 或 "0.0.0.0:0"
 
@@ -159,19 +170,43 @@ func (s *Server) serve() error {
 
 // buildMetadata 编写服务器元数据
 func (s *Server) buildMetadata() string {
-	serverId := s.config.ID
-	gouldId := utils2.GetGroupIdByServerId(serverId) // (1~999):1 （1000~1999):2 (2000~2999):3
-	metadata := strings.Join([]string{
-		fmt.Sprintf("id=%d", serverId),
-		fmt.Sprintf("version=%d", s.config.Version),
-		fmt.Sprintf("groupId=%d", gouldId),
-	}, "&")
-
-	return metadata
+	return buildMetadata(s.config)
 }
 
-func (s *Server) publicServicePath() string {
-	return fmt.Sprintf("%s", s.config.BasePath)
+// buildMetadata 节点元数据，rpcx 和进程内服务共用
+func buildMetadata(config *ServerConfig) string {
+	fields := []string{
+		fmt.Sprintf("id=%d", config.ID),
+		fmt.Sprintf("version=%d", config.Version),
+		fmt.Sprintf("group=%s", config.Group),
+		fmt.Sprintf("weight=%d", config.Weight),
+	}
+	// 负载
+	fields = append(fields, loadMetadata()...)
 
-	//return fmt.Sprintf("%s/%s", s.config.BasePath, s.config.ServiceName)
+	return strings.Join(fields, "&")
+}
+
+// refreshMetadata 定时把最新负载写入注册中心
+func (s *Server) refreshMetadata() {
+	if s.config.UpdateInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.config.UpdateInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if s.closed.Load() {
+			return
+		}
+		metadata := s.buildMetadata()
+
+		s.servicesMu.Lock()
+		for name, rcvr := range s.services {
+			if err := s.registry.Register(name, rcvr, metadata); err != nil {
+				log2.Get().Warn("[rpc.server] refresh metadata failed", zap.String("service", name), zap.Error(err))
+			}
+		}
+		s.servicesMu.Unlock()
+	}
 }
@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"context"
+	"gameServer/service/rpc"
+	"gameServer/service/rpc/client"
+	"strconv"
+	"time"
+
+	xclient "github.com/smallnest/rpcx/client"
+	"github.com/smallnest/rpcx/share"
+)
+
+// Client 优先通过流链接调用的客户端，目标节点没有链接时使用 rpcx
+//
+// 目标节点 id 取自 ctx 元数据 share.ResMetaDataKey 的 id，与选择器一致，调用方无需修改
+type Client struct {
+	rpc.ClientInterface // 未建立链接或未指定节点时使用
+
+	hub *Hub
+	// resolve 目标节点的流地址，没有链接时据此连接；为 nil 时只使用对端连入的链接
+	resolve func(id uint32) string
+	// breakers 与 rpcx 共用的熔断器，按节点的 rpc 地址统计，inner 不支持时为 nil
+	breakers *client.Breakers
+	// address 节点 id 对应的 rpc 地址
+	address func(id uint32) string
+}
+
+// NewClient 创建
+//
+//   - inner: rpcx 客户端
+//   - resolve: 节点 id 对应的流地址，网关传入选择器的 StreamAddress，节点传 nil
+func NewClient(inner rpc.ClientInterface, hub *Hub, resolve func(id uint32) string) *Client {
+	c := &Client{ClientInterface: inner, hub: hub, resolve: resolve}
+	b, ok := inner.(interface{ Breakers() *client.Breakers })
+	if !ok {
+		return c
+	}
+	if s, ok := inner.GetSelector().(interface{ Address(id uint32) string }); ok {
+		c.breakers = b.Breakers()
+		c.address = s.Address
+	}
+	return c
+}
+
+// Call 同步调用，目标节点有链接时通过链接发送
+//
+// 链接断开时返回 ErrLinkClosed 而不是重发，请求可能已被处理
+func (c *Client) Call(ctx context.Context, serviceMethod string, args any, reply any) error {
+	if id := targetId(ctx); id > 0 {
+		if l := c.hub.Get(id); l != nil {
+			return c.callLink(ctx, l, serviceMethod, args, reply)
+		}
+		if c.resolve != nil {
+			if address := c.resolve(id); address != "" {
+				c.hub.Dial(id, address) // 异步连接，本次仍走 rpcx
+			}
+		}
+	}
+	return c.ClientInterface.Call(ctx, serviceMethod, args, reply)
+}
+
+// callLink 通过链接调用，与 rpcx 一样经过熔断：熔断时直接失败，结果计入统计
+func (c *Client) callLink(ctx context.Context, l *Link, serviceMethod string, args any, reply any) error {
+	if c.breakers == nil {
+		return l.Call(ctx, serviceMethod, args, reply)
+	}
+	address := c.address(l.RemoteId())
+	if address != "" && !c.breakers.Acquire(address) {
+		return xclient.ErrXClientNoServer
+	}
+	start := time.Now()
+	err := l.Call(ctx, serviceMethod, args, reply)
+	c.breakers.Record(address, err, time.Since(start))
+	return err
+}
+
+// Hub 流链接
+func (c *Client) Hub() *Hub {
+	return c.hub
+}
+
+func targetId(ctx context.Context) uint32 {
+	m, ok := ctx.Value(share.ResMetaDataKey).(map[string]string)
+	if !ok {
+		return 0
+	}
+	id, _ := strconv.ParseUint(m["id"], 10, 32)
+	return uint32(id)
+}
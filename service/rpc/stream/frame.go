@@ -0,0 +1,118 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// 帧类型
+const (
+	frameHello    uint8 = iota + 1 // 连接方发送的第一帧，携带本节点 id
+	frameRequest                   // 请求，对端处理后返回 frameResponse
+	frameResponse                  // 响应，id 与请求一致
+	framePing                      // 心跳
+	framePong                      // 心跳应答
+)
+
+// MaxFrameSize 单帧最大长度
+const MaxFrameSize = 4 << 20
+
+var errFrameTooLarge = errors.New("stream: frame too large")
+
+// frame 链接上传输的帧
+//
+// 格式: len(4) | type(1) | id(4) | 类型相关头部 | payload
+//   - request: timeout 毫秒(4) | methodLen(1) | method
+//   - response: errLen(2) | err
+//   - hello: serverId(4)
+type frame struct {
+	typ     uint8
+	id      uint32
+	timeout uint32 // 请求剩余时间，毫秒，0 表示不限制
+	method  string
+	err     string
+	payload []byte
+}
+
+func writeFrame(w *bufio.Writer, f *frame) error {
+	size := 1 + 4 + len(f.payload)
+	switch f.typ {
+	case frameRequest:
+		size += 4 + 1 + len(f.method)
+	case frameResponse:
+		size += 2 + len(f.err)
+	}
+	if size > MaxFrameSize {
+		return errFrameTooLarge
+	}
+
+	var head [4 + 1 + 4 + 4 + 1]byte
+	binary.BigEndian.PutUint32(head[0:], uint32(size))
+	head[4] = f.typ
+	binary.BigEndian.PutUint32(head[5:], f.id)
+	n := 9
+	switch f.typ {
+	case frameRequest:
+		binary.BigEndian.PutUint32(head[n:], f.timeout)
+		head[n+4] = uint8(len(f.method))
+		n += 5
+	case frameResponse:
+		binary.BigEndian.PutUint16(head[n:], uint16(len(f.err)))
+		n += 2
+	}
+	if _, err := w.Write(head[:n]); err != nil {
+		return err
+	}
+	switch f.typ {
+	case frameRequest:
+		if _, err := w.WriteString(f.method); err != nil {
+			return err
+		}
+	case frameResponse:
+		if _, err := w.WriteString(f.err); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(f.payload)
+	return err
+}
+
+func readFrame(r *bufio.Reader) (*frame, error) {
+	var head [4]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(head[:])
+	if size < 5 || size > MaxFrameSize {
+		return nil, fmt.Errorf("stream: invalid frame size %d", size)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+
+	f := &frame{typ: buf[0], id: binary.BigEndian.Uint32(buf[1:])}
+	buf = buf[5:]
+	switch f.typ {
+	case frameRequest:
+		if len(buf) < 5 || len(buf) < 5+int(buf[4]) {
+			return nil, errors.New("stream: invalid request frame")
+		}
+		f.timeout = binary.BigEndian.Uint32(buf)
+		n := int(buf[4])
+		f.method = string(buf[5 : 5+n])
+		buf = buf[5+n:]
+	case frameResponse:
+		if len(buf) < 2 || len(buf) < 2+int(binary.BigEndian.Uint16(buf)) {
+			return nil, errors.New("stream: invalid response frame")
+		}
+		n := int(binary.BigEndian.Uint16(buf))
+		f.err = string(buf[2 : 2+n])
+		buf = buf[2+n:]
+	}
+	f.payload = buf
+	return f, nil
+}
@@ -0,0 +1,240 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Config 流链接配置，在 [common.stream] 中配置
+type Config struct {
+	// Enable 是否开启，关闭时所有调用走 rpcx
+	Enable bool
+	// Window 单个链接的在途请求上限，同时也是对端并发处理上限
+	Window int
+	// PingInterval 心跳间隔
+	PingInterval time.Duration
+	// DeadTimeout 超过该时间未收到对端数据视为断开
+	DeadTimeout time.Duration
+	// DialTimeout 连接超时
+	DialTimeout time.Duration
+}
+
+// BuildConfig 从通用配置中创建，时间单位毫秒
+func BuildConfig() Config {
+	c := Config{
+		Window:       256,
+		PingInterval: 3 * time.Second,
+		DeadTimeout:  10 * time.Second,
+		DialTimeout:  3 * time.Second,
+	}
+	common := config.Get().Common()
+	if common == nil {
+		return c
+	}
+	c.Enable = common.GetBool("stream.enable")
+	if v := common.GetInt("stream.window"); v > 0 {
+		c.Window = v
+	}
+	if v := common.GetInt64("stream.pinginterval"); v > 0 {
+		c.PingInterval = time.Duration(v) * time.Millisecond
+	}
+	if v := common.GetInt64("stream.deadtimeout"); v > 0 {
+		c.DeadTimeout = time.Duration(v) * time.Millisecond
+	}
+	if v := common.GetInt64("stream.dialtimeout"); v > 0 {
+		c.DialTimeout = time.Duration(v) * time.Millisecond
+	}
+	return c
+}
+
+// Hub 本节点与其它节点之间的所有流链接，对端节点 id-链接
+//
+// 网关主动连接节点，节点接受连接；建立后双方都可以通过链接发起请求
+type Hub struct {
+	id     uint32 // 本节点 id
+	rcvr   any    // 处理对端请求的服务对象
+	config Config
+
+	mu       sync.RWMutex
+	links    map[uint32]*Link
+	dialing  map[uint32]bool
+	listener net.Listener
+	onClose  []func(id uint32, err error)
+}
+
+// NewHub 创建
+//
+//   - id: 本节点 id，连接时告知对端
+//   - rcvr: 处理对端请求的服务对象，例如 Forward, Gate
+func NewHub(id uint32, rcvr any, config Config) *Hub {
+	return &Hub{
+		id:      id,
+		rcvr:    rcvr,
+		config:  config,
+		links:   make(map[uint32]*Link),
+		dialing: make(map[uint32]bool),
+	}
+}
+
+// OnClose 链接断开时回调，用于立即清理与对端相关的状态
+func (h *Hub) OnClose(fn func(id uint32, err error)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onClose = append(h.onClose, fn)
+}
+
+// Get 与对端节点的链接，没有时返回 nil
+func (h *Hub) Get(id uint32) *Link {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.links[id]
+}
+
+// Listen 监听其它节点的连接，返回实际监听地址
+func (h *Hub) Listen(address string) (string, error) {
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		return "", err
+	}
+	h.mu.Lock()
+	h.listener = ln
+	h.mu.Unlock()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					log2.Get().Error("[stream] accept failed", zap.Error(err))
+				}
+				return
+			}
+			go h.accept(conn)
+		}
+	}()
+	return ln.Addr().String(), nil
+}
+
+// Dial 异步连接对端节点，已连接或正在连接时忽略
+func (h *Hub) Dial(id uint32, address string) {
+	h.mu.Lock()
+	if h.links[id] != nil || h.dialing[id] {
+		h.mu.Unlock()
+		return
+	}
+	h.dialing[id] = true
+	h.mu.Unlock()
+
+	go func() {
+		defer func() {
+			h.mu.Lock()
+			delete(h.dialing, id)
+			h.mu.Unlock()
+		}()
+
+		conn, err := net.DialTimeout("tcp", address, h.config.DialTimeout)
+		if err != nil {
+			log2.Get().Warn("[stream] dial failed", zap.Uint32("id", id), zap.String("address", address), zap.Error(err))
+			return
+		}
+		if err = writeHello(conn, h.id); err != nil {
+			_ = conn.Close()
+			return
+		}
+		h.add(id, conn)
+	}()
+}
+
+// Close 关闭监听和所有链接
+func (h *Hub) Close() {
+	h.mu.Lock()
+	if h.listener != nil {
+		_ = h.listener.Close()
+	}
+	links := make([]*Link, 0, len(h.links))
+	for _, l := range h.links {
+		links = append(links, l)
+	}
+	h.mu.Unlock()
+
+	for _, l := range links {
+		_ = l.Close()
+	}
+}
+
+func (h *Hub) accept(conn net.Conn) {
+	_ = conn.SetReadDeadline(time.Now().Add(h.config.DialTimeout))
+	id, err := readHello(conn)
+	if err != nil {
+		log2.Get().Warn("[stream] read hello failed", zap.String("remote", conn.RemoteAddr().String()), zap.Error(err))
+		_ = conn.Close()
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+	h.add(id, conn)
+}
+
+// add 登记链接，同一对端重复连接时替换旧链接
+func (h *Hub) add(id uint32, conn net.Conn) {
+	l := newLink(id, conn, h.rcvr, h.config, h.remove)
+
+	h.mu.Lock()
+	old := h.links[id]
+	h.links[id] = l
+	h.mu.Unlock()
+
+	if old != nil {
+		_ = old.Close()
+	}
+	l.start()
+	log2.Get().Info("[stream] link up", zap.Uint32("id", id), zap.String("remote", conn.RemoteAddr().String()))
+}
+
+func (h *Hub) remove(l *Link, err error) {
+	h.mu.Lock()
+	current := h.links[l.remoteId] == l
+	if current {
+		delete(h.links, l.remoteId)
+	}
+	callbacks := h.onClose
+	h.mu.Unlock()
+
+	if !current { // 已被新链接替换
+		return
+	}
+	log2.Get().Warn("[stream] link down", zap.Uint32("id", l.remoteId), zap.Error(err))
+	for _, fn := range callbacks {
+		fn(l.remoteId, err)
+	}
+}
+
+func writeHello(conn net.Conn, id uint32) error {
+	w := bufio.NewWriter(conn)
+	var payload [4]byte
+	binary.BigEndian.PutUint32(payload[:], id)
+	if err := writeFrame(w, &frame{typ: frameHello, payload: payload[:]}); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func readHello(conn net.Conn) (uint32, error) {
+	// 只读取 hello 帧的长度，避免缓冲区吞掉后续数据
+	var buf [4 + 1 + 4 + 4]byte
+	if _, err := io.ReadFull(conn, buf[:]); err != nil {
+		return 0, err
+	}
+	if binary.BigEndian.Uint32(buf[:]) != 1+4+4 || buf[4] != frameHello {
+		return 0, errors.New("stream: invalid hello")
+	}
+	return binary.BigEndian.Uint32(buf[9:]), nil
+}
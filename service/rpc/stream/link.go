@@ -0,0 +1,315 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/rpc/codec"
+	"net"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	xclient "github.com/smallnest/rpcx/client"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrLinkClosed 链接已断开，请求可能已被对端处理
+	ErrLinkClosed = errors.New("stream: link closed")
+	// ErrLinkTimeout 超过 DeadTimeout 未收到对端任何数据
+	ErrLinkTimeout = errors.New("stream: link timeout")
+	// ErrBusy 对端处理中的请求已满，请求未被处理，计入熔断
+	ErrBusy = errors.New("stream: peer busy")
+
+	typeOfContext = reflect.TypeOf((*context.Context)(nil)).Elem()
+	typeOfError   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Link 两个节点之间的持久链接，双向多路复用请求和响应
+//
+// 流量控制：
+//   - 发送方在途请求数不超过 Window，超出时等待
+//   - 接收方同时处理的请求数不超过 Window，超出时直接返回 ErrBusy，不停止读取，响应帧不受影响
+type Link struct {
+	remoteId uint32
+	conn     net.Conn
+	rcvr     any // 处理对端请求的服务对象，例如 Forward, Gate
+	config   Config
+
+	window   chan struct{} // 发送方在途请求
+	handling chan struct{} // 接收方处理中的请求
+	writeCh  chan *frame
+
+	nextId  atomic.Uint32
+	pending map[uint32]chan *frame
+	mu      sync.Mutex
+
+	lastRecv  atomic.Int64 // 最后收到数据的时间，纳秒
+	closed    chan struct{}
+	closeOnce sync.Once
+	err       error
+	onClose   func(l *Link, err error)
+}
+
+func newLink(remoteId uint32, conn net.Conn, rcvr any, config Config, onClose func(*Link, error)) *Link {
+	l := &Link{
+		remoteId: remoteId,
+		conn:     conn,
+		rcvr:     rcvr,
+		config:   config,
+		window:   make(chan struct{}, config.Window),
+		handling: make(chan struct{}, config.Window),
+		writeCh:  make(chan *frame, config.Window),
+		pending:  make(map[uint32]chan *frame),
+		closed:   make(chan struct{}),
+		onClose:  onClose,
+	}
+	l.lastRecv.Store(time.Now().UnixNano())
+	return l
+}
+
+// RemoteId 对端节点 id
+func (l *Link) RemoteId() uint32 {
+	return l.remoteId
+}
+
+// Done 链接断开时关闭
+func (l *Link) Done() <-chan struct{} {
+	return l.closed
+}
+
+// Call 同步调用对端服务对象的方法，语义与 rpcx 一致，对端返回的错误为 ServiceError
+func (l *Link) Call(ctx context.Context, serviceMethod string, args any, reply any) error {
+	if len(serviceMethod) > 255 {
+		return fmt.Errorf("stream: method name too long: %s", serviceMethod)
+	}
+	// 在途请求上限
+	select {
+	case l.window <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.closed:
+		return ErrLinkClosed
+	}
+	defer func() { <-l.window }()
+
+	payload, err := codec.EnvelopeCodec{}.Encode(args)
+	if err != nil {
+		return err
+	}
+	f := &frame{typ: frameRequest, id: l.nextId.Add(1), method: serviceMethod, payload: payload}
+	if deadline, ok := ctx.Deadline(); ok {
+		f.timeout = uint32(max(time.Until(deadline).Milliseconds(), 1))
+	}
+
+	ch := make(chan *frame, 1)
+	l.mu.Lock()
+	l.pending[f.id] = ch
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		delete(l.pending, f.id)
+		l.mu.Unlock()
+	}()
+
+	if err = l.send(ctx, f); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.err == ErrBusy.Error() {
+			return ErrBusy
+		}
+		if resp.err != "" {
+			return xclient.NewServiceError(resp.err)
+		}
+		if reply == nil {
+			return nil
+		}
+		return codec.EnvelopeCodec{}.Decode(resp.payload, reply)
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.closed:
+		return ErrLinkClosed
+	}
+}
+
+// Close 关闭链接，等待中的请求立即返回 ErrLinkClosed
+func (l *Link) Close() error {
+	l.close(ErrLinkClosed)
+	return nil
+}
+
+// start 启动读写和心跳
+func (l *Link) start() {
+	go l.readLoop()
+	go l.writeLoop()
+	go l.keepalive()
+}
+
+func (l *Link) send(ctx context.Context, f *frame) error {
+	select {
+	case l.writeCh <- f:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.closed:
+		return ErrLinkClosed
+	}
+}
+
+func (l *Link) close(err error) {
+	l.closeOnce.Do(func() {
+		l.err = err
+		close(l.closed)
+		_ = l.conn.Close()
+		if l.onClose != nil {
+			l.onClose(l, err)
+		}
+	})
+}
+
+func (l *Link) readLoop() {
+	r := bufio.NewReaderSize(l.conn, 64*1024)
+	for {
+		f, err := readFrame(r)
+		if err != nil {
+			l.close(err)
+			return
+		}
+		l.lastRecv.Store(time.Now().UnixNano())
+
+		switch f.typ {
+		case frameResponse:
+			l.mu.Lock()
+			ch, ok := l.pending[f.id]
+			l.mu.Unlock()
+			if ok {
+				ch <- f
+			}
+		case frameRequest:
+			// 处理数已满时拒绝，继续读取后续的响应帧
+			select {
+			case l.handling <- struct{}{}:
+				go l.handle(f)
+			default:
+				l.reject(f)
+			}
+		case framePing:
+			select {
+			case l.writeCh <- &frame{typ: framePong}:
+			default: // 写队列已满，队列中的数据同样可以证明存活，不阻塞读取
+			}
+		case framePong:
+		}
+	}
+}
+
+func (l *Link) writeLoop() {
+	w := bufio.NewWriterSize(l.conn, 64*1024)
+	for {
+		select {
+		case f := <-l.writeCh:
+			if err := writeFrame(w, f); err != nil {
+				l.close(err)
+				return
+			}
+			// 没有待写的帧时才刷新，合并小包
+			if len(l.writeCh) == 0 {
+				if err := w.Flush(); err != nil {
+					l.close(err)
+					return
+				}
+			}
+		case <-l.closed:
+			return
+		}
+	}
+}
+
+func (l *Link) keepalive() {
+	ticker := time.NewTicker(l.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if time.Since(time.Unix(0, l.lastRecv.Load())) > l.config.DeadTimeout {
+				l.close(ErrLinkTimeout)
+				return
+			}
+			_ = l.send(context.Background(), &frame{typ: framePing})
+		case <-l.closed:
+			return
+		}
+	}
+}
+
+// handle 处理对端请求，方法签名与 rpcx 服务一致 func(ctx, *Args, *Reply) error
+func (l *Link) handle(f *frame) {
+	defer func() { <-l.handling }()
+
+	resp := &frame{typ: frameResponse, id: f.id}
+	payload, err := l.invoke(f)
+	if err != nil {
+		resp.err = err.Error()
+	} else {
+		resp.payload = payload
+	}
+	if err = l.send(context.Background(), resp); err != nil {
+		log2.Get().Debug("[stream] send response failed", zap.Uint32("remote", l.remoteId), zap.String("method", f.method), zap.Error(err))
+	}
+}
+
+// reject 返回 ErrBusy，写队列已满时在新协程中等待，读取不被阻塞
+func (l *Link) reject(f *frame) {
+	resp := &frame{typ: frameResponse, id: f.id, err: ErrBusy.Error()}
+	select {
+	case l.writeCh <- resp:
+	default:
+		go func() { _ = l.send(context.Background(), resp) }()
+	}
+}
+
+func (l *Link) invoke(f *frame) (payload []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("method: %s panic: %v", f.method, r)
+		}
+	}()
+
+	method := reflect.ValueOf(l.rcvr).MethodByName(f.method)
+	if !method.IsValid() || !validMethod(method.Type()) {
+		return nil, fmt.Errorf("stream: method not found: %T.%s", l.rcvr, f.method)
+	}
+	argv := reflect.New(method.Type().In(1).Elem())
+	if err = (codec.EnvelopeCodec{}).Decode(f.payload, argv.Interface()); err != nil {
+		return nil, err
+	}
+	replyv := reflect.New(method.Type().In(2).Elem())
+
+	ctx := context.Background()
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(f.timeout)*time.Millisecond)
+		defer cancel()
+	}
+	out := method.Call([]reflect.Value{reflect.ValueOf(ctx), argv, replyv})
+	if e, _ := out[0].Interface().(error); e != nil {
+		return nil, e
+	}
+	return codec.EnvelopeCodec{}.Encode(replyv.Interface())
+}
+
+// validMethod func(context.Context, *Args, *Reply) error
+func validMethod(t reflect.Type) bool {
+	return t.NumIn() == 3 && t.NumOut() == 1 &&
+		t.In(0) == typeOfContext &&
+		t.In(1).Kind() == reflect.Ptr && t.In(2).Kind() == reflect.Ptr &&
+		t.Out(0) == typeOfError
+}
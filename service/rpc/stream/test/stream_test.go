@@ -0,0 +1,183 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+	"gameServer/service/rpc/client"
+	"gameServer/service/rpc/client/selector"
+	"gameServer/service/rpc/local"
+	"gameServer/service/rpc/server"
+	"gameServer/service/rpc/stream"
+
+	"github.com/smallnest/rpcx/share"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	gateId = 1
+	nodeId = 1000
+
+	protocolBlock = 1 // 处理时阻塞到 release
+)
+
+func TestMain(m *testing.M) {
+	log2.Init(log2.Config{Level: zapcore.ErrorLevel, IsDocker: true})
+	os.Exit(m.Run())
+}
+
+// Forward 节点服务对象
+type Forward struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (f *Forward) Dispatch(ctx context.Context, req *common.RpcMessage, resp *common.Resp) error {
+	if req.Data.Head.Protocol == protocolBlock {
+		f.entered <- struct{}{}
+		select {
+		case <-f.release:
+		case <-ctx.Done():
+		}
+	}
+	resp.Body = req.Data.Body
+	return nil
+}
+
+// Gate 网关服务对象
+type Gate struct{}
+
+func (g *Gate) Receive(_ context.Context, req *common.RpcMessage, resp *common.Resp) error {
+	resp.Body = req.Data.Body
+	return nil
+}
+
+func message(protocol uint16) common.RpcMessage {
+	return common.RpcMessage{
+		Data:   common.NewMessage(0, 0, 0, protocol, []byte("ping")),
+		Player: &common.Player{UserId: 10086},
+	}
+}
+
+// connect 网关连接节点，节点只允许同时处理一个请求
+func connect(t *testing.T) (*stream.Hub, *stream.Hub, *Forward) {
+	f := &Forward{entered: make(chan struct{}, 4), release: make(chan struct{})}
+	config := stream.Config{Enable: true, Window: 1, PingInterval: time.Second, DeadTimeout: 5 * time.Second, DialTimeout: time.Second}
+	nodeHub := stream.NewHub(nodeId, f, config)
+	address, err := nodeHub.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Window = 4
+	gateHub := stream.NewHub(gateId, &Gate{}, config)
+	t.Cleanup(func() {
+		close(f.release)
+		gateHub.Close()
+		nodeHub.Close()
+	})
+
+	gateHub.Dial(nodeId, address)
+	deadline := time.Now().Add(time.Second)
+	for gateHub.Get(nodeId) == nil || nodeHub.Get(gateId) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("link not established")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return gateHub, nodeHub, f
+}
+
+// 对端处理数已满时立即返回 ErrBusy，链接上的响应帧照常读取
+func TestBusy(t *testing.T) {
+	gateHub, nodeHub, f := connect(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	go func() {
+		_ = gateHub.Get(nodeId).Call(ctx, "Dispatch", message(protocolBlock), &common.Resp{})
+	}()
+	<-f.entered
+
+	start := time.Now()
+	err := gateHub.Get(nodeId).Call(ctx, "Dispatch", message(2), &common.Resp{})
+	if !errors.Is(err, stream.ErrBusy) {
+		t.Fatalf("err = %v, want ErrBusy", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatal("busy reject should not wait")
+	}
+
+	// 节点处理中仍能收到网关的响应
+	resp := &common.Resp{}
+	if err = nodeHub.Get(gateId).Call(ctx, "Receive", message(3), resp); err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Body) != "ping" {
+		t.Fatalf("body = %q", resp.Body)
+	}
+}
+
+// 链接断开时在途请求立即返回，并通知 OnClose
+func TestLinkLost(t *testing.T) {
+	gateHub, nodeHub, f := connect(t)
+	lost := make(chan uint32, 1)
+	gateHub.OnClose(func(id uint32, _ error) { lost <- id })
+
+	done := make(chan error, 1)
+	go func() {
+		done <- gateHub.Get(nodeId).Call(context.Background(), "Dispatch", message(protocolBlock), &common.Resp{})
+	}()
+	<-f.entered
+	nodeHub.Close()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, stream.ErrLinkClosed) {
+			t.Fatalf("err = %v, want ErrLinkClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("in-flight call should return when the link is lost")
+	}
+	select {
+	case id := <-lost:
+		if id != nodeId {
+			t.Fatalf("lost id = %d", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnClose not called")
+	}
+	if gateHub.Get(nodeId) != nil {
+		t.Fatal("lost link should be removed")
+	}
+}
+
+// 通过链接的调用计入 rpc 客户端的熔断统计
+func TestClientBreaker(t *testing.T) {
+	gateHub, _, f := connect(t)
+
+	registry := local.NewRegistry()
+	s, _ := server.NewLocalServer(&server.ServerConfig{ID: nodeId, Name: "room-1", Group: "room"}, registry)
+	_ = s.Register(f)
+	_ = s.Start()
+	defer s.Stop()
+	inner, err := client.NewLocalClient(&client.ClientConfig{ServicePath: "Forward", Selector: selector.NewDefaultSelector(nil)}, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inner.Close()
+
+	c := stream.NewClient(inner, gateHub, nil)
+	ctx := context.WithValue(context.Background(), share.ResMetaDataKey, map[string]string{"id": "1000", "group": "room"})
+	if err = c.Call(ctx, "Dispatch", message(2), &common.Resp{}); err != nil {
+		t.Fatal(err)
+	}
+	stat, ok := inner.Breakers().Stats()[local.AddressPrefix+"room-1"]
+	if !ok || stat.Requests != 1 {
+		t.Fatalf("stat = %+v, want one request recorded", stat)
+	}
+}
@@ -0,0 +1,102 @@
+package gate
+
+import (
+	"context"
+	"gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// canaryRule 某个组的灰度规则，白名单和按比例命中的玩家进入 Version，其余玩家使用低于 Version 的版本
+type canaryRule struct {
+	Version   uint32
+	Percent   uint32 // 0~100
+	Whitelist map[uint64]struct{}
+}
+
+// canaryRules 组名-灰度规则
+type canaryRules struct {
+	mu    sync.RWMutex
+	rules map[string]*canaryRule
+}
+
+var canary = &canaryRules{rules: make(map[string]*canaryRule)}
+
+// loadCanary 读取 [gate.canary.<group>]
+func loadCanary(c *config.Config) {
+	sub := c.Service().Sub("canary")
+	if sub == nil {
+		return
+	}
+
+	canary.mu.Lock()
+	defer canary.mu.Unlock()
+
+	for group := range sub.AllSettings() {
+		rule := &canaryRule{
+			Version:   sub.GetUint32(group + ".version"),
+			Percent:   sub.GetUint32(group + ".percent"),
+			Whitelist: make(map[uint64]struct{}),
+		}
+		for _, userId := range sub.GetIntSlice(group + ".whitelist") {
+			rule.Whitelist[uint64(userId)] = struct{}{}
+		}
+		canary.rules[group] = rule
+		log2.Get().Info("[gate] canary loaded", zap.String("group", group), zap.Uint32("version", rule.Version), zap.Uint32("percent", rule.Percent))
+	}
+}
+
+// versionRange 玩家在该组应使用的版本范围，没有规则时返回 false
+func (c *canaryRules) versionRange(group string, userId uint64) (uint32, uint32, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rule, ok := c.rules[group]
+	if !ok || rule.Version == 0 {
+		return 0, 0, false
+	}
+	if _, ok = rule.Whitelist[userId]; ok || uint32(userId%100) < rule.Percent {
+		return rule.Version, rule.Version, true
+	}
+	return 0, rule.Version - 1, true
+}
+
+// SetCanary 运维调整灰度规则，Remove 为 true 时删除该组规则，已登录玩家保持原版本直到重新登录
+func (g *Gate) SetCanary(_ context.Context, req *common.CanaryRule, _ *common.Resp) error {
+	canary.mu.Lock()
+	defer canary.mu.Unlock()
+
+	if req.Remove {
+		delete(canary.rules, req.Group)
+		log2.Get().Info("[gate] canary removed", zap.String("group", req.Group))
+		return nil
+	}
+
+	rule := &canaryRule{
+		Version:   req.Version,
+		Percent:   req.Percent,
+		Whitelist: make(map[uint64]struct{}, len(req.Whitelist)),
+	}
+	for _, userId := range req.Whitelist {
+		rule.Whitelist[userId] = struct{}{}
+	}
+	canary.rules[req.Group] = rule
+	log2.Get().Info("[gate] canary updated", zap.String("group", req.Group), zap.Uint32("version", req.Version), zap.Uint32("percent", req.Percent))
+	return nil
+}
+
+// pinVersion 会话内固定版本，首次选择后不再随规则变化
+func pinVersion(session *common.Session, group string) (uint32, uint32, bool) {
+	if versionMin, versionMax, ok := session.PinnedVersion(group); ok {
+		return versionMin, versionMax, true
+	}
+	versionMin, versionMax, ok := canary.versionRange(group, session.UserID())
+	if !ok {
+		return 0, 0, false
+	}
+	session.PinVersion(group, versionMin, versionMax)
+	return versionMin, versionMax, true
+}
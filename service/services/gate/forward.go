@@ -2,11 +2,11 @@ package gate
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"gameServer/common/db/affinity"
 	"gameServer/common/errorCode"
-	"gameServer/pkg/cache/ssdb"
 	"gameServer/pkg/logger/log2"
-	"gameServer/pkg/utils"
 	"gameServer/service/common"
 	"gameServer/service/common/proto"
 	"gameServer/service/rpc"
@@ -24,18 +24,34 @@ func (g *Gate) forward(session *common.Session, message *common.Message) *common
 	// 按照协议号 protocol 转发到对应的服务
 	// protocol 范围: 0 ~ 65535
 
+	// 请求期限，随 rpcx 元数据传递到节点，节点和数据库操作超时后都会返回
+	ctx, cancel := context.WithTimeout(context.Background(), g.config.ProtocolTimeout(message.Head.Protocol))
+	defer cancel()
+
 	// 并发控制
 	//1. 生产模式下，只允许心跳验证并发处理
 	//2. 开发模式下，全部都只能串行处理，方便调试
 	if protocol != 1 {
-		session.ReadChan <- struct{}{}
+		select {
+		case session.ReadChan <- struct{}{}:
+		case <-ctx.Done(): // 前一个请求仍未结束
+			return proto.Errorf1(errorCode.ErrorCode_Timeout)
+		}
 		defer func() {
 			<-session.ReadChan
 		}()
 	}
-	// 本地处理: < 1000
-	if protocol < 1000 {
-		return g.forwardLocal(session, message)
+	// 本组协议本地处理
+	group := routeGroup(message.Head.Protocol)
+	if group == "" {
+		return proto.Errorf1(errorCode.ErrorCode_ProtocolNotFound)
+	}
+	if group == g.config.ServiceGroup() {
+		resp := g.forwardLocal(ctx, session, message)
+		if resp != nil && resp.Code != errorCode.ErrorCode_Success && ctx.Err() != nil {
+			resp.Code = errorCode.ErrorCode_Timeout // 失败由超时引起
+		}
+		return resp
 	}
 
 	//转发到其它服务，需要 登录 准备好
@@ -44,10 +60,15 @@ func (g *Gate) forward(session *common.Session, message *common.Message) *common
 	}
 
 	// 远程rpc: 101 ~ 199
-	return g.rpcForward(session, message)
+	resp := g.rpcForward(ctx, session, message)
+	if resp != nil && resp.Code == errorCode.ErrorCode_Moved {
+		// 节点已把该组协议绑定到其它节点，重新转发一次
+		resp = g.rpcForward(ctx, session, message)
+	}
+	return resp
 }
 
-func (g *Gate) forwardLocal(session *common.Session, message *common.Message) *common.Resp {
+func (g *Gate) forwardLocal(ctx context.Context, session *common.Session, message *common.Message) *common.Resp {
 	switch message.Head.Protocol {
 	case 1:
 		return g.heartHandler(session, message)
@@ -57,65 +78,83 @@ func (g *Gate) forwardLocal(session *common.Session, message *common.Message) *c
 	//	return g.secretShareTestHandler(session, message)
 	case 4:
 		fmt.Println("=======4=============")
-		return g.loginHandler(session, message)
+		return g.loginHandler(ctx, session, message)
 	}
 	return proto.Errorf1(errorCode.ErrorCode_ProtocolNotFound)
 }
 
-func (g *Gate) rpcForward(session *common.Session, message *common.Message) *common.Resp {
+func (g *Gate) rpcForward(ctx context.Context, session *common.Session, message *common.Message) *common.Resp {
 	//return g.forwardTarget (session, message, nil)
 	// 根据etcd创建客户端，进行调用
-	return ForwardTarget(session, message, RpcGateClient)
+	return ForwardTarget(ctx, session, message, RpcGateClient)
 }
 
-func ForwardTarget(session *common.Session, message *common.Message, rpcClient rpc.ClientInterface) *common.Resp {
+// roomGroup 房间服务组，玩家可能正在房间中，需要查找房间绑定
+const roomGroup = "room"
+
+// roomServer 玩家房间绑定的节点，节点已下线时返回 0
+func roomServer(ctx context.Context, s *selector.DefaultSelector, userId uint64) int {
+	binding, err := affinity.Find(ctx, userId)
+	if err != nil {
+		log2.Get().Warn("ForwardTarget find room binding failed", zap.Uint64("userId:", userId), zap.Error(err))
+		return 0
+	}
+	if binding == nil {
+		return 0
+	}
+	if s.BoundServer(roomGroup, []uint32{binding.ServerId}) == 0 {
+		return 0
+	}
+	return int(binding.ServerId)
+}
+
+// ForwardTarget 转发到目标节点
+//
+//   - ctx: 带有请求期限，rpcx 会把剩余时间写入元数据，节点的 Dispatch 据此限制处理时间
+func ForwardTarget(ctx context.Context, session *common.Session, message *common.Message, rpcClient rpc.ClientInterface) *common.Resp {
 	start := time.Now().UnixMilli()
 	rpcReq := common.RpcMessage{
 		Data:   message,
-		Player: session.Player,
+		Player: session.PlayerCopy(), // 绑定可能被 rpcx 协程同时修改
 	}
 	var rpcResp = &common.Resp{}
 	var err error
 
-	ctx := context.Background()
-	protocolId := message.Head.Protocol
-	groupId := utils.GetGroupIdByPb(int(protocolId))
-	id := utils.GetServerId(groupId, session.Player.ServerIds) //本网关可能没有
+	s, ok := rpcClient.GetSelector().(*selector.DefaultSelector)
+	if !ok {
+		log2.Get().Error("转换失败")
+		return proto.Errorf1(errorCode.ErrorCode_RemoteCallFailed)
+	}
+
+	group := routeGroup(message.Head.Protocol)
+	id := int(s.BoundServer(group, rpcReq.Player.ServerIds)) //本网关可能没有
 	flag := false
+	if id == 0 && group == roomGroup { //room类型协议，可能正在进行游戏，重连后回到原房间节点
+		id = roomServer(ctx, s, session.Player.UserId)
+		flag = id > 0
+	}
 	if id == 0 {
-		if protocolId >= 1000 && protocolId < 2000 { //room类型协议，可能正在进行游戏
-
-			roleID := strconv.FormatUint(session.Player.UserId, 10)
-			value, err := ssdb.GetClient().Get("RoleID:" + roleID)
-			if err == nil && value.String() != "" {
-				id = value.Int()
-			}
+		metadata := map[string]string{
+			"id":     "0",
+			"group":  group,
+			"userId": strconv.FormatUint(session.Player.UserId, 10),
 		}
-
-		s, ok := rpcClient.GetSelector().(*selector.DefaultSelector)
-		if !ok {
-			log2.Get().Error("转换失败")
-			return proto.Errorf1(errorCode.ErrorCode_RemoteCallFailed)
+		// 灰度版本
+		if versionMin, versionMax, ok := pinVersion(session, group); ok {
+			metadata["versionMin"] = strconv.Itoa(int(versionMin))
+			metadata["versionMax"] = strconv.Itoa(int(versionMax))
 		}
-		ctx = context.WithValue(ctx, share.ResMetaDataKey, map[string]string{
-			"id":      "0",
-			"groupId": strconv.Itoa(int(groupId)),
-		})
-		addr := s.Select(ctx, "", "", nil)
+		ctx = context.WithValue(ctx, share.ResMetaDataKey, metadata)
+		addr := s.Pick(ctx) // 试探名额由下面的调用占用
 		if addr != "" {
-			for _, info := range s.Servers {
-				if info.Address == addr {
-					id = int(info.Id)
-					break
-				}
-			}
+			id = int(s.ServerID(addr))
 		}
 		flag = true
 	}
 
 	ctx = context.WithValue(ctx, share.ResMetaDataKey, map[string]string{
-		"id":      strconv.Itoa(id),
-		"groupId": strconv.Itoa(int(groupId)),
+		"id":    strconv.Itoa(id),
+		"group": group,
 	})
 	// 调用远程的Dispatch方法
 	//start := time.Now() // 记录开始时间
@@ -127,6 +166,15 @@ func ForwardTarget(session *common.Session, message *common.Message, rpcClient r
 	end := time.Now().UnixMilli()
 	fmt.Printf("gate:函数运行时间: %v\n", end-start)
 
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		// 超时不代表节点不可用，保留绑定
+		log2.Get().Warn("ForwardTarget call Dispatch timeout",
+			zap.Uint16("ProtocolId:", rpcReq.Data.Head.Protocol),
+			zap.Uint64("userId:", rpcReq.Player.UserId),
+			zap.Int("serverId:", id),
+		)
+		return proto.Errorf1(errorCode.ErrorCode_Timeout)
+	}
 	if err != nil {
 		log2.Get().Error("ForwardTarget call Dispatch failed",
 			zap.Uint16("ProtocolId:", rpcReq.Data.Head.Protocol),
@@ -134,11 +182,7 @@ func ForwardTarget(session *common.Session, message *common.Message, rpcClient r
 			zap.Error(err),
 		)
 		//todo 如果失败，也更新session,删除id
-		for i := len(session.Player.ServerIds) - 1; i >= 0; i-- {
-			if session.Player.ServerIds[i] == uint32(id) {
-				session.Player.ServerIds = append(session.Player.ServerIds[:i], session.Player.ServerIds[i+1:]...)
-			}
-		}
+		session.RemoveServerId(uint32(id))
 		return proto.Errorf1(errorCode.ErrorCode_RemoteCallFailed)
 	}
 
@@ -156,27 +200,87 @@ func ForwardTarget(session *common.Session, message *common.Message, rpcClient r
 		//}
 
 		// 排除相同的
-		for _, serverId := range session.Player.ServerIds {
-			if serverId == uint32(id) {
-				return rpcResp
-			}
-		}
 		if id == 0 {
 			return rpcResp
 		}
-		session.Player.ServerIds = append(session.Player.ServerIds, uint32(id))
+		session.AddServerId(uint32(id))
 	}
 	return rpcResp
 }
 
+// ReceiveBatch 网关接收其它服务的批量推送，同一消息分发给本网关上的多个玩家
+func (g *Gate) ReceiveBatch(_ context.Context, req *common.RpcBatchMessage, resp *common.BatchResp) error {
+	for _, userId := range req.UserIds {
+		session := g.tcpServer.findSession(userId)
+		if session == nil {
+			addBatchFailed(resp, userId, "session not found")
+			continue
+		}
+		if err := g.tcpServer.write(session, &common.Resp{Body: req.Data.Body}, req.Data); err != nil {
+			addBatchFailed(resp, userId, err.Error())
+		}
+	}
+	if len(resp.Failed) > 0 {
+		log2.Get().Warn("[ReceiveBatch] partial failed", zap.Uint16("protocol", req.Data.Head.Protocol), zap.Any("failed", resp.Failed))
+	}
+	return nil
+}
+
+// ReceiveMulti 网关接收节点异步推送的多条消息，按顺序分发
+func (g *Gate) ReceiveMulti(ctx context.Context, req *common.RpcMultiMessage, resp *common.BatchResp) error {
+	for _, item := range req.Items {
+		if err := g.ReceiveBatch(ctx, item, resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BindServer 节点修改玩家某组协议的目标节点，原来绑定的同组节点被替换
+func (g *Gate) BindServer(_ context.Context, req *common.ServerBind, resp *common.BatchResp) error {
+	s, ok := RpcGateClient.GetSelector().(*selector.DefaultSelector)
+	if !ok {
+		return errors.New("selector is not DefaultSelector")
+	}
+	for _, userId := range req.UserIds {
+		session := g.tcpServer.findSession(userId)
+		if session == nil || session.Player == nil {
+			addBatchFailed(resp, userId, "session not found")
+			continue
+		}
+		// 新节点放在最前面，已下线、选择器不再认识的旧节点也不会被选中
+		session.UpdateServerIds(func(old []uint32) []uint32 {
+			serverIds := []uint32{req.ServerId}
+			for _, id := range old {
+				if id != req.ServerId && s.BoundServer(req.Group, []uint32{id}) == 0 {
+					serverIds = append(serverIds, id)
+				}
+			}
+			return serverIds
+		})
+	}
+	return nil
+}
+
+func addBatchFailed(resp *common.BatchResp, userId uint64, reason string) {
+	if resp.Failed == nil {
+		resp.Failed = make(map[uint64]string)
+	}
+	resp.Failed[userId] = reason
+}
+
 // Receive 网关接收其它服务的单个消息推送,必须实现，不然无法注册，rpcx协程
-func (g *Gate) Receive(_ context.Context, req *common.RpcMessage, resp *common.Resp) error {
+func (g *Gate) Receive(ctx context.Context, req *common.RpcMessage, resp *common.Resp) error {
 	//找到对应的 session，写入消息
 	session := g.tcpServer.findSession(req.Player.UserId)
 	if session == nil {
 		log2.Get().Warn("[Receive] session not found", zap.Uint64("roleID", req.Player.UserId))
 		return fmt.Errorf("session not found, roleID: %d", req.Player.UserId)
 	}
+	// 服务端发起的请求，等待客户端应答后返回
+	if req.Data.Head.Flag&common.MessageFlagServerRequest != 0 {
+		return g.request(ctx, session, req, resp)
+	}
 	// todo 需要修改room 信息?
 	//if req.Data.Head.Protocol == 1010 { //离开room
 	//	groupId := utils.GetGroupIdByPb(int(req.Data.Head.Protocol))
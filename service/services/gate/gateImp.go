@@ -1,14 +1,18 @@
 package gate
 
 import (
+	"gameServer/common/db/cacheChanel"
 	"gameServer/pkg/compress"
 	"gameServer/pkg/config"
 	"gameServer/pkg/logger/log2"
+	"gameServer/pkg/redis"
 	"gameServer/service/rpc"
+	"gameServer/service/rpc/stream"
 	"gameServer/service/services"
 	"gameServer/service/services/gate/datapack"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 
 	rpcxServer "gameServer/service/rpc/server"
@@ -29,16 +33,28 @@ type Gate struct {
 	tcpServer *gNetServer         // 返回给用户
 	rpcServer rpc.ServerInterface //rpc服务器
 
+	topics *topicHub // 主题订阅
+
+	streams *stream.Hub // 与节点的流链接，未开启时为 nil
+
+	sessionSeq atomic.Uint32 // 会话 id 自增序号
+
+	config *config.Config // 本网关的配置，同一进程运行多个服务时与 config.Get() 不同
 }
 
 // New 创建一个网格服务
 func NewServer() (services.ServiceInterface, error) {
-	c := config.Get()
+	return NewServerFrom(config.Get())
+}
 
+// NewServerFrom 使用指定的配置创建，同一进程运行多个服务时使用
+func NewServerFrom(c *config.Config) (*Gate, error) {
 	g := &Gate{
 		id:      c.NodeID(),
 		name:    c.NodeName(),
 		version: c.NodeVersion(),
+		topics:  newTopicHub(),
+		config:  c,
 	}
 	//if err := g.parse(); err != nil {
 	//	return nil, err
@@ -66,7 +82,24 @@ func (g *Gate) Version() uint32 {
 //	return g.initJWT()
 //}
 
+// Start 启动服务，阻塞到收到退出信号
 func (g *Gate) Start() error {
+	if err := g.Serve(); err != nil {
+		return err
+	}
+	g.listenSignal()
+	return nil
+}
+
+// Serve 启动服务后立即返回，由调用方负责 Close
+func (g *Gate) Serve() error {
+	// 协议路由
+	if err := loadRoutes(g.config); err != nil {
+		return err
+	}
+	// 灰度规则
+	loadCanary(g.config)
+
 	// 启动网络监听
 	if err := g.gNetStart(); err != nil {
 		return err
@@ -77,14 +110,19 @@ func (g *Gate) Start() error {
 		return err
 	}
 
+	// 主题推送
+	redis.SubscribeMessage(cacheChanel.TopicChanel, g.onTopicMessage)
+
 	log2.Get().Info("[gate] service started")
-	g.listenSignal()
 	return nil
 }
 
 func (g *Gate) Close() error {
 	// 清理 JWT 资源
 	//g.loginJWT = nil
+	if g.streams != nil {
+		g.streams.Close()
+	}
 	if err := g.rpcServer.Stop(); err != nil {
 		log2.Get().Error("[account] close failed", zap.Error(err))
 		return err
@@ -158,7 +196,7 @@ func (g *Gate) close() {
 //}
 
 func (g *Gate) gNetStart() error {
-	c := config.Get()
+	c := g.config
 
 	var (
 		address = c.Node().GetString("address")
@@ -190,6 +228,10 @@ func (g *Gate) gNetStart() error {
 	}
 
 	g.tcpServer = s
+	// 会话数作为负载上报
+	rpcxServer.SetLoad("sessions", func() int64 {
+		return int64(s.sessionCount.Load())
+	})
 
 	go func() {
 		err := gnet.Run(
@@ -209,9 +251,12 @@ func (g *Gate) gNetStart() error {
 
 func (g *Gate) initRPC() error {
 	// 客户端懒加载
-	SetGateRPCClient(GateRPCClients())
+	if RpcGateClient == nil {
+		SetGateRPCClient(newGateRPCClient(g.config))
+	}
+	g.initStream()
 
-	s, err := rpcxServer.NewServer(rpcxServer.BuildServerConfig())
+	s, err := rpcxServer.New(rpcxServer.BuildServerConfigFrom(g.config))
 	if err != nil {
 		return err
 	}
@@ -1,12 +1,13 @@
 package gate
 
 import (
+	"context"
 	"gameServer/common/db/heros"
 	"gameServer/common/db/items"
 	"gameServer/common/db/reward"
 	"gameServer/common/db/user"
 	"gameServer/common/errorCode"
-	"gameServer/pkg/config"
+	"gameServer/pkg/cache/ssdb"
 	"gameServer/pkg/logger/log2"
 	"gameServer/pkg/loginSdk"
 	"gameServer/pkg/utils"
@@ -14,7 +15,6 @@ import (
 	"gameServer/service/common"
 	"gameServer/service/common/proto"
 	"gameServer/service/services/gate/excelConfig"
-	"slices"
 	"time"
 
 	"go.uber.org/zap"
@@ -40,8 +40,13 @@ func (g *Gate) heartHandler(session *common.Session, message *common.Message) *c
 	//})
 }
 
+// nextSessionId 网关内唯一的会话 id，高 32 位为网关 id
+func (g *Gate) nextSessionId() uint64 {
+	return uint64(g.id)<<32 | uint64(g.sessionSeq.Add(1))
+}
+
 // loginHandler 登录
-func (g *Gate) loginHandler(session *common.Session, message *common.Message) *common.Resp {
+func (g *Gate) loginHandler(ctx context.Context, session *common.Session, message *common.Message) *common.Resp {
 	sum++
 	//fmt.Println("=====%d", message.Head.SN)
 	//fmt.Println("sum=%d", sum)
@@ -81,12 +86,17 @@ func (g *Gate) loginHandler(session *common.Session, message *common.Message) *c
 	}
 
 	// ol 查询
-	err, ol = user.FindOL(*openid, loginType)
+	err, ol = user.FindOL(ctx, *openid, loginType)
 	if err != nil {
 		return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
 	}
 	if ol == nil {
-		userInfo, err = user.CreateUser(*openid, loginType)
+		// 创建账号和初始奖励必须一起完成，期限只在创建前检查
+		ctx, err := ssdb.Detach(ctx)
+		if err != nil {
+			return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
+		}
+		userInfo, err = user.CreateUser(ctx, *openid, loginType)
 		if err != nil {
 			log2.Get().Error("loginHandler AddUserToOL failed ", zap.Any("openid", openid))
 			return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
@@ -99,14 +109,14 @@ func (g *Gate) loginHandler(session *common.Session, message *common.Message) *c
 			return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
 		}
 		for _, id := range idList {
-			ok := reward.SaveRewardInfo(userId, id)
+			ok := reward.SaveRewardInfo(ctx, userId, id)
 			if !ok {
 				log2.Get().Error(" save SaveRewardInfo is false ", zap.Any("idList:", idList))
 				return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
 			}
 		}
 
-		ok := items.RewardItem(userId, rewardMap)
+		ok := items.RewardItem(ctx, userId, rewardMap)
 		if !ok {
 			log2.Get().Error("user loginHandler InitLoginConfig failed ", zap.Any("userId:", userId))
 			return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
@@ -118,14 +128,14 @@ func (g *Gate) loginHandler(session *common.Session, message *common.Message) *c
 
 			return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
 		}
-		ok = heros.UnLockCharacter(userId, characterList)
+		ok = heros.UnLockCharacter(ctx, userId, characterList)
 		if !ok {
 			log2.Get().Error("user loginHandler UnLockCharacter failed ", zap.Any("userId:", userId), zap.Any("characterList:", characterList))
 			return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
 		}
 
 	} else {
-		userInfo, err = user.FindUser(ol.UserId)
+		userInfo, err = user.FindUser(ctx, ol.UserId)
 		if err != nil {
 			log2.Get().Error("loginHandler FindUser failed ", zap.Any("UserId：", ol.UserId))
 			return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
@@ -141,7 +151,7 @@ func (g *Gate) loginHandler(session *common.Session, message *common.Message) *c
 	userId := userInfo.UserId
 
 	// 自身道具
-	allItems, err := items.GetAllItems(userId)
+	allItems, err := items.GetAllItems(ctx, userId)
 	if err != nil {
 		log2.Get().Error("loginHandler GetAllItems failed ", zap.Any("err", err))
 		return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
@@ -156,7 +166,7 @@ func (g *Gate) loginHandler(session *common.Session, message *common.Message) *c
 		i++
 	}
 	// 自身解锁的人物
-	characterList := heros.GetAllUnLockCharacter(userId)
+	characterList := heros.GetAllUnLockCharacter(ctx, userId)
 	heroList = make([]*pbGo.HeroInfo, len(characterList))
 	for ii, info := range characterList {
 		heroList[ii] = &pbGo.HeroInfo{
@@ -170,7 +180,7 @@ func (g *Gate) loginHandler(session *common.Session, message *common.Message) *c
 		log2.Get().Error("loginHandler GetAllReceiveAwardConfig failed ，awardConfig is null")
 		return proto.Errorf1(errorCode.ErrorCode_LoginFailed)
 	}
-	rewardInfo := reward.GetAllRewardInfo(userId)
+	rewardInfo := reward.GetAllRewardInfo(ctx, userId)
 	awardInfoList := make([]*pbGo.AwardInfo, 0)
 	for _, info := range awardConfig {
 		rewardType := info.RewardType
@@ -202,15 +212,27 @@ func (g *Gate) loginHandler(session *common.Session, message *common.Message) *c
 		awardInfoList = append(awardInfoList, one)
 	}
 
-	// 保存会话
+	// 保存会话，玩家上下文随请求传给节点
 	player := common.PlayerPool.Get()
 	player.UserId = userId
+	player.AccountId = *openid
+	player.ServerId = uint32(g.config.ID())
+	player.RealServerId = player.ServerId
+	player.ClientVersion = cliReq.ClientVersion
+	player.Platform = common.Platform(cliReq.LoginType)
+	player.DeviceId = cliReq.DeviceId
+	player.Locale = cliReq.Locale
+	player.GateId = g.id
+	player.SessionId = g.nextSessionId()
 	session.Player = player
+	log2.Get().Info("loginHandler login", player.LogFields()...)
 	g.tcpServer.roles.Store(session.Player.UserId, session)
 	// 保存网关节点
-	if !slices.Contains(session.Player.ServerIds, config.Get().NodeID()) {
-		session.Player.ServerIds = append(session.Player.ServerIds, config.Get().NodeID())
-	}
+	session.AddServerId(g.config.NodeID())
+	// 订阅全服、区服和个人主题
+	g.topics.subscribe(common.TopicGlobal, userId)
+	g.topics.subscribe(common.RegionTopic(session.RealServerID()), userId)
+	g.topics.subscribe(common.UserTopic(userId), userId)
 
 	return proto.Response1(&pbGo.LoginResp{
 		AwardInfoList: awardInfoList,
@@ -0,0 +1,73 @@
+package gate
+
+import (
+	"context"
+	"errors"
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultResendInterval 未设置超时时间时，服务端请求的重发间隔
+const DefaultResendInterval = 2 * time.Second
+
+var (
+	// ErrRequestNoReply 重发次数用完仍未收到客户端应答
+	ErrRequestNoReply = errors.New("server request no reply")
+
+	// ErrSessionClosed 等待应答期间会话关闭
+	ErrSessionClosed = errors.New("session closed")
+)
+
+// request 服务端发起的请求，分配服务端 SN 后发送给客户端，未应答时按间隔重发同一 SN，
+// 客户端按 SN 去重；应答的错误码和内容写入 resp，经 rpcx 返回给发起请求的节点
+func (g *Gate) request(ctx context.Context, session *common.Session, req *common.RpcMessage, resp *common.Resp) error {
+	sn := session.NextServerSN()
+	replyCh := session.AddPending(sn)
+	defer session.RemovePending(sn)
+
+	message := common.NewMessage(req.Data.Head.Flag|common.MessageFlagServerRequest, sn, 0, req.Data.Head.Protocol, req.Data.Body)
+	defer common.FreeMessage(message)
+
+	attempts := int(req.Retries) + 1
+	interval := resendInterval(ctx, attempts)
+	for i := 0; i < attempts; i++ {
+		if err := g.tcpServer.writeMessage(session, message); err != nil {
+			return err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case reply, ok := <-replyCh:
+			timer.Stop()
+			if !ok {
+				return ErrSessionClosed
+			}
+			resp.Code = reply.Head.Code
+			resp.Body = reply.Body
+			return nil
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+			log2.Get().Debug("[gate.request] no reply, resend", zap.Uint64("userId", req.Player.UserId), zap.Uint32("sn", sn), zap.Int("attempt", i+1))
+		}
+	}
+
+	return ErrRequestNoReply
+}
+
+// resendInterval 按剩余时间平分每次发送的等待时长
+func resendInterval(ctx context.Context, attempts int) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return DefaultResendInterval
+	}
+	interval := time.Until(deadline) / time.Duration(attempts)
+	if interval <= 0 {
+		return time.Millisecond
+	}
+	return interval
+}
@@ -0,0 +1,49 @@
+package gate
+
+import (
+	"fmt"
+	"gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// protocolRoute 协议号范围到服务组的映射，见 [[gate.route]]
+type protocolRoute struct {
+	Min   uint16 `mapstructure:"min"`
+	Max   uint16 `mapstructure:"max"`
+	Group string `mapstructure:"group"`
+}
+
+// routes 按 Min 升序，启动时加载
+var routes []protocolRoute
+
+// loadRoutes 加载协议路由表，范围重叠时报错
+func loadRoutes(c *config.Config) error {
+	var list []protocolRoute
+	if err := c.Service().UnmarshalKey("route", &list); err != nil {
+		return err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Min < list[j].Min })
+	for i, r := range list {
+		if r.Min > r.Max || r.Group == "" {
+			return fmt.Errorf("invalid route: %d~%d %q", r.Min, r.Max, r.Group)
+		}
+		if i > 0 && list[i-1].Max >= r.Min {
+			return fmt.Errorf("route overlap: %d~%d %s, %d~%d %s", list[i-1].Min, list[i-1].Max, list[i-1].Group, r.Min, r.Max, r.Group)
+		}
+		log2.Get().Info("[gate] route loaded", zap.Uint16("min", r.Min), zap.Uint16("max", r.Max), zap.String("group", r.Group))
+	}
+	routes = list
+	return nil
+}
+
+// routeGroup 协议所属服务组，未配置时返回空
+func routeGroup(protocol uint16) string {
+	i := sort.Search(len(routes), func(i int) bool { return routes[i].Max >= protocol })
+	if i < len(routes) && routes[i].Min <= protocol {
+		return routes[i].Group
+	}
+	return ""
+}
@@ -1,6 +1,7 @@
 package gate
 
 import (
+	"gameServer/pkg/config"
 	"gameServer/service/rpc"
 	"gameServer/service/rpc/client"
 	"gameServer/service/rpc/client/selector"
@@ -22,19 +23,25 @@ var GateRPCClients = sync.OnceValue(func() rpc.ClientInterface {
 	if RpcGateClient != nil {
 		return RpcGateClient
 	}
+	return newGateRPCClient(config.Get())
+})
 
-	defaultSelector := selector.DefaultSelector{}
+// newGateRPCClient 按网关配置创建到节点的客户端
+func newGateRPCClient(c *config.Config) rpc.ClientInterface {
+	// 各组的选择策略，见 [gate.selector]
+	defaultSelector := selector.NewDefaultSelector(c.Service().GetStringMapString("selector"))
 	// node
-	c, err := client.NewClient(client.BuildClientConfig(
+	rpcClient, err := client.New(client.BuildClientConfigFrom(
+		c,
 		"node",
 		"Forward",
 		100,
 		xclient.Failfast,     // account 节点具有唯一性，不需要重复尝试
 		xclient.SelectByUser, // 使用自定义选择器
-		&defaultSelector,
+		defaultSelector,
 	))
 	if err != nil {
 		panic(err)
 	}
-	return c
-})
+	return rpcClient
+}
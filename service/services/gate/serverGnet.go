@@ -219,6 +219,7 @@ func (ts *gNetServer) delete(address string) {
 	session := sessionI.(*common.Session)
 	if session.Player != nil && session.Player.UserId > 0 {
 		ts.roles.Delete(session.Player.UserId)
+		ts.gate.topics.unsubscribeAll(session.Player.UserId)
 	}
 	ts.sessions.Delete(address)
 }
@@ -249,6 +250,14 @@ func (ts *gNetServer) handleMessage(session *common.Session, req *common.Message
 	//	)
 	//}
 
+	// 客户端对服务端请求的应答，不走转发，也不回包
+	if req.Head.Flag&common.MessageFlagServerReply != 0 {
+		if !session.DeliverReply(req) {
+			log2.Get().Debug("[gate.handleMessage] reply discarded", zap.Uint64("userId", session.UserID()), zap.Uint32("sn", req.Head.SN))
+		}
+		return
+	}
+
 	// 返回结果
 	resp := ts.gate.forward(session, req)
 	//time.Sleep(100 * time.Millisecond)
@@ -263,6 +272,11 @@ func (ts *gNetServer) write(session *common.Session, resp *common.Resp, req *com
 	respMessage := common.NewMessageResp(resp, req)
 	defer common.FreeMessage(respMessage)
 
+	return ts.writeMessage(session, respMessage)
+}
+
+// writeMessage 写入组装好的消息，发送到客户端
+func (ts *gNetServer) writeMessage(session *common.Session, respMessage *common.Message) error {
 	// 分享秘钥时不加密，也不验证校验值
 	//if protocol == pb_protocol.MessageID_SecretSharePubKey {
 	//	resp.Head.Flag = resp.Head.Flag &^ datapack.MessageFlagEncrypt //按位清除
@@ -0,0 +1,44 @@
+package gate
+
+import (
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+	"gameServer/service/rpc/client/selector"
+	"gameServer/service/rpc/stream"
+
+	"go.uber.org/zap"
+)
+
+// initStream 开启流链接时，转发到节点优先走链接，按选择器中节点的 stream 元数据连接
+func (g *Gate) initStream() {
+	cfg := stream.BuildConfig()
+	if !cfg.Enable {
+		return
+	}
+	s, ok := RpcGateClient.GetSelector().(*selector.DefaultSelector)
+	if !ok {
+		log2.Get().Error("[gate] stream needs DefaultSelector")
+		return
+	}
+	hub := stream.NewHub(g.id, g, cfg)
+	hub.OnClose(g.onNodeLost)
+
+	SetGateRPCClient(stream.NewClient(RpcGateClient, hub, s.StreamAddress))
+	g.streams = hub
+}
+
+// onNodeLost 节点链接断开，立即解除玩家与该节点的绑定，后续请求重新选择节点
+func (g *Gate) onNodeLost(id uint32, err error) {
+	n := 0
+	g.tcpServer.sessions.Range(func(_, value any) bool {
+		session := value.(*common.Session)
+		if session.Player == nil {
+			return true
+		}
+		if session.RemoveServerId(id) {
+			n++
+		}
+		return true
+	})
+	log2.Get().Warn("[gate] node link lost", zap.Uint32("id", id), zap.Int("sessions", n), zap.Error(err))
+}
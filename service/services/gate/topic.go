@@ -0,0 +1,118 @@
+package gate
+
+import (
+	"context"
+	"encoding/json"
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// topicHub 本网关的主题订阅关系，按 userId 记录，推送时再查找 session
+type topicHub struct {
+	mu     sync.RWMutex
+	topics map[string]map[uint64]struct{} // 主题-订阅的玩家
+	users  map[uint64]map[string]struct{} // 玩家-已订阅的主题，用于断线清理
+}
+
+func newTopicHub() *topicHub {
+	return &topicHub{
+		topics: make(map[string]map[uint64]struct{}),
+		users:  make(map[uint64]map[string]struct{}),
+	}
+}
+
+func (h *topicHub) subscribe(topic string, userId uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[uint64]struct{})
+	}
+	h.topics[topic][userId] = struct{}{}
+	if h.users[userId] == nil {
+		h.users[userId] = make(map[string]struct{})
+	}
+	h.users[userId][topic] = struct{}{}
+}
+
+func (h *topicHub) unsubscribe(topic string, userId uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(topic, userId)
+	delete(h.users[userId], topic)
+	if len(h.users[userId]) == 0 {
+		delete(h.users, userId)
+	}
+}
+
+// unsubscribeAll 玩家断线时清除所有订阅
+func (h *topicHub) unsubscribeAll(userId uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for topic := range h.users[userId] {
+		h.remove(topic, userId)
+	}
+	delete(h.users, userId)
+}
+
+func (h *topicHub) remove(topic string, userId uint64) {
+	delete(h.topics[topic], userId)
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+// subscribers 复制一份订阅者，避免推送时持锁
+func (h *topicHub) subscribers(topic string) []uint64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	userIds := make([]uint64, 0, len(h.topics[topic]))
+	for userId := range h.topics[topic] {
+		userIds = append(userIds, userId)
+	}
+	return userIds
+}
+
+// Subscribe 节点为本网关上的玩家订阅主题
+func (g *Gate) Subscribe(_ context.Context, req *common.TopicSubscribe, _ *common.Resp) error {
+	for _, userId := range req.UserIds {
+		g.topics.subscribe(req.Topic, userId)
+	}
+	return nil
+}
+
+// Unsubscribe 节点为本网关上的玩家取消订阅
+func (g *Gate) Unsubscribe(_ context.Context, req *common.TopicSubscribe, _ *common.Resp) error {
+	for _, userId := range req.UserIds {
+		g.topics.unsubscribe(req.Topic, userId)
+	}
+	return nil
+}
+
+// Publish 推送给本网关上订阅该主题的所有玩家，由节点 rpcx 广播调用
+func (g *Gate) Publish(ctx context.Context, req *common.TopicMessage, resp *common.BatchResp) error {
+	userIds := g.topics.subscribers(req.Topic)
+	if len(userIds) == 0 {
+		return nil
+	}
+	message := common.NewMessage(0, 0, 0, req.Protocol, req.Body)
+	defer common.FreeMessage(message)
+
+	return g.ReceiveBatch(ctx, &common.RpcBatchMessage{Data: message, UserIds: userIds}, resp)
+}
+
+// onTopicMessage redis 主题消息
+func (g *Gate) onTopicMessage(payload string) {
+	req := &common.TopicMessage{}
+	if err := json.Unmarshal([]byte(payload), req); err != nil {
+		log2.Get().Warn("[gate.onTopicMessage] unmarshal failed", zap.Error(err))
+		return
+	}
+	_ = g.Publish(context.Background(), req, &common.BatchResp{})
+}
@@ -0,0 +1,28 @@
+package node
+
+import (
+	"context"
+	"gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+
+	"github.com/smallnest/rpcx/share"
+	"go.uber.org/zap"
+)
+
+// BindServer 通知玩家所在网关，该组协议以后转发到 serverId 节点
+func BindServer(players []*common.Player, group string, serverId uint32) {
+	if config.Get().IsTest() || len(players) == 0 {
+		return
+	}
+	for id, userIds := range groupByGate(RpcNodeClient, players, nil) {
+		ctx, cancel := context.WithTimeout(context.Background(), PushTimeout)
+		ctx = context.WithValue(ctx, share.ResMetaDataKey, gateMetadata(id))
+		resp := &common.BatchResp{}
+		err := RpcNodeClient.Call(ctx, "BindServer", &common.ServerBind{Group: group, ServerId: serverId, UserIds: userIds}, resp)
+		cancel()
+		if err != nil || len(resp.Failed) > 0 {
+			log2.Get().Warn("bind server failed", zap.Int("gate", id), zap.String("group", group), zap.Uint32("serverId", serverId), zap.Any("failed", resp.Failed), zap.Error(err))
+		}
+	}
+}
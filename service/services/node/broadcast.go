@@ -0,0 +1,104 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+	"gameServer/service/rpc"
+	"sync"
+
+	"github.com/smallnest/rpcx/share"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Broadcast 推送同一消息给多个玩家，消息只序列化一次，按网关分组后每个网关只调用一次
+//
+// 返回推送失败的玩家，userId-错误
+func Broadcast(players []*common.Player, protoId uint16, message proto.Message) map[uint64]error {
+	failed := ToGateBatch(players, protoId, message, RpcNodeClient)
+	if len(failed) > 0 {
+		log2.Get().Error("broadcast to gate partial failed", zap.Int32("protocol", int32(protoId)), zap.Int("failed", len(failed)), zap.Any("detail", failed))
+	}
+	return failed
+}
+
+// ToGateBatch 批量推送到网关
+func ToGateBatch(players []*common.Player, protocol uint16, message proto.Message, rpcClient rpc.ClientInterface) map[uint64]error {
+	if config.Get().IsTest() || len(players) == 0 {
+		return nil
+	}
+	body, err := proto.Marshal(message)
+	if err != nil {
+		log2.Get().Error("proto marshal failed", zap.Error(err))
+		return allFailed(players, err)
+	}
+
+	failed := make(map[uint64]error)
+	groups := groupByGate(rpcClient, players, failed)
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for id, userIds := range groups {
+		wg.Add(1)
+		go func(id int, userIds []uint64) {
+			defer wg.Done()
+
+			pushMessage := common.NewMessage(0, 0, 0, protocol, body)
+			defer common.FreeMessage(pushMessage)
+
+			ctx, cancel := context.WithTimeout(context.Background(), PushTimeout)
+			defer cancel()
+			ctx = context.WithValue(ctx, share.ResMetaDataKey, gateMetadata(id))
+
+			resp := &common.BatchResp{}
+			err := rpcClient.Call(ctx, "ReceiveBatch", &common.RpcBatchMessage{Data: pushMessage, UserIds: userIds}, resp)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil { // 整个网关失败
+				for _, userId := range userIds {
+					failed[userId] = err
+				}
+				return
+			}
+			for userId, reason := range resp.Failed {
+				failed[userId] = errors.New(reason)
+			}
+		}(id, userIds)
+	}
+	wg.Wait()
+
+	if len(failed) == 0 {
+		return nil
+	}
+	return failed
+}
+
+// groupByGate 按网关分组，找不到网关的玩家记入 failed
+func groupByGate(rpcClient rpc.ClientInterface, players []*common.Player, failed map[uint64]error) map[int][]uint64 {
+	groups := make(map[int][]uint64)
+	for _, player := range players {
+		id := gateId(rpcClient, player) //获取网关id
+		if id == 0 {
+			if failed != nil {
+				failed[player.UserId] = errors.New("server id is 0")
+			}
+			continue
+		}
+		groups[id] = append(groups[id], player.UserId)
+	}
+	return groups
+}
+
+func allFailed(players []*common.Player, err error) map[uint64]error {
+	failed := make(map[uint64]error, len(players))
+	for _, player := range players {
+		failed[player.UserId] = err
+	}
+	return failed
+}
@@ -0,0 +1,316 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+	"gameServer/service/rpc"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/smallnest/rpcx/share"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// DropPolicy 网关队列满时的丢弃策略
+type DropPolicy uint8
+
+const (
+	// DropOldest 丢弃队列中最早的推送，保证最新状态送达
+	DropOldest DropPolicy = iota
+	// DropNewest 丢弃本次推送
+	DropNewest
+)
+
+// DispatcherOption 异步推送配置，对应 [room.push]
+type DispatcherOption struct {
+	QueueSize     int           // 每个网关的队列长度
+	BatchSize     int           // 单次 rpc 最多携带的推送数
+	FlushInterval time.Duration // 未攒满一批时的最长等待
+	DropPolicy    DropPolicy
+}
+
+// DispatcherStats 推送统计，用于观察背压
+type DispatcherStats struct {
+	Enqueued uint64         // 入队数
+	Sent     uint64         // 网关确认的推送数（按玩家计）
+	Dropped  uint64         // 队列满被丢弃数
+	Failed   uint64         // 发送失败数（按玩家计）
+	Queues   map[int]int    // 网关 id-当前队列长度
+	Batches  map[int]uint64 // 网关 id-已发送批次数
+}
+
+// pushItem 一条待发送的推送，消息体已序列化
+type pushItem struct {
+	protocol uint16
+	body     []byte
+	userIds  []uint64
+}
+
+// Dispatcher 节点级异步推送分发器
+//
+// 每个目标网关一个有界队列和一个发送协程，调用方只负责入队，不会因网络阻塞
+type Dispatcher struct {
+	opt       DispatcherOption
+	rpcClient func() rpc.ClientInterface // 每次使用时读取，节点启动时会替换客户端
+
+	mu     sync.Mutex
+	queues map[int]*gateQueue
+	closed bool
+	wg     sync.WaitGroup
+
+	enqueued atomic.Uint64
+	sent     atomic.Uint64
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+}
+
+type gateQueue struct {
+	id      int
+	ch      chan *pushItem
+	batches atomic.Uint64
+}
+
+// NewDispatcher 创建分发器，rpcClient 在每次分组和发送时调用
+func NewDispatcher(opt DispatcherOption, rpcClient func() rpc.ClientInterface) *Dispatcher {
+	if opt.QueueSize <= 0 {
+		opt.QueueSize = 1024
+	}
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = 64
+	}
+	if opt.FlushInterval <= 0 {
+		opt.FlushInterval = 5 * time.Millisecond
+	}
+	return &Dispatcher{
+		opt:       opt,
+		rpcClient: rpcClient,
+		queues:    make(map[int]*gateQueue),
+	}
+}
+
+// BuildDispatcherOption 从配置读取，未配置的项使用默认值
+func BuildDispatcherOption() DispatcherOption {
+	opt := DispatcherOption{}
+	service := config.Get().Service()
+	if service == nil {
+		return opt
+	}
+	opt.QueueSize = service.GetInt("push.queuesize")
+	opt.BatchSize = service.GetInt("push.batchsize")
+	opt.FlushInterval = time.Duration(service.GetInt64("push.flushinterval")) * time.Millisecond
+	if service.GetString("push.droppolicy") == "newest" {
+		opt.DropPolicy = DropNewest
+	}
+	return opt
+}
+
+var defaultDispatcher = sync.OnceValue(func() *Dispatcher {
+	return NewDispatcher(BuildDispatcherOption(), func() rpc.ClientInterface { return RpcNodeClient })
+})
+
+// PushAsync 异步推送给单个玩家，立即返回
+func PushAsync(player *common.Player, protoId uint16, message proto.Message) {
+	BroadcastAsync([]*common.Player{player}, protoId, message)
+}
+
+// BroadcastAsync 异步推送给多个玩家，消息只序列化一次，立即返回
+func BroadcastAsync(players []*common.Player, protoId uint16, message proto.Message) {
+	if config.Get().IsTest() || len(players) == 0 {
+		return
+	}
+	defaultDispatcher().Broadcast(players, protoId, message)
+}
+
+// PushStats 默认分发器的统计
+func PushStats() DispatcherStats {
+	return defaultDispatcher().Stats()
+}
+
+// Broadcast 序列化后按网关分组入队
+func (d *Dispatcher) Broadcast(players []*common.Player, protoId uint16, message proto.Message) {
+	body, err := proto.Marshal(message)
+	if err != nil {
+		log2.Get().Error("proto marshal failed", zap.Error(err))
+		return
+	}
+
+	rpcClient := d.rpcClient()
+	if rpcClient == nil { // rpc 还未启动
+		n := d.failed.Add(uint64(len(players)))
+		log2.Get().Warn("push before rpc client ready, dropped", zap.Int32("protocol", int32(protoId)), zap.Uint64("totalFailed", n))
+		return
+	}
+	failed := make(map[uint64]error)
+	groups := groupByGate(rpcClient, players, failed)
+	d.failed.Add(uint64(len(failed)))
+
+	for id, userIds := range groups {
+		d.enqueue(id, &pushItem{protocol: protoId, body: body, userIds: userIds})
+	}
+}
+
+// Stats 当前统计
+func (d *Dispatcher) Stats() DispatcherStats {
+	stats := DispatcherStats{
+		Enqueued: d.enqueued.Load(),
+		Sent:     d.sent.Load(),
+		Dropped:  d.dropped.Load(),
+		Failed:   d.failed.Load(),
+		Queues:   make(map[int]int),
+		Batches:  make(map[int]uint64),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for id, q := range d.queues {
+		stats.Queues[id] = len(q.ch)
+		stats.Batches[id] = q.batches.Load()
+	}
+	return stats
+}
+
+// Close 停止入队，等待队列中的推送发送完毕
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	for _, q := range d.queues {
+		close(q.ch)
+	}
+	d.mu.Unlock()
+
+	d.wg.Wait()
+}
+
+// -------------------------------------- 内部 --------------------------------------
+
+// queue 取网关队列，不存在时创建，调用方持有 mu
+func (d *Dispatcher) queue(id int) *gateQueue {
+	q, ok := d.queues[id]
+	if !ok {
+		q = &gateQueue{id: id, ch: make(chan *pushItem, d.opt.QueueSize)}
+		d.queues[id] = q
+		d.wg.Add(1)
+		go d.run(q)
+	}
+	return q
+}
+
+// enqueue 非阻塞入队，队列满时按策略丢弃
+//
+// 检查 closed 和写入队列都在 mu 内完成，Close 关闭队列时不会有写入
+func (d *Dispatcher) enqueue(id int, item *pushItem) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		d.dropped.Add(uint64(len(item.userIds)))
+		return
+	}
+	q := d.queue(id)
+
+	for {
+		select {
+		case q.ch <- item:
+			d.enqueued.Add(1)
+			return
+		default:
+		}
+
+		if d.opt.DropPolicy == DropNewest {
+			d.drop(q, item)
+			return
+		}
+		// 丢弃最早的一条后重试
+		select {
+		case old := <-q.ch:
+			d.drop(q, old)
+		default:
+		}
+	}
+}
+
+func (d *Dispatcher) drop(q *gateQueue, item *pushItem) {
+	n := d.dropped.Add(uint64(len(item.userIds)))
+	if n%100 == 1 { // 避免刷屏
+		log2.Get().Warn("push queue full, dropped", zap.Int("gate", q.id), zap.Int32("protocol", int32(item.protocol)), zap.Uint64("totalDropped", n))
+	}
+}
+
+// run 网关发送协程，攒批后一次 rpc 发送
+func (d *Dispatcher) run(q *gateQueue) {
+	defer d.wg.Done()
+
+	batch := make([]*pushItem, 0, d.opt.BatchSize)
+	timer := time.NewTimer(d.opt.FlushInterval)
+	defer timer.Stop()
+
+	for {
+		item, ok := <-q.ch
+		if !ok {
+			return
+		}
+		batch = append(batch[:0], item)
+
+		timer.Reset(d.opt.FlushInterval)
+	collect:
+		for len(batch) < d.opt.BatchSize {
+			select {
+			case item, ok = <-q.ch:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, item)
+			case <-timer.C:
+				break collect
+			}
+		}
+
+		d.send(q, batch)
+		if !ok {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) send(q *gateQueue, batch []*pushItem) {
+	req := &common.RpcMultiMessage{Items: make([]*common.RpcBatchMessage, 0, len(batch))}
+	total := 0
+	for _, item := range batch {
+		req.Items = append(req.Items, &common.RpcBatchMessage{
+			Data:    &common.Message{Head: &common.MessageHead{Len: uint16(len(item.body)), Protocol: item.protocol}, Body: item.body},
+			UserIds: item.userIds,
+		})
+		total += len(item.userIds)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), PushTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, share.ResMetaDataKey, gateMetadata(q.id))
+
+	resp := &common.BatchResp{}
+	err := d.rpcClient().Call(ctx, "ReceiveMulti", req, resp)
+	q.batches.Add(1)
+	if err == nil && len(resp.Failed) > 0 {
+		err = errors.New("partial failed")
+	}
+	if err != nil {
+		failed := total
+		if len(resp.Failed) > 0 {
+			failed = len(resp.Failed)
+		}
+		d.failed.Add(uint64(failed))
+		d.sent.Add(uint64(total - failed))
+		log2.Get().Warn("async push to gate failed", zap.Int("gate", q.id), zap.Int("items", len(batch)), zap.Int("failed", failed), zap.Error(err))
+		return
+	}
+	d.sent.Add(uint64(total))
+}
@@ -4,6 +4,7 @@ import (
 	"gameServer/pkg/config"
 	"gameServer/pkg/logger/log2"
 	"gameServer/service/rpc"
+	"gameServer/service/rpc/stream"
 	"os"
 	"os/signal"
 	"syscall"
@@ -19,16 +20,24 @@ type NodeServer struct {
 	version uint32
 
 	rpcServer rpc.ServerInterface
+	// streams 与网关的流链接，未开启时为 nil
+	streams *stream.Hub
+
+	config *config.Config // 本节点的配置，同一进程运行多个服务时与 config.Get() 不同
 }
 
 // New 创建一个网格服务
 func NewServer() *NodeServer {
-	c := config.Get()
+	return NewServerFrom(config.Get())
+}
 
+// NewServerFrom 使用指定的配置创建，同一进程运行多个服务时使用
+func NewServerFrom(c *config.Config) *NodeServer {
 	g := &NodeServer{
 		id:      c.NodeID(),
 		name:    c.NodeName(),
 		version: c.NodeVersion(),
+		config:  c,
 	}
 	//if err := g.parse(); err != nil {
 	//	return nil, err
@@ -75,21 +84,38 @@ func (a *NodeServer) Version() uint32 {
 //	return nil
 //}
 
-// Start 启动服务
-func (n *NodeServer) Start(f *rpc.Forward) error {
+// Start 启动服务，阻塞到收到退出信号
+//
+// ready 在 rpc 客户端就绪之后、开始接受请求之前依次调用，用于恢复重启前的状态，
+// 其中可以向网关推送；在此之前 RpcNodeClient 还未设置
+func (n *NodeServer) Start(f *rpc.Forward, ready ...func()) error {
+	if err := n.Serve(f, ready...); err != nil {
+		return err
+	}
+	n.listenSignal()
+	return nil
+}
+
+// Serve 启动服务后立即返回，由调用方负责 Close，ready 同 Start
+func (n *NodeServer) Serve(f *rpc.Forward, ready ...func()) error {
 	// 启动定时器
 	//crontab.Start()
-	err := n.initRPC(f)
+	err := n.initRPC(f, ready)
 	if err != nil {
 		return err
 	}
 	log2.Get().Info("[account] started")
-	n.listenSignal()
 	return nil
 }
 
 // Close 关闭服务
 func (a *NodeServer) Close() error {
+	// 发送完队列中的推送
+	defaultDispatcher().Close()
+	if a.streams != nil {
+		a.streams.Close()
+	}
+
 	if err := a.rpcServer.Stop(); err != nil {
 		log2.Get().Error("[account] close failed", zap.Error(err))
 		return err
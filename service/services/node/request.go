@@ -0,0 +1,95 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/service/common"
+	"gameServer/service/rpc"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultRequestTimeout 服务端请求默认等待客户端应答的时间
+const DefaultRequestTimeout = 10 * time.Second
+
+// ErrRequestTimeout 超时仍未收到客户端应答
+var ErrRequestTimeout = errors.New("client reply timeout")
+
+// RequestOption 服务端请求选项
+type RequestOption struct {
+	// Timeout 等待客户端应答的总时间，为 0 时使用 DefaultRequestTimeout
+	Timeout time.Duration
+	// Retries 未收到应答时网关的重发次数，重发使用同一 SN
+	Retries uint8
+}
+
+// Request 向客户端发起请求并等待应答，如道具使用确认、交易邀请等
+//
+// 请求经玩家所在网关的 Receive 下发，应答由网关直接返回给本节点；
+// 返回客户端应答的错误码，reply 不为 nil 时解析应答内容
+func Request(player *common.Player, protoId uint16, message proto.Message, reply proto.Message, opt RequestOption) (uint16, error) {
+	return ToGateRequest(player, protoId, message, reply, opt, RpcNodeClient)
+}
+
+// RequestAsync 异步发起请求，结果通过 cb 回调，cb 在独立协程中执行
+//
+// 房间等单协程逻辑应在 cb 中把结果投递回自己的协程，不要直接修改状态
+func RequestAsync(player *common.Player, protoId uint16, message proto.Message, reply proto.Message, opt RequestOption, cb func(code uint16, err error)) {
+	go func() {
+		code, err := Request(player, protoId, message, reply, opt)
+		if err != nil {
+			log2.Get().Warn("request to client failed", zap.Uint64("roleID", player.UserId), zap.Int32("protocol", int32(protoId)), zap.Error(err))
+		}
+		if cb != nil {
+			cb(code, err)
+		}
+	}()
+}
+
+// ToGateRequest 经网关向客户端发起请求
+func ToGateRequest(player *common.Player, protocol uint16, message proto.Message, reply proto.Message, opt RequestOption, rpcClient rpc.ClientInterface) (uint16, error) {
+	if config.Get().IsTest() {
+		return 0, nil
+	}
+	body, err := proto.Marshal(message)
+	if err != nil {
+		log2.Get().Error("proto marshal failed", zap.Error(err))
+		return 0, err
+	}
+
+	timeout := opt.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+
+	requestMessage := common.NewMessage(common.MessageFlagServerRequest, 0, 0, protocol, body)
+	defer common.FreeMessage(requestMessage)
+	rpcReq := common.RpcMessage{
+		Data:    requestMessage,
+		Player:  player,
+		Retries: opt.Retries,
+	}
+
+	// 截止时间随 rpcx 传到网关，网关据此计算重发间隔
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resp := &common.Resp{}
+	if err = callGate(ctx, player, rpcReq, resp, rpcClient); err != nil {
+		if ctx.Err() != nil {
+			return 0, ErrRequestTimeout
+		}
+		return 0, err
+	}
+
+	if reply != nil && len(resp.Body) > 0 {
+		if err = proto.Unmarshal(resp.Body, reply); err != nil {
+			return resp.Code, err
+		}
+	}
+	return resp.Code, nil
+}
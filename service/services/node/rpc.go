@@ -5,22 +5,32 @@ import (
 	"errors"
 	"gameServer/pkg/config"
 	"gameServer/pkg/logger/log2"
-	"gameServer/pkg/utils"
 	"gameServer/service/common"
 	"gameServer/service/rpc"
+	"gameServer/service/rpc/client/selector"
 	rpcxServer "gameServer/service/rpc/server"
 	"strconv"
+	"time"
 
 	"github.com/smallnest/rpcx/share"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
 )
 
-func (n *NodeServer) initRPC(f *rpc.Forward) error {
+// PushTimeout 单次推送到网关的最长等待时间，避免网关卡住时阻塞调用方
+const PushTimeout = 3 * time.Second
+
+func (n *NodeServer) initRPC(f *rpc.Forward, ready []func()) error {
 	SetNodeRPCClient(RPCNodeClients())
+	if err := n.initStream(f); err != nil {
+		return err
+	}
+	for _, fn := range ready {
+		fn()
+	}
 
 	// 1. 服务端
-	s, err := rpcxServer.NewServer(rpcxServer.BuildServerConfig())
+	s, err := rpcxServer.New(rpcxServer.BuildServerConfigFrom(n.config))
 	if err != nil {
 		return err
 	}
@@ -71,15 +81,36 @@ func ToGate(player *common.Player, protocol uint16, message proto.Message, rpcCl
 		Player: player,
 	}
 	defer common.FreeMessage(pushMessage)
-	id := utils.GetServerId(1, player.ServerIds) //获取网关id,网格为1组
+	ctx, cancel := context.WithTimeout(context.Background(), PushTimeout)
+	defer cancel()
+
+	return callGate(ctx, player, rpcReq, nil, rpcClient)
+}
+
+// callGate 调用玩家所在网关的 Receive
+func callGate(ctx context.Context, player *common.Player, rpcReq common.RpcMessage, resp *common.Resp, rpcClient rpc.ClientInterface) error {
+	id := gateId(rpcClient, player) //获取网关id
 	if id == 0 {
 		return errors.New("server id is 0")
 	}
-	ctx := context.Background()
-	ctx = context.WithValue(ctx, share.ResMetaDataKey, map[string]string{
-		"id":      strconv.Itoa(id),
-		"groupId": strconv.Itoa(1),
-	})
+	ctx = context.WithValue(ctx, share.ResMetaDataKey, gateMetadata(id))
 
-	return rpcClient.Call(ctx, "Receive", rpcReq, nil)
+	return rpcClient.Call(ctx, "Receive", rpcReq, resp)
+}
+
+// gateId 玩家所在网关 id，按节点元数据中的服务组查找
+func gateId(rpcClient rpc.ClientInterface, player *common.Player) int {
+	s, ok := rpcClient.GetSelector().(*selector.DefaultSelector)
+	if !ok {
+		return 0
+	}
+	return int(s.BoundServer(common.GateGroup, player.ServerIds))
+}
+
+// gateMetadata 指定网关调用
+func gateMetadata(id int) map[string]string {
+	return map[string]string{
+		"id":    strconv.Itoa(id),
+		"group": common.GateGroup,
+	}
 }
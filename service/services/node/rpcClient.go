@@ -24,7 +24,7 @@ var RPCNodeClients = sync.OnceValue(func() rpc.ClientInterface {
 	}
 
 	// gate 集群
-	c, err := client.NewClient(client.BuildClientConfig(
+	c, err := client.New(client.BuildClientConfig(
 		"gate",
 		"Gate",
 		8,
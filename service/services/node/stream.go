@@ -0,0 +1,27 @@
+package node
+
+import (
+	"fmt"
+	utils2 "gameServer/pkg/utils"
+	"gameServer/service/rpc"
+	rpcxServer "gameServer/service/rpc/server"
+	"gameServer/service/rpc/stream"
+)
+
+// initStream 开启流链接时监听网关的连接，地址写入节点元数据，网关连入后推送走链接
+func (n *NodeServer) initStream(f *rpc.Forward) error {
+	cfg := stream.BuildConfig()
+	if !cfg.Enable {
+		return nil
+	}
+	hub := stream.NewHub(n.id, f, cfg)
+	address, err := hub.Listen(fmt.Sprintf("%s:0", utils2.LocalIP()))
+	if err != nil {
+		return err
+	}
+	rpcxServer.SetMetadata("stream", address)
+
+	SetNodeRPCClient(stream.NewClient(RpcNodeClient, hub, nil))
+	n.streams = hub
+	return nil
+}
@@ -0,0 +1,78 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"gameServer/common/db/cacheChanel"
+	"gameServer/pkg/config"
+	"gameServer/pkg/logger/log2"
+	"gameServer/pkg/redis"
+	"gameServer/service/common"
+
+	"github.com/smallnest/rpcx/share"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Subscribe 为玩家订阅主题，如房间类型、公会等自定义主题，玩家断线后网关自动清除
+func Subscribe(players []*common.Player, topic string) {
+	subscribe("Subscribe", players, topic)
+}
+
+// Unsubscribe 为玩家取消订阅主题
+func Unsubscribe(players []*common.Player, topic string) {
+	subscribe("Unsubscribe", players, topic)
+}
+
+// Publish 通过 redis 发布到所有网关，由网关推送给订阅该主题的玩家
+func Publish(topic string, protoId uint16, message proto.Message) error {
+	payload, err := buildTopicMessage(topic, protoId, message)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return redis.PublishMessage(cacheChanel.TopicChanel, string(b))
+}
+
+// PublishRPC 通过 rpcx 广播到所有网关，不依赖 redis
+func PublishRPC(ctx context.Context, topic string, protoId uint16, message proto.Message) error {
+	payload, err := buildTopicMessage(topic, protoId, message)
+	if err != nil {
+		return err
+	}
+	return RpcNodeClient.Broadcast(ctx, "Publish", payload, &common.BatchResp{})
+}
+
+// -------------------------------------- 内部 --------------------------------------
+
+func buildTopicMessage(topic string, protoId uint16, message proto.Message) (*common.TopicMessage, error) {
+	body, err := proto.Marshal(message)
+	if err != nil {
+		log2.Get().Error("proto marshal failed", zap.Error(err))
+		return nil, err
+	}
+	return &common.TopicMessage{
+		Topic:    topic,
+		Protocol: protoId,
+		Body:     body,
+	}, nil
+}
+
+// subscribe 按网关分组后调用
+func subscribe(method string, players []*common.Player, topic string) {
+	if config.Get().IsTest() || len(players) == 0 {
+		return
+	}
+	for id, userIds := range groupByGate(RpcNodeClient, players, nil) {
+		ctx, cancel := context.WithTimeout(context.Background(), PushTimeout)
+		ctx = context.WithValue(ctx, share.ResMetaDataKey, gateMetadata(id))
+		err := RpcNodeClient.Call(ctx, method, &common.TopicSubscribe{Topic: topic, UserIds: userIds}, &common.Resp{})
+		cancel()
+		if err != nil {
+			log2.Get().Warn("topic "+method+" failed", zap.Int("gate", id), zap.String("topic", topic), zap.Error(err))
+		}
+	}
+}